@@ -0,0 +1,70 @@
+// Command akamai-api-server runs the in-memory stand-in of the Akamai APIs so terraform configurations can be
+// planned, applied and imported against it without an Akamai account
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/test/apiserver"
+)
+
+func main() {
+	var (
+		listen  string
+		dir     string
+		section string
+		polls   int
+		quiet   bool
+	)
+	flag.StringVar(&listen, "listen", "127.0.0.1:8443", "the address the server listens on")
+	flag.StringVar(&dir, "dir", ".", "the directory the certificate and edgerc files are written to")
+	flag.StringVar(&section, "section", "default", "the section of the generated edgerc file")
+	flag.IntVar(&polls, "pending-polls", apiserver.DefaultPendingPolls, "the number of status reads asynchronous changes stay pending for")
+	flag.BoolVar(&quiet, "quiet", false, "do not log the requests")
+	flag.Parse()
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		log.Fatalf("resolving %s: %s", dir, err)
+	}
+
+	opts := []apiserver.Option{apiserver.WithAddress(listen), apiserver.WithPendingPolls(polls)}
+	if !quiet {
+		opts = append(opts, apiserver.WithLog(os.Stderr))
+	}
+	srv := apiserver.NewServer(opts...)
+	defer srv.Close()
+
+	certPath := filepath.Join(dir, "akamai-api-server.pem")
+	if err := ioutil.WriteFile(certPath, srv.CertificatePEM(), 0644); err != nil {
+		log.Fatalf("writing the certificate: %s", err)
+	}
+	edgercPath := filepath.Join(dir, "akamai-api-server.edgerc")
+	if err := ioutil.WriteFile(edgercPath, []byte(srv.Edgerc(section)), 0600); err != nil {
+		log.Fatalf("writing the edgerc file: %s", err)
+	}
+
+	fmt.Printf(`Akamai API stand-in listening on https://%s
+
+Point terraform at it with, on Linux:
+
+  export SSL_CERT_FILE=%s
+  export EDGERC=%s
+
+Contract: %s
+Group:    %s
+AppSec configuration: %d
+
+Press Ctrl+C to stop, all the objects are lost when the server stops.
+`, srv.Host(), certPath, edgercPath, apiserver.ContractID, apiserver.GroupID, apiserver.AppSecConfigID)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	<-stop
+}
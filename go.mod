@@ -17,6 +17,7 @@ require (
 	github.com/tj/assert v0.0.3
 	github.com/zclconf/go-cty v1.7.1 // indirect
 	golang.org/x/mod v0.4.0 // indirect
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
 	golang.org/x/tools v0.0.0-20201207191902-7bb39e4ca9ac // indirect
 	google.golang.org/api v0.34.0 // indirect
 )
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"os"
 
 	// Load the providers
 	_ "github.com/akamai/terraform-provider-akamai/v2/pkg/providers"
@@ -15,6 +16,11 @@ import (
 )
 
 func main() {
+	// The provider binary is started by terraform, the tools are run by hand with the tools argument
+	if len(os.Args) > 1 && os.Args[1] == "tools" {
+		os.Exit(runTools(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	var debugMode bool
 
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -0,0 +1,368 @@
+package akamai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+type (
+	// AuditRecord is the audit log line written for a mutating API call
+	AuditRecord struct {
+		Time         time.Time         `json:"time"`
+		OperationID  string            `json:"operation_id"`
+		Credentials  string            `json:"credentials,omitempty"`
+		Resource     string            `json:"resource,omitempty"`
+		ResourceType string            `json:"resource_type,omitempty"`
+		ResourceID   string            `json:"resource_id,omitempty"`
+		API          string            `json:"api"`
+		Method       string            `json:"method"`
+		Path         string            `json:"path"`
+		Status       int               `json:"status,omitempty"`
+		Error        string            `json:"error,omitempty"`
+		DurationMS   int64             `json:"duration_ms"`
+		ObjectIDs    map[string]string `json:"object_ids,omitempty"`
+		RequestBody  json.RawMessage   `json:"request_body,omitempty"`
+		ResponseBody json.RawMessage   `json:"response_body,omitempty"`
+	}
+
+	// AuditLog writes one JSON line per mutating API call of a provider configuration
+	AuditLog struct {
+		w             *auditWriter
+		operationID   string
+		credentials   string
+		includeBodies bool
+	}
+
+	auditWriter struct {
+		sync.Mutex
+		out io.Writer
+	}
+
+	// auditFile appends each write to the file at its path, the file is only open during the write so no handle
+	// outlives the provider configuration
+	auditFile string
+
+	auditTransport struct {
+		next  http.RoundTripper
+		audit *AuditLog
+		log   log.Interface
+	}
+
+	auditResource struct {
+		name string
+		d    *schema.ResourceData
+	}
+
+	auditResourceKey struct{}
+)
+
+var (
+	// auditPathIDs maps the path segments followed by an object id to the name the id is recorded with
+	auditPathIDs = map[string]map[string]string{
+		APIFamilyPAPI: {
+			"properties":    "property_id",
+			"versions":      "property_version",
+			"activations":   "activation_id",
+			"cpcodes":       "cpcode_id",
+			"edgehostnames": "edge_hostname_id",
+		},
+		APIFamilyDNS: {
+			"zones":       "zone",
+			"changelists": "zone",
+			"names":       "record_name",
+			"types":       "record_type",
+		},
+		APIFamilyGTM: {
+			"domains":         "domain",
+			"datacenters":     "datacenter_id",
+			"properties":      "property",
+			"resources":       "resource",
+			"geographic-maps": "map",
+			"cidr-maps":       "map",
+			"as-maps":         "map",
+		},
+		APIFamilyAPPSEC: {
+			"configs":           "config_id",
+			"versions":          "config_version",
+			"security-policies": "policy_id",
+			"match-targets":     "match_target_id",
+			"rate-policies":     "rate_policy_id",
+			"custom-rules":      "custom_rule_id",
+			"activations":       "activation_id",
+		},
+		APIFamilyIAM: {
+			"ui-identities": "identity_id",
+		},
+	}
+
+	// auditQueryIDs maps the query parameters holding object ids to the name the id is recorded with
+	auditQueryIDs = map[string]string{
+		"contractId": "contract_id",
+		"groupId":    "group_id",
+		"gid":        "group_id",
+		"propertyId": "property_id",
+		"zone":       "zone",
+	}
+)
+
+// NewAuditLog returns an audit log writing to out
+// Request and response bodies are only recorded with includeBodies, as they may hold sensitive data
+func NewAuditLog(out io.Writer, operationID string, includeBodies bool) *AuditLog {
+	return &AuditLog{
+		w:             &auditWriter{out: out},
+		operationID:   operationID,
+		includeBodies: includeBodies,
+	}
+}
+
+// WithCredentials returns a copy of the audit log whose records name the credential set in use
+func (a *AuditLog) WithCredentials(name string) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	audit := *a
+	audit.credentials = name
+
+	return &audit
+}
+
+// Write appends the record to the log, fields set by the log itself are filled in
+func (a *AuditLog) Write(rec AuditRecord) error {
+	rec.OperationID = a.operationID
+	rec.Credentials = a.credentials
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	a.w.Lock()
+	defer a.w.Unlock()
+
+	// a single write per line keeps lines whole when several providers append to the same file
+	_, err = a.w.out.Write(append(data, '\n'))
+
+	return err
+}
+
+// NewAuditTransport returns a http.RoundTripper which records the mutating requests passed to next
+// It should wrap the retry transport, so a call is recorded once with its final status
+func NewAuditTransport(next http.RoundTripper, audit *AuditLog, log log.Interface) http.RoundTripper {
+	return &auditTransport{
+		next:  next,
+		audit: audit,
+		log:   log,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *auditTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.next.RoundTrip(r)
+	}
+
+	rec := AuditRecord{
+		Time:      time.Now().UTC(),
+		API:       APIFamily(r),
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		ObjectIDs: auditObjectIDs(r),
+	}
+	res, _ := r.Context().Value(auditResourceKey{}).(auditResource)
+	rec.ResourceType = res.name
+
+	if t.audit.includeBodies && r.Body != nil {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		rec.RequestBody = auditBody(body)
+	}
+
+	resp, err := t.next.RoundTrip(r)
+	rec.DurationMS = time.Since(rec.Time).Milliseconds()
+
+	if err != nil {
+		rec.Error = err.Error()
+	} else {
+		rec.Status = resp.StatusCode
+		if t.audit.includeBodies && resp.Body != nil {
+			body, readErr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			if readErr != nil {
+				return resp, readErr
+			}
+			rec.ResponseBody = auditBody(body)
+		}
+	}
+
+	// the resource id is read once the call is done, so it is known for creates too
+	if res.d != nil {
+		rec.ResourceID = res.d.Id()
+		rec.Resource = resourceAddress(res.name, rec.ResourceID)
+	}
+
+	if werr := t.audit.Write(rec); werr != nil {
+		t.log.WithError(werr).Warnf("failed to write the audit record of %s %s", rec.Method, rec.Path)
+	}
+
+	return resp, err
+}
+
+// resourceAddress identifies a resource in the audit records and dry run reports. Providers are not given the name of
+// a resource in the configuration, so the resource is addressed by its type and ID like in a terraform import command.
+func resourceAddress(resourceType, id string) string {
+	if id == "" {
+		return resourceType
+	}
+
+	return resourceType + " " + id
+}
+
+// auditObjectIDs returns the ids of the objects a request applies to, read from its path and query
+func auditObjectIDs(r *http.Request) map[string]string {
+	ids := make(map[string]string)
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if names, ok := auditPathIDs[APIFamily(r)]; ok {
+		for i := 0; i < len(segments)-1; i++ {
+			if name, ok := names[segments[i]]; ok {
+				ids[name] = segments[i+1]
+			}
+		}
+	}
+
+	query := r.URL.Query()
+	for param, name := range auditQueryIDs {
+		if value := query.Get(param); value != "" {
+			ids[name] = value
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return ids
+}
+
+// auditBody returns a body as raw JSON, bodies which are not JSON are recorded as a string
+func auditBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body)
+	}
+	data, _ := json.Marshal(string(body))
+
+	return data
+}
+
+// addAuditResource wraps the resource operations so their API calls are recorded with the resource type and id
+func addAuditResource(name string, r *schema.Resource) {
+	r.CreateContext = withAuditResource(name, r.CreateContext)
+	r.ReadContext = withAuditResource(name, r.ReadContext)
+	r.UpdateContext = withAuditResource(name, r.UpdateContext)
+	r.DeleteContext = withAuditResource(name, r.DeleteContext)
+}
+
+func withAuditResource(name string, f crudFunc) crudFunc {
+	if f == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return f(context.WithValue(ctx, auditResourceKey{}, auditResource{name: name, d: d}), d, m)
+	}
+}
+
+func auditLogSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "JSON lines log of every mutating API call, for change management",
+		Optional:    true,
+		Type:        schema.TypeList,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"path": {
+					Description: "The file the records are appended to",
+					Required:    true,
+					Type:        schema.TypeString,
+				},
+				"include_bodies": {
+					Description: "Whether the request and response bodies are recorded, they may hold sensitive data",
+					Optional:    true,
+					Type:        schema.TypeBool,
+					Default:     false,
+				},
+			},
+		},
+	}
+}
+
+// getAuditLog opens the audit log configured in the audit_log block or with AKAMAI_AUDIT_LOG
+// It returns nil when no audit log is configured
+func getAuditLog(d tools.ResourceDataFetcher, operationID string) (*AuditLog, error) {
+	path := os.Getenv("AKAMAI_AUDIT_LOG")
+	includeBodies := false
+
+	block, err := tools.GetListValue("audit_log", d)
+	if err != nil && !errors.Is(err, tools.ErrNotFound) {
+		return nil, err
+	}
+	if len(block) > 0 && block[0] != nil {
+		m, ok := block[0].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "audit_log", "map[string]interface{}")
+		}
+		path = m["path"].(string)
+		includeBodies = m["include_bodies"].(bool)
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	// an empty write checks the file can be appended to when the provider is configured
+	f := auditFile(path)
+	if _, err := f.Write(nil); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAuditLogInvalid, err)
+	}
+
+	return NewAuditLog(f, operationID, includeBodies), nil
+}
+
+// Write implements the io.Writer interface
+func (f auditFile) Write(p []byte) (int, error) {
+	file, err := os.OpenFile(string(f), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := file.Write(p)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+
+	return n, err
+}
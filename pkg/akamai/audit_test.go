@@ -0,0 +1,150 @@
+package akamai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"propertyLink":"/papi/v1/properties/prp_1"}`))
+	}))
+	defer srv.Close()
+
+	send := func(ctx context.Context, t *testing.T, audit *AuditLog, method, path string) {
+		client := &http.Client{Transport: NewAuditTransport(http.DefaultTransport, audit, Log())}
+		req, err := http.NewRequestWithContext(ctx, method, srv.URL+path, strings.NewReader(`{"propertyName":"test"}`))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "EG1-HMAC-SHA256 client_token=secret")
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"propertyLink":"/papi/v1/properties/prp_1"}`, string(body))
+	}
+
+	records := func(t *testing.T, out *bytes.Buffer) []AuditRecord {
+		var recs []AuditRecord
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var rec AuditRecord
+			require.NoError(t, json.Unmarshal([]byte(line), &rec))
+			recs = append(recs, rec)
+		}
+		return recs
+	}
+
+	t.Run("mutating calls are recorded without bodies", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		audit := NewAuditLog(out, "opid", false)
+
+		send(context.Background(), t, audit, http.MethodGet, "/papi/v1/properties/prp_1?contractId=ctr_1&groupId=grp_2")
+		send(context.Background(), t, audit, http.MethodPost, "/papi/v1/properties/prp_1/activations?contractId=ctr_1&groupId=grp_2")
+
+		recs := records(t, out)
+		require.Len(t, recs, 1)
+		rec := recs[0]
+		assert.Equal(t, "opid", rec.OperationID)
+		assert.Equal(t, APIFamilyPAPI, rec.API)
+		assert.Equal(t, http.MethodPost, rec.Method)
+		assert.Equal(t, "/papi/v1/properties/prp_1/activations", rec.Path)
+		assert.Equal(t, http.StatusCreated, rec.Status)
+		assert.Equal(t, map[string]string{"property_id": "prp_1", "contract_id": "ctr_1", "group_id": "grp_2"}, rec.ObjectIDs)
+		assert.Nil(t, rec.RequestBody)
+		assert.Nil(t, rec.ResponseBody)
+		assert.NotContains(t, out.String(), "secret")
+	})
+
+	t.Run("bodies are recorded when opted in", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		audit := NewAuditLog(out, "opid", true).WithCredentials("staging")
+
+		send(context.Background(), t, audit, http.MethodPut, "/config-dns/v2/zones/example.com/names/www.example.com/types/A")
+
+		recs := records(t, out)
+		require.Len(t, recs, 1)
+		rec := recs[0]
+		assert.Equal(t, "staging", rec.Credentials)
+		assert.Equal(t, map[string]string{"zone": "example.com", "record_name": "www.example.com", "record_type": "A"}, rec.ObjectIDs)
+		assert.JSONEq(t, `{"propertyName":"test"}`, string(rec.RequestBody))
+		assert.JSONEq(t, `{"propertyLink":"/papi/v1/properties/prp_1"}`, string(rec.ResponseBody))
+		assert.NotContains(t, out.String(), "secret")
+	})
+
+	t.Run("resource operations name the resource", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		audit := NewAuditLog(out, "opid", false)
+
+		r := &schema.Resource{
+			Schema: map[string]*schema.Schema{},
+			DeleteContext: func(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+				send(ctx, t, audit, http.MethodDelete, "/appsec/v1/configs/43253/versions/2/security-policies/TEST_1")
+				return nil
+			},
+		}
+		addAuditResource("akamai_appsec_security_policy", r)
+
+		d := r.TestResourceData()
+		d.SetId("43253:TEST_1")
+		require.Nil(t, r.DeleteContext(context.Background(), d, nil))
+
+		recs := records(t, out)
+		require.Len(t, recs, 1)
+		rec := recs[0]
+		assert.Equal(t, "akamai_appsec_security_policy", rec.ResourceType)
+		assert.Equal(t, "43253:TEST_1", rec.ResourceID)
+		assert.Equal(t, "akamai_appsec_security_policy 43253:TEST_1", rec.Resource)
+		assert.Equal(t, APIFamilyAPPSEC, rec.API)
+		assert.Equal(t, map[string]string{"config_id": "43253", "config_version": "2", "policy_id": "TEST_1"}, rec.ObjectIDs)
+	})
+}
+
+func TestAuditFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{"audit_log": auditLogSchema()}, map[string]interface{}{
+		"audit_log": []interface{}{map[string]interface{}{"path": path}},
+	})
+	audit, err := getAuditLog(d, "opid")
+	require.NoError(t, err)
+
+	require.NoError(t, audit.Write(AuditRecord{Method: http.MethodPost}))
+	require.NoError(t, audit.Write(AuditRecord{Method: http.MethodDelete}))
+
+	// the records are appended to the file, which is closed after each of them
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `"method":"DELETE"`)
+	require.NoError(t, os.Remove(path))
+
+	d = schema.TestResourceDataRaw(t, map[string]*schema.Schema{"audit_log": auditLogSchema()}, map[string]interface{}{
+		"audit_log": []interface{}{map[string]interface{}{"path": filepath.Join(dir, "missing", "audit.log")}},
+	})
+	_, err = getAuditLog(d, "opid")
+	assert.True(t, errors.Is(err, ErrAuditLogInvalid))
+}
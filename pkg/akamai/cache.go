@@ -0,0 +1,180 @@
+package akamai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/allegro/bigcache"
+)
+
+const (
+	// CacheTypeMemory is the in-process cache backend, entries live as long as the provider process
+	CacheTypeMemory = "memory"
+
+	// CacheTypeFile is the on-disk cache backend, entries are shared between terraform runs
+	CacheTypeFile = "file"
+
+	// DefaultCacheTTL is the default time to live of a cache entry
+	DefaultCacheTTL = 10 * time.Minute
+
+	// maxMemoryCacheTTL is the life window of the in-memory cache, entries with a longer ttl are evicted earlier
+	maxMemoryCacheTTL = 24 * time.Hour
+)
+
+type (
+	// CacheStore is the interface implemented by the provider cache backends
+	CacheStore interface {
+		// Get returns the data stored for the key or ErrCacheEntryNotFound if it is missing or expired
+		Get(key string) ([]byte, error)
+
+		// Set stores the data for the key, the entry expires after ttl
+		Set(key string, data []byte, ttl time.Duration) error
+	}
+
+	cacheEntry struct {
+		Expires time.Time `json:"expires"`
+		Data    []byte    `json:"data"`
+	}
+
+	memoryCache struct {
+		cache *bigcache.BigCache
+	}
+
+	fileCache struct {
+		dir string
+	}
+)
+
+// NewMemoryCache returns a CacheStore which keeps the entries in the process memory
+func NewMemoryCache() (CacheStore, error) {
+	cache, err := bigcache.NewBigCache(bigcache.DefaultConfig(maxMemoryCacheTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &memoryCache{cache: cache}, nil
+}
+
+// NewFileCache returns a CacheStore which keeps each entry in a separate file under dir
+// The directory is created if it does not exist
+func NewFileCache(dir string) (CacheStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("%w: cache directory must be provided", ErrCacheInvalidConfig)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCacheInvalidConfig, err)
+	}
+
+	return &fileCache{dir: dir}, nil
+}
+
+// DefaultCachePath returns the directory used by the file cache when none is configured
+func DefaultCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "terraform-provider-akamai")
+}
+
+func (c *memoryCache) Get(key string) ([]byte, error) {
+	data, err := c.cache.Get(key)
+	if err != nil {
+		if err == bigcache.ErrEntryNotFound {
+			return nil, ErrCacheEntryNotFound
+		}
+		return nil, err
+	}
+
+	return decodeCacheEntry(data)
+}
+
+func (c *memoryCache) Set(key string, data []byte, ttl time.Duration) error {
+	entry, err := encodeCacheEntry(data, ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.cache.Set(key, entry)
+}
+
+func (c *fileCache) Get(key string) ([]byte, error) {
+	path := c.path(key)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrCacheEntryNotFound
+		}
+		return nil, err
+	}
+
+	val, err := decodeCacheEntry(data)
+	if err == ErrCacheEntryNotFound {
+		// expired or corrupted entries are removed so the directory does not grow unbounded
+		_ = os.Remove(path)
+	}
+
+	return val, err
+}
+
+func (c *fileCache) Set(key string, data []byte, ttl time.Duration) error {
+	entry, err := encodeCacheEntry(data, ttl)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so concurrent readers never see a partial entry
+	tmp, err := ioutil.TempFile(c.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(entry); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// path returns the entry file name, keys are hashed as they may contain characters not allowed in file names
+func (c *fileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+func encodeCacheEntry(data []byte, ttl time.Duration) ([]byte, error) {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return json.Marshal(cacheEntry{
+		Expires: time.Now().Add(ttl),
+		Data:    data,
+	})
+}
+
+func decodeCacheEntry(data []byte) ([]byte, error) {
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, ErrCacheEntryNotFound
+	}
+	if time.Now().After(entry.Expires) {
+		return nil, ErrCacheEntryNotFound
+	}
+
+	return entry.Data, nil
+}
@@ -2,14 +2,19 @@ package akamai
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type (
@@ -163,6 +168,55 @@ func TestCacheSet_disabled(t *testing.T) {
 	})
 }
 
+func TestCacheStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "akamai-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	memory, err := NewMemoryCache()
+	require.NoError(t, err)
+	file, err := NewFileCache(dir)
+	require.NoError(t, err)
+
+	stores := map[string]CacheStore{
+		"memory": memory,
+		"file":   file,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.Get("missing")
+			assert.Equal(t, ErrCacheEntryNotFound, err)
+
+			require.NoError(t, store.Set("contracts:property", []byte(`{"a":1}`), time.Minute))
+			data, err := store.Get("contracts:property")
+			require.NoError(t, err)
+			assert.Equal(t, `{"a":1}`, string(data))
+
+			require.NoError(t, store.Set("expired", []byte(`{}`), time.Nanosecond))
+			time.Sleep(time.Millisecond)
+			_, err = store.Get("expired")
+			assert.Equal(t, ErrCacheEntryNotFound, err)
+		})
+	}
+}
+
+func TestFileCachePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "akamai-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	first, err := NewFileCache(dir)
+	require.NoError(t, err)
+	require.NoError(t, first.Set("groups:property", []byte(`"value"`), time.Hour))
+
+	second, err := NewFileCache(dir)
+	require.NoError(t, err)
+	data, err := second.Get("groups:property")
+	require.NoError(t, err)
+	assert.Equal(t, `"value"`, string(data))
+}
+
 func newCacheProvider() Subprovider {
 	testInst = &cacheSubprovider{}
 	return testInst
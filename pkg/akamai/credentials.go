@@ -0,0 +1,315 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+var (
+	accountKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9]+(-[A-Za-z0-9]+)+(:[A-Za-z0-9]+(-[A-Za-z0-9]+)+)?$`)
+)
+
+const (
+	// CredentialsKey is the attribute added to every resource and data source to select a named credential set
+	CredentialsKey = "credentials"
+)
+
+type (
+	// credentialSet is a named EdgeGrid configuration declared in the provider block
+	credentialSet struct {
+		name   string
+		config *edgegrid.Config
+	}
+
+	// credentialSession is the API session built for a credential set
+	credentialSession struct {
+		sess           session.Session
+		cacheNamespace string
+	}
+
+	crudFunc = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+)
+
+func credentialsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Named EdgeGrid credential sets which resources and data sources can select with the credentials attribute",
+		Optional:    true,
+		Type:        schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Description:      "The name resources use to select this credential set",
+					Required:         true,
+					Type:             schema.TypeString,
+					ValidateDiagFunc: tools.IsNotBlank,
+				},
+				"edgerc": {
+					Description: "The location of the edgerc file holding the credentials",
+					Optional:    true,
+					Type:        schema.TypeString,
+				},
+				"config_section": {
+					Description: "The section of the edgerc file to use",
+					Optional:    true,
+					Type:        schema.TypeString,
+				},
+				"account_key": {
+					Description:      "The account switch key sent with every request made with this credential set",
+					Optional:         true,
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateAccountKey,
+				},
+				"config": {
+					Description: "Inline credentials, used instead of the edgerc file",
+					Optional:    true,
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"host": {
+								Required: true,
+								Type:     schema.TypeString,
+							},
+							"access_token": {
+								Required:  true,
+								Type:      schema.TypeString,
+								Sensitive: true,
+							},
+							"client_token": {
+								Required:  true,
+								Type:      schema.TypeString,
+								Sensitive: true,
+							},
+							"client_secret": {
+								Required:  true,
+								Type:      schema.TypeString,
+								Sensitive: true,
+							},
+							"max_body": {
+								Optional: true,
+								Type:     schema.TypeInt,
+								Default:  edgegrid.MaxBodySize,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getCredentialSets reads the credential sets declared in the provider block
+// The configurations are built in memory, the process environment is never modified
+func getCredentialSets(d tools.ResourceDataFetcher) ([]credentialSet, error) {
+	blocks, err := tools.GetListValue(CredentialsKey, d)
+	if err != nil {
+		if errors.Is(err, tools.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	sets := make([]credentialSet, 0, len(blocks))
+	names := make(map[string]bool)
+	for _, b := range blocks {
+		block, ok := b.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, CredentialsKey, "map[string]interface{}")
+		}
+
+		name := block["name"].(string)
+		if names[name] {
+			return nil, fmt.Errorf("%w: %q is declared more than once", ErrCredentialsInvalid, name)
+		}
+		names[name] = true
+
+		config, err := credentialSetConfig(block)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %s", ErrCredentialsInvalid, name, err)
+		}
+		sets = append(sets, credentialSet{name: name, config: config})
+	}
+
+	return sets, nil
+}
+
+// credentialSetConfig builds the configuration of a credential set from its inline block and edgerc section
+// Environment variables are not considered, they only apply to the provider-level credentials
+func credentialSetConfig(block map[string]interface{}) (*edgegrid.Config, error) {
+	var inline map[string]interface{}
+	if values, _ := block["config"].([]interface{}); len(values) > 0 && values[0] != nil {
+		var ok bool
+		if inline, ok = values[0].(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "config", "map[string]interface{}")
+		}
+	}
+
+	builder := newEdgegridConfigBuilder(inline, block["account_key"].(string), block["edgerc"].(string), block["config_section"].(string))
+	builder.lookupEnv = nil
+
+	config, _, err := builder.Build()
+
+	return config, err
+}
+
+// getInlineConfig returns the values of the inline config block of the provider
+func getInlineConfig(d tools.ResourceDataFetcher) (map[string]interface{}, error) {
+	inline, err := tools.GetSetValue("config", d)
+	if err != nil {
+		if errors.Is(err, tools.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(inline.List()) == 0 {
+		return nil, nil
+	}
+	values, ok := inline.List()[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "config", "map[string]interface{}")
+	}
+
+	return values, nil
+}
+
+// validateAccountKey checks the account switch key format, e.g. 1-5BYUG1 or 1-5BYUG1:1-8BYUX
+func validateAccountKey(i interface{}, _ cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("%s: %q", tools.ErrInvalidType, "string")
+	}
+	if v != "" && !accountKeyRegexp.MatchString(v) {
+		return diag.Errorf("%q is not a valid account switch key, expected a value like 1-5BYUG1 or 1-5BYUG1:1-8BYUX", v)
+	}
+
+	return nil
+}
+
+// cacheNamespace identifies the account the objects cached with the config belong to
+func cacheNamespace(config *edgegrid.Config) string {
+	if config.AccountKey == "" {
+		return config.Host
+	}
+
+	return fmt.Sprintf("%s:%s", config.Host, config.AccountKey)
+}
+
+// CredentialsImportPrefix selects the credential set of an import, like credentials:customer:prp_123
+const CredentialsImportPrefix = "credentials:"
+
+// addCredentialsSelection adds the credentials attribute to the resource and wraps its operations, plan customization
+// and import so that they receive a meta whose session is signed with the selected credential set
+func addCredentialsSelection(r *schema.Resource) {
+	if _, ok := r.Schema[CredentialsKey]; ok {
+		return
+	}
+
+	r.Schema[CredentialsKey] = &schema.Schema{
+		Description: "The name of the provider credential set used for the API calls of this object",
+		Optional:    true,
+		Type:        schema.TypeString,
+	}
+
+	r.CreateContext = withCredentials(r.CreateContext)
+	r.ReadContext = withCredentials(r.ReadContext)
+	r.UpdateContext = withCredentials(r.UpdateContext)
+	r.DeleteContext = withCredentials(r.DeleteContext)
+	r.CustomizeDiff = withDiffCredentials(r.CustomizeDiff)
+	if r.Importer != nil {
+		importer := r.Importer.StateContext
+		if importer == nil && r.Importer.State != nil {
+			state := r.Importer.State
+			importer = func(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				return state(d, m)
+			}
+		}
+		r.Importer = &schema.ResourceImporter{StateContext: withImportCredentials(importer)}
+	}
+}
+
+// selectCredentials returns the meta of the named credential set, the given meta when no set is named
+func selectCredentials(name string, m interface{}) (interface{}, error) {
+	if name == "" {
+		return m, nil
+	}
+
+	mt, ok := m.(*meta)
+	if !ok {
+		return m, nil
+	}
+
+	return mt.withCredentials(name)
+}
+
+func withCredentials(f crudFunc) crudFunc {
+	if f == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		name, _ := d.Get(CredentialsKey).(string)
+		selected, err := selectCredentials(name, m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		return f(ctx, d, selected)
+	}
+}
+
+func withDiffCredentials(f schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	if f == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		name, _ := d.Get(CredentialsKey).(string)
+		selected, err := selectCredentials(name, m)
+		if err != nil {
+			return err
+		}
+
+		return f(ctx, d, selected)
+	}
+}
+
+// withImportCredentials selects the credential set named by the import ID prefix, the configuration is not known
+// during an import. The prefix is removed from the ID and the set is kept in the state for the next operations.
+func withImportCredentials(f schema.StateContextFunc) schema.StateContextFunc {
+	if f == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		if !strings.HasPrefix(d.Id(), CredentialsImportPrefix) {
+			return f(ctx, d, m)
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(d.Id(), CredentialsImportPrefix), ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%w: import ID %q, expected %s<name>:<id>", ErrCredentialsNotFound, d.Id(), CredentialsImportPrefix)
+		}
+		selected, err := selectCredentials(parts[0], m)
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(parts[1])
+		if err := d.Set(CredentialsKey, parts[0]); err != nil {
+			return nil, fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error())
+		}
+
+		return f(ctx, d, selected)
+	}
+}
@@ -0,0 +1,232 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func credentialsResourceData(t *testing.T, raw []interface{}) *schema.ResourceData {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			CredentialsKey: credentialsSchema(),
+		},
+	}
+
+	return schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		CredentialsKey: raw,
+	})
+}
+
+func TestGetCredentialSets(t *testing.T) {
+	edgerc, err := ioutil.TempFile("", "edgerc")
+	require.NoError(t, err)
+	defer os.Remove(edgerc.Name())
+	_, err = edgerc.WriteString(`[customer]
+host = customer.luna.akamaiapis.net
+client_token = customer_client_token
+client_secret = customer_client_secret
+access_token = customer_access_token
+`)
+	require.NoError(t, err)
+	require.NoError(t, edgerc.Close())
+
+	t.Run("inline and edgerc sets", func(t *testing.T) {
+		d := credentialsResourceData(t, []interface{}{
+			map[string]interface{}{
+				"name": "inline",
+				"config": []interface{}{
+					map[string]interface{}{
+						"host":          "inline.luna.akamaiapis.net",
+						"access_token":  "inline_access_token",
+						"client_token":  "inline_client_token",
+						"client_secret": "inline_client_secret",
+					},
+				},
+			},
+			map[string]interface{}{
+				"name":           "customer",
+				"edgerc":         edgerc.Name(),
+				"config_section": "customer",
+				"account_key":    "1-ABCDE",
+			},
+		})
+
+		sets, err := getCredentialSets(d)
+		require.NoError(t, err)
+		require.Len(t, sets, 2)
+
+		assert.Equal(t, "inline", sets[0].name)
+		assert.Equal(t, "inline.luna.akamaiapis.net", sets[0].config.Host)
+		assert.Equal(t, "inline_client_secret", sets[0].config.ClientSecret)
+		assert.Equal(t, 131072, sets[0].config.MaxBody)
+
+		assert.Equal(t, "customer", sets[1].name)
+		assert.Equal(t, "customer.luna.akamaiapis.net", sets[1].config.Host)
+		assert.Equal(t, "customer_access_token", sets[1].config.AccessToken)
+		assert.Equal(t, "1-ABCDE", sets[1].config.AccountKey)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		d := credentialsResourceData(t, []interface{}{
+			map[string]interface{}{"name": "customer", "edgerc": edgerc.Name(), "config_section": "customer"},
+			map[string]interface{}{"name": "customer", "edgerc": edgerc.Name(), "config_section": "customer"},
+		})
+
+		_, err := getCredentialSets(d)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing section", func(t *testing.T) {
+		d := credentialsResourceData(t, []interface{}{
+			map[string]interface{}{"name": "other", "edgerc": edgerc.Name(), "config_section": "other"},
+		})
+
+		_, err := getCredentialSets(d)
+		assert.Error(t, err)
+	})
+}
+
+func TestWithCredentials(t *testing.T) {
+	defaultSess, err := session.New()
+	require.NoError(t, err)
+	customerSess, err := session.New()
+	require.NoError(t, err)
+
+	m := &meta{
+		log:  hclog.Default(),
+		sess: defaultSess,
+		credentials: map[string]credentialSession{
+			"customer": {sess: customerSess, cacheNamespace: "customer.luna.akamaiapis.net"},
+		},
+	}
+
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+		},
+	}
+	var got OperationMeta
+	res.ReadContext = func(_ context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
+		got = Meta(m)
+		return nil
+	}
+	addCredentialsSelection(res)
+	require.Contains(t, res.Schema, CredentialsKey)
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{})
+	require.Nil(t, res.ReadContext(context.Background(), d, m))
+	assert.Same(t, defaultSess, got.Session())
+
+	d = schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{CredentialsKey: "customer"})
+	require.Nil(t, res.ReadContext(context.Background(), d, m))
+	assert.Same(t, customerSess, got.Session())
+
+	d = schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{CredentialsKey: "unknown"})
+	diags := res.ReadContext(context.Background(), d, m)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "credential set not found")
+}
+
+func TestWithCredentialsDiffAndImport(t *testing.T) {
+	defaultSess, err := session.New()
+	require.NoError(t, err)
+	customerSess, err := session.New()
+	require.NoError(t, err)
+
+	m := &meta{
+		log:  hclog.Default(),
+		sess: defaultSess,
+		credentials: map[string]credentialSession{
+			"customer": {sess: customerSess, cacheNamespace: "customer.luna.akamaiapis.net"},
+		},
+	}
+
+	var got OperationMeta
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+		},
+		CustomizeDiff: func(_ context.Context, _ *schema.ResourceDiff, m interface{}) error {
+			got = Meta(m)
+			return nil
+		},
+		Importer: &schema.ResourceImporter{
+			State: func(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				got = Meta(m)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+	}
+	addCredentialsSelection(res)
+
+	t.Run("plan signed with the selected set", func(t *testing.T) {
+		_, err := res.SimpleDiff(context.Background(), &terraform.InstanceState{}, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":         "test",
+			CredentialsKey: "customer",
+		}), m)
+		require.NoError(t, err)
+		assert.Same(t, customerSess, got.Session())
+
+		_, err = res.SimpleDiff(context.Background(), &terraform.InstanceState{}, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name": "test",
+		}), m)
+		require.NoError(t, err)
+		assert.Same(t, defaultSess, got.Session())
+
+		_, err = res.SimpleDiff(context.Background(), &terraform.InstanceState{}, terraform.NewResourceConfigRaw(map[string]interface{}{
+			CredentialsKey: "unknown",
+		}), m)
+		assert.True(t, errors.Is(err, ErrCredentialsNotFound))
+	})
+
+	t.Run("import signed with the set of the ID prefix", func(t *testing.T) {
+		d := res.TestResourceData()
+		d.SetId("credentials:customer:prp_1:v2")
+		imported, err := res.Importer.StateContext(context.Background(), d, m)
+		require.NoError(t, err)
+		assert.Same(t, customerSess, got.Session())
+		require.Len(t, imported, 1)
+		assert.Equal(t, "prp_1:v2", imported[0].Id())
+		assert.Equal(t, "customer", imported[0].Get(CredentialsKey))
+
+		d = res.TestResourceData()
+		d.SetId("prp_1")
+		_, err = res.Importer.StateContext(context.Background(), d, m)
+		require.NoError(t, err)
+		assert.Same(t, defaultSess, got.Session())
+		assert.Equal(t, "prp_1", d.Id())
+
+		for _, id := range []string{"credentials:unknown:prp_1", "credentials:customer"} {
+			d = res.TestResourceData()
+			d.SetId(id)
+			_, err = res.Importer.StateContext(context.Background(), d, m)
+			assert.True(t, errors.Is(err, ErrCredentialsNotFound), id)
+		}
+	})
+}
+
+func TestValidateAccountKey(t *testing.T) {
+	for _, key := range []string{"", "1-5BYUG1", "1-5BYUG1:1-8BYUX", "B-C-1IE2OHM:1-8BYUX", "F-AC-1234567"} {
+		assert.Nil(t, validateAccountKey(key, nil), key)
+	}
+	for _, key := range []string{"5BYUG1", "1-5BYUG1:", "1-5BYUG1?foo=bar", "1-5BYUG1 1-8BYUX"} {
+		assert.NotNil(t, validateAccountKey(key, nil), key)
+	}
+}
+
+func TestCacheNamespace(t *testing.T) {
+	assert.Equal(t, "host.luna.akamaiapis.net", cacheNamespace(&edgegrid.Config{Host: "host.luna.akamaiapis.net"}))
+	assert.Equal(t, "host.luna.akamaiapis.net:1-5BYUG1", cacheNamespace(&edgegrid.Config{Host: "host.luna.akamaiapis.net", AccountKey: "1-5BYUG1"}))
+}
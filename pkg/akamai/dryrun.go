@@ -0,0 +1,171 @@
+package akamai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/apex/log"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type (
+	// DryRunError is returned in dry run mode in place of sending a request which would change an object
+	DryRunError struct {
+		Method string
+		URI    string
+		Body   []byte
+	}
+
+	dryRunTransport struct {
+		next http.RoundTripper
+		log  log.Interface
+	}
+
+	// dryRunRequests collects the requests a resource operation did not send in dry run mode
+	dryRunRequests struct {
+		sync.Mutex
+		errs []*DryRunError
+	}
+
+	dryRunRequestsKey struct{}
+)
+
+// Error implements the error interface
+func (e *DryRunError) Error() string {
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("dry run, would have sent %s %s", e.Method, e.URI)
+	}
+
+	return fmt.Sprintf("dry run, would have sent %s %s with body %s", e.Method, e.URI, e.Body)
+}
+
+// IsValidationRequest tells whether a request only validates its input without changing anything, e.g. the PAPI dryRun calls
+func IsValidationRequest(r *http.Request) bool {
+	return APIFamily(r) == APIFamilyPAPI && r.URL.Query().Get("dryRun") == "true"
+}
+
+// NewDryRunTransport returns a http.RoundTripper which only passes the reads and validation requests to next
+// Every other request fails with a DryRunError describing it
+func NewDryRunTransport(next http.RoundTripper, log log.Interface) http.RoundTripper {
+	return &dryRunTransport{
+		next: next,
+		log:  log,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *dryRunTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.next.RoundTrip(r)
+	}
+	if IsValidationRequest(r) {
+		return t.next.RoundTrip(r)
+	}
+
+	err := &DryRunError{
+		Method: r.Method,
+		URI:    dryRunURI(r),
+	}
+	if r.Body != nil {
+		body, readErr := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+		err.Body = bytes.TrimSpace(body)
+	}
+
+	t.log.WithFields(log.Fields{
+		"api":    APIFamily(r),
+		"method": r.Method,
+		"path":   r.URL.Path,
+	}).Info("dry run, request not sent")
+
+	if requests, ok := r.Context().Value(dryRunRequestsKey{}).(*dryRunRequests); ok {
+		requests.Lock()
+		requests.errs = append(requests.errs, err)
+		requests.Unlock()
+	}
+
+	return nil, err
+}
+
+// dryRunURI returns the path and query of the request without the account switch key added by the signer
+func dryRunURI(r *http.Request) string {
+	query := r.URL.Query()
+	query.Del("accountSwitchKey")
+	if len(query) == 0 {
+		return r.URL.Path
+	}
+
+	return strings.Join([]string{r.URL.Path, query.Encode()}, "?")
+}
+
+// addDryRunReport wraps the resource operations so that in dry run mode the requests they did not send are reported
+// in place of the errors they caused. An update is skipped with a warning and the state keeps the prior values.
+// Creates and deletes still fail, as Terraform requires a created object and removes a deleted one from the state.
+func addDryRunReport(name string, r *schema.Resource) {
+	r.CreateContext = withDryRunReport(name, "create", r.CreateContext)
+	r.UpdateContext = withDryRunReport(name, "update", r.UpdateContext)
+	r.DeleteContext = withDryRunReport(name, "delete", r.DeleteContext)
+}
+
+func withDryRunReport(name, operation string, f crudFunc) crudFunc {
+	if f == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if mt, ok := m.(OperationMeta); !ok || !mt.DryRun() {
+			return f(ctx, d, m)
+		}
+
+		requests := &dryRunRequests{}
+		diags := f(context.WithValue(ctx, dryRunRequestsKey{}, requests), d, m)
+		if len(requests.errs) == 0 {
+			return diags
+		}
+
+		// the errors caused by the requests which were not sent are replaced by the report
+		var report diag.Diagnostics
+		lines := make([]string, 0, len(requests.errs))
+		for _, e := range requests.errs {
+			lines = append(lines, strings.TrimPrefix(e.Error(), "dry run, would have sent "))
+		}
+		for _, dg := range diags {
+			if dg.Severity == diag.Error && causedByDryRun(dg, requests.errs) {
+				continue
+			}
+			report = append(report, dg)
+		}
+
+		severity := diag.Error
+		if operation == "update" {
+			severity = diag.Warning
+			d.Partial(true)
+		}
+		return append(report, diag.Diagnostic{
+			Severity: severity,
+			Summary:  fmt.Sprintf("dry run, %s was not %sd", resourceAddress(name, d.Id()), operation),
+			Detail:   fmt.Sprintf("The %s would have sent:\n  - %s", operation, strings.Join(lines, "\n  - ")),
+		})
+	}
+}
+
+// causedByDryRun tells whether the diagnostic reports one of the requests which were not sent
+func causedByDryRun(d diag.Diagnostic, errs []*DryRunError) bool {
+	for _, e := range errs {
+		if strings.Contains(d.Summary, e.Error()) || strings.Contains(d.Detail, e.Error()) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,149 @@
+package akamai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunTransport(t *testing.T) {
+	var sent []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewDryRunTransport(http.DefaultTransport, Log())}
+
+	tests := map[string]struct {
+		method    string
+		path      string
+		body      string
+		withError string
+	}{
+		"read": {
+			method: http.MethodGet,
+			path:   "/papi/v1/properties/prp_1?contractId=ctr_1&groupId=grp_2",
+		},
+		"rules validation": {
+			method: http.MethodPut,
+			path:   "/papi/v1/properties/prp_1/versions/2/rules?contractId=ctr_1&groupId=grp_2&dryRun=true",
+			body:   `{"rules":{}}`,
+		},
+		"rules update": {
+			method:    http.MethodPut,
+			path:      "/papi/v1/properties/prp_1/versions/2/rules?contractId=ctr_1&groupId=grp_2&accountSwitchKey=1-ABC",
+			body:      `{"rules":{}}`,
+			withError: `dry run, would have sent PUT /papi/v1/properties/prp_1/versions/2/rules?contractId=ctr_1&groupId=grp_2 with body {"rules":{}}`,
+		},
+		"record delete": {
+			method:    http.MethodDelete,
+			path:      "/config-dns/v2/zones/example.com/names/www.example.com/types/A",
+			withError: "dry run, would have sent DELETE /config-dns/v2/zones/example.com/names/www.example.com/types/A",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sent = nil
+			req, err := http.NewRequest(test.method, srv.URL+test.path, strings.NewReader(test.body))
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			if test.withError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.withError)
+				assert.Empty(t, sent)
+				return
+			}
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, []string{test.method + " " + strings.Split(test.path, "?")[0]}, sent)
+		})
+	}
+}
+
+func TestDryRunReport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: NewDryRunTransport(http.DefaultTransport, Log())}
+
+	// the operations fail at their first change, like the API clients do with the error of the transport
+	write := func(method string) crudFunc {
+		return func(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			if err := d.Set("name", "changed"); err != nil {
+				return diag.FromErr(err)
+			}
+			req, err := http.NewRequestWithContext(ctx, method, srv.URL+"/config-dns/v2/zones/example.com", nil)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("zone request failed: %w", err))
+			}
+			resp.Body.Close()
+			return nil
+		}
+	}
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+		},
+		CreateContext: write(http.MethodPost),
+		UpdateContext: write(http.MethodPut),
+		DeleteContext: write(http.MethodDelete),
+	}
+	addDryRunReport("akamai_dns_zone", res)
+	dryRun := &meta{log: hclog.Default(), dryRun: true}
+
+	t.Run("update skipped with a warning", func(t *testing.T) {
+		d := res.TestResourceData()
+		d.SetId("example.com")
+		diags := res.UpdateContext(context.Background(), d, dryRun)
+		require.Len(t, diags, 1)
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Equal(t, "dry run, akamai_dns_zone example.com was not updated", diags[0].Summary)
+		assert.Equal(t, "The update would have sent:\n  - PUT /config-dns/v2/zones/example.com", diags[0].Detail)
+
+		// the state keeps the prior values
+		state := d.State()
+		require.NotNil(t, state)
+		assert.Empty(t, state.Attributes["name"])
+	})
+
+	t.Run("create and delete fail with the report", func(t *testing.T) {
+		d := res.TestResourceData()
+		diags := res.CreateContext(context.Background(), d, dryRun)
+		require.Len(t, diags, 1)
+		assert.Equal(t, diag.Error, diags[0].Severity)
+		assert.Equal(t, "dry run, akamai_dns_zone was not created", diags[0].Summary)
+
+		d.SetId("example.com")
+		diags = res.DeleteContext(context.Background(), d, dryRun)
+		require.Len(t, diags, 1)
+		assert.Equal(t, diag.Error, diags[0].Severity)
+		assert.Equal(t, "The delete would have sent:\n  - DELETE /config-dns/v2/zones/example.com", diags[0].Detail)
+	})
+
+	t.Run("operations outside of dry run are not changed", func(t *testing.T) {
+		d := res.TestResourceData()
+		d.SetId("example.com")
+		diags := res.UpdateContext(context.Background(), d, &meta{log: hclog.Default()})
+		require.Len(t, diags, 1)
+		assert.Equal(t, diag.Error, diags[0].Severity)
+		assert.Contains(t, diags[0].Summary, "zone request failed: ")
+	})
+}
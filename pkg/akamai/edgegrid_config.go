@@ -0,0 +1,223 @@
+package akamai
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+const (
+	sourceConfigBlock = "config block"
+	sourceAccountKey  = "account_key attribute"
+)
+
+type (
+	// edgegridConfigBuilder merges the EdgeGrid credentials from all the supported sources in memory
+	//
+	// Each field is resolved on its own, in this order of precedence:
+	//   1. the inline config block of the provider
+	//   2. the AKAMAI_{SECTION}_* environment variables, AKAMAI_* for the default section
+	//   3. the section of the edgerc file
+	// The account_key attribute of the provider, when set, overrides the account key of every source.
+	edgegridConfigBuilder struct {
+		inline     map[string]interface{}
+		accountKey string
+		edgerc     string
+		section    string
+		lookupEnv  func(string) (string, bool)
+	}
+
+	// edgegridSources records where each field of the EdgeGrid configuration was read from
+	edgegridSources map[string]string
+)
+
+var (
+	requiredEdgegridFields = []string{"host", "client_token", "client_secret", "access_token"}
+
+	edgegridFields = []string{"host", "client_token", "client_secret", "access_token", "max_body", "account_key"}
+)
+
+// newEdgegridConfigBuilder returns a builder reading the process environment
+func newEdgegridConfigBuilder(inline map[string]interface{}, accountKey, edgerc, section string) *edgegridConfigBuilder {
+	if edgerc == "" {
+		edgerc = edgegrid.DefaultConfigFile
+	}
+	if section == "" {
+		section = edgegrid.DefaultSection
+	}
+
+	return &edgegridConfigBuilder{
+		inline:     inline,
+		accountKey: accountKey,
+		edgerc:     edgerc,
+		section:    section,
+		lookupEnv:  os.LookupEnv,
+	}
+}
+
+// Build returns the merged configuration and the source of each field
+func (b *edgegridConfigBuilder) Build() (*edgegrid.Config, edgegridSources, error) {
+	sources := make(edgegridSources)
+
+	file := &edgegrid.Config{}
+	fileErr := file.FromFile(b.edgerc, b.section)
+	// a section missing some options is still merged with the other sources
+	if fileErr != nil && !errors.Is(fileErr, edgegrid.ErrRequiredOptionEdgerc) {
+		file = &edgegrid.Config{}
+	}
+	fileSource := fmt.Sprintf("edgerc file %s [%s]", b.edgerc, b.section)
+
+	values := make(map[string]string)
+	for _, field := range edgegridFields {
+		if v := b.inlineValue(field); v != "" {
+			values[field], sources[field] = v, sourceConfigBlock
+			continue
+		}
+		if name := b.envName(field); name != "" {
+			if v, ok := b.lookupEnv(name); ok && v != "" {
+				values[field], sources[field] = v, "environment variable "+name
+				continue
+			}
+		}
+		if v := fileValue(file, field); v != "" {
+			values[field], sources[field] = v, fileSource
+		}
+	}
+	if b.accountKey != "" {
+		values["account_key"], sources["account_key"] = b.accountKey, sourceAccountKey
+	}
+
+	var missing []string
+	for _, field := range requiredEdgegridFields {
+		if values[field] == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		checked := []string{sourceConfigBlock}
+		if b.lookupEnv != nil {
+			checked = append(checked, b.envPrefix()+"_* environment variables")
+		}
+		checked = append(checked, fileSource)
+		err := fmt.Errorf("%w: %s not set in any of: %s",
+			ErrCredentialsInvalid, strings.Join(missing, ", "), strings.Join(checked, ", "))
+		if fileErr != nil && !errors.Is(fileErr, edgegrid.ErrRequiredOptionEdgerc) {
+			err = fmt.Errorf("%s: %s", err, fileErr)
+		}
+		return nil, sources, err
+	}
+
+	config := &edgegrid.Config{
+		Host:         values["host"],
+		ClientToken:  values["client_token"],
+		ClientSecret: values["client_secret"],
+		AccessToken:  values["access_token"],
+		AccountKey:   values["account_key"],
+		HeaderToSign: file.HeaderToSign,
+		MaxBody:      edgegrid.MaxBodySize,
+	}
+	if v, err := strconv.Atoi(values["max_body"]); err == nil && v > 0 {
+		config.MaxBody = v
+	}
+
+	return config, sources, nil
+}
+
+func (b *edgegridConfigBuilder) inlineValue(field string) string {
+	switch v := b.inline[field].(type) {
+	case string:
+		return v
+	case int:
+		if v > 0 {
+			return strconv.Itoa(v)
+		}
+	}
+
+	return ""
+}
+
+func (b *edgegridConfigBuilder) envPrefix() string {
+	if b.section == edgegrid.DefaultSection {
+		return "AKAMAI"
+	}
+
+	return "AKAMAI_" + strings.ToUpper(b.section)
+}
+
+func (b *edgegridConfigBuilder) envName(field string) string {
+	if b.lookupEnv == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s_%s", b.envPrefix(), strings.ToUpper(field))
+}
+
+func fileValue(c *edgegrid.Config, field string) string {
+	switch field {
+	case "host":
+		return c.Host
+	case "client_token":
+		return c.ClientToken
+	case "client_secret":
+		return c.ClientSecret
+	case "access_token":
+		return c.AccessToken
+	case "account_key":
+		return c.AccountKey
+	case "max_body":
+		if c.MaxBody > 0 {
+			return strconv.Itoa(c.MaxBody)
+		}
+	}
+
+	return ""
+}
+
+// String lists the source of each field, the values are never included as they may be secrets
+func (s edgegridSources) String() string {
+	fields := make([]string, 0, len(s))
+	for field := range s {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	lines := make([]string, 0, len(fields))
+	for _, field := range fields {
+		lines = append(lines, fmt.Sprintf("%s: %s", field, s[field]))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// mixed tells whether the required fields were read from more than one source
+func (s edgegridSources) mixed() bool {
+	seen := make(map[string]bool)
+	for _, field := range requiredEdgegridFields {
+		if source, ok := s[field]; ok {
+			seen[source] = true
+		}
+	}
+
+	return len(seen) > 1
+}
+
+// Diagnostics returns a warning when the credentials were assembled from several sources,
+// as a leftover environment variable silently replacing a field is hard to spot otherwise
+func (s edgegridSources) Diagnostics() diag.Diagnostics {
+	if !s.mixed() {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Akamai EdgeGrid credentials are merged from several sources",
+		Detail: "The credential fields were read from these sources, the config block takes precedence over " +
+			"environment variables, which take precedence over the edgerc file:\n" + s.String(),
+	}}
+}
@@ -0,0 +1,157 @@
+package akamai
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgegridConfigBuilder(t *testing.T) {
+	edgerc, err := ioutil.TempFile("", "edgerc")
+	require.NoError(t, err)
+	defer os.Remove(edgerc.Name())
+	_, err = edgerc.WriteString(`[default]
+host = file_host
+client_token = file_client_token
+client_secret = file_client_secret
+access_token = file_access_token
+
+[partial]
+host = partial_host
+account_key = 1-FILE
+`)
+	require.NoError(t, err)
+	require.NoError(t, edgerc.Close())
+
+	inline := map[string]interface{}{
+		"access_token":  "test_access_token",
+		"client_token":  "test_client_token",
+		"client_secret": "test_client_secret",
+		"host":          "test_host",
+		"max_body":      123,
+	}
+
+	tests := map[string]struct {
+		inline          map[string]interface{}
+		accountKey      string
+		edgerc          string
+		section         string
+		envs            map[string]string
+		expected        *edgegrid.Config
+		expectedSources edgegridSources
+		withError       bool
+		mixed           bool
+	}{
+		"config block only": {
+			inline: inline,
+			edgerc: "/not/existing/edgerc",
+			expected: &edgegrid.Config{
+				Host:         "test_host",
+				ClientToken:  "test_client_token",
+				ClientSecret: "test_client_secret",
+				AccessToken:  "test_access_token",
+				MaxBody:      123,
+			},
+			expectedSources: edgegridSources{
+				"host":          sourceConfigBlock,
+				"client_token":  sourceConfigBlock,
+				"client_secret": sourceConfigBlock,
+				"access_token":  sourceConfigBlock,
+				"max_body":      sourceConfigBlock,
+			},
+		},
+		"config block takes precedence over envs": {
+			inline:  inline,
+			edgerc:  "/not/existing/edgerc",
+			section: "test",
+			envs: map[string]string{
+				"AKAMAI_TEST_ACCESS_TOKEN": "existing_access_token",
+				"AKAMAI_TEST_HOST":         "existing_host",
+				"AKAMAI_TEST_ACCOUNT_KEY":  "1-ENV",
+			},
+			expected: &edgegrid.Config{
+				Host:         "test_host",
+				ClientToken:  "test_client_token",
+				ClientSecret: "test_client_secret",
+				AccessToken:  "test_access_token",
+				AccountKey:   "1-ENV",
+				MaxBody:      123,
+			},
+			expectedSources: edgegridSources{
+				"host":          sourceConfigBlock,
+				"client_token":  sourceConfigBlock,
+				"client_secret": sourceConfigBlock,
+				"access_token":  sourceConfigBlock,
+				"max_body":      sourceConfigBlock,
+				"account_key":   "environment variable AKAMAI_TEST_ACCOUNT_KEY",
+			},
+		},
+		"envs take precedence over edgerc": {
+			edgerc: edgerc.Name(),
+			envs: map[string]string{
+				"AKAMAI_CLIENT_SECRET": "env_client_secret",
+			},
+			expected: &edgegrid.Config{
+				Host:         "file_host",
+				ClientToken:  "file_client_token",
+				ClientSecret: "env_client_secret",
+				AccessToken:  "file_access_token",
+				MaxBody:      edgegrid.MaxBodySize,
+			},
+			mixed: true,
+		},
+		"partial edgerc section merged with envs and account key attribute": {
+			edgerc:     edgerc.Name(),
+			section:    "partial",
+			accountKey: "1-ATTR",
+			envs: map[string]string{
+				"AKAMAI_PARTIAL_CLIENT_TOKEN":  "env_client_token",
+				"AKAMAI_PARTIAL_CLIENT_SECRET": "env_client_secret",
+				"AKAMAI_PARTIAL_ACCESS_TOKEN":  "env_access_token",
+			},
+			expected: &edgegrid.Config{
+				Host:         "partial_host",
+				ClientToken:  "env_client_token",
+				ClientSecret: "env_client_secret",
+				AccessToken:  "env_access_token",
+				AccountKey:   "1-ATTR",
+				MaxBody:      edgegrid.MaxBodySize,
+			},
+			mixed: true,
+		},
+		"missing fields": {
+			edgerc:    edgerc.Name(),
+			section:   "partial",
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := newEdgegridConfigBuilder(test.inline, test.accountKey, test.edgerc, test.section)
+			builder.lookupEnv = func(key string) (string, bool) {
+				v, ok := test.envs[key]
+				return v, ok
+			}
+
+			config, sources, err := builder.Build()
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, config)
+			if test.expectedSources != nil {
+				assert.Equal(t, test.expectedSources, sources)
+			}
+			assert.Equal(t, test.mixed, sources.Diagnostics() != nil)
+			for _, secret := range []string{config.ClientSecret, config.AccessToken, config.ClientToken} {
+				assert.NotContains(t, sources.String(), secret)
+			}
+		})
+	}
+}
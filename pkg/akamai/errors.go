@@ -24,6 +24,18 @@ var (
 	// ErrCacheDisabled is returned when the cache is disabled
 	ErrCacheDisabled = &Error{"cache is disabled", false}
 
+	// ErrCacheInvalidConfig is returned when the cache backend cannot be configured
+	ErrCacheInvalidConfig = &Error{"invalid cache configuration", false}
+
+	// ErrCredentialsInvalid is returned when a credential set of the provider block cannot be loaded
+	ErrCredentialsInvalid = &Error{"invalid credential set", false}
+
+	// ErrCredentialsNotFound is returned when a resource selects a credential set which is not declared
+	ErrCredentialsNotFound = &Error{"credential set not found", true}
+
+	// ErrAuditLogInvalid is returned when the audit log file cannot be opened
+	ErrAuditLogInvalid = &Error{"invalid audit log", false}
+
 	// ErrProviderNotLoaded returned and panic'd when a requested provider is not loaded
 	// Users should never see this, unit tests and sanity checks should pick this up
 	ErrProviderNotLoaded = &Error{"provider not loaded", false}
@@ -3,9 +3,9 @@ package akamai
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
-	"github.com/allegro/bigcache"
 	"github.com/apex/log"
 	"github.com/hashicorp/go-hclog"
 )
@@ -26,15 +26,26 @@ type (
 		// CacheGet returns an object from the cache
 		CacheGet(prov Subprovider, key string, out interface{}) error
 
-		// CacheSet sets a value in the cache
+		// CacheSet sets a value in the cache using the provider default ttl
 		CacheSet(prov Subprovider, key string, val interface{}) error
+
+		// CacheSetWithTTL sets a value in the cache which expires after ttl
+		CacheSetWithTTL(prov Subprovider, key string, val interface{}, ttl time.Duration) error
+
+		// DryRun tells whether the provider only sends reads and validation requests
+		DryRun() bool
 	}
 
 	meta struct {
-		operationID  string
-		log          hclog.Logger
-		sess         session.Session
-		cacheEnabled bool
+		operationID    string
+		log            hclog.Logger
+		sess           session.Session
+		cacheEnabled   bool
+		cache          CacheStore
+		cacheTTL       time.Duration
+		cacheNamespace string
+		credentials    map[string]credentialSession
+		dryRun         bool
 	}
 )
 
@@ -58,7 +69,16 @@ func (m *meta) Session() session.Session {
 	return m.sess
 }
 
+// DryRun returns whether the meta session is in dry run mode
+func (m *meta) DryRun() bool {
+	return m.dryRun
+}
+
 func (m *meta) CacheSet(prov Subprovider, key string, val interface{}) error {
+	return m.CacheSetWithTTL(prov, key, val, m.cacheTTL)
+}
+
+func (m *meta) CacheSetWithTTL(prov Subprovider, key string, val interface{}, ttl time.Duration) error {
 	log := m.Log("meta", "CacheSet")
 
 	if !m.cacheEnabled {
@@ -66,16 +86,16 @@ func (m *meta) CacheSet(prov Subprovider, key string, val interface{}) error {
 		return ErrCacheDisabled
 	}
 
-	key = fmt.Sprintf("%s:%s", key, prov.Name())
+	key = m.cacheKey(prov, key)
 
 	data, err := json.Marshal(val)
 	if err != nil {
 		return fmt.Errorf("failed to marshal object to cache: %w", err)
 	}
 
-	log.Debugf("cache set for for key %s [%d bytes]", key, len(data))
+	log.Debugf("cache set for for key %s [%d bytes, ttl %s]", key, len(data), ttl)
 
-	return instance.cache.Set(key, data)
+	return m.cache.Set(key, data, ttl)
 }
 
 func (m *meta) CacheGet(prov Subprovider, key string, out interface{}) error {
@@ -86,14 +106,12 @@ func (m *meta) CacheGet(prov Subprovider, key string, out interface{}) error {
 		return ErrCacheDisabled
 	}
 
-	key = fmt.Sprintf("%s:%s", key, prov.Name())
+	key = m.cacheKey(prov, key)
 
-	data, err := instance.cache.Get(key)
+	data, err := m.cache.Get(key)
 	if err != nil {
-		if err == bigcache.ErrEntryNotFound {
+		if err == ErrCacheEntryNotFound {
 			log.Debugf("cache miss for for key %s", key)
-
-			return ErrCacheEntryNotFound
 		}
 		return err
 	}
@@ -102,3 +120,29 @@ func (m *meta) CacheGet(prov Subprovider, key string, out interface{}) error {
 
 	return json.Unmarshal(data, out)
 }
+
+// cacheKey scopes the key to the subprovider and the credentials in use
+// so a persistent cache never returns objects fetched for another account
+func (m *meta) cacheKey(prov Subprovider, key string) string {
+	key = fmt.Sprintf("%s:%s", key, prov.Name())
+	if m.cacheNamespace != "" {
+		key = fmt.Sprintf("%s:%s", m.cacheNamespace, key)
+	}
+
+	return key
+}
+
+// withCredentials returns a copy of the meta which uses the session of the named credential set
+func (m *meta) withCredentials(name string) (*meta, error) {
+	creds, ok := m.credentials[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q, declare it in a credentials block of the provider", ErrCredentialsNotFound, name)
+	}
+
+	selected := *m
+	selected.sess = creds.sess
+	selected.cacheNamespace = creds.cacheNamespace
+	selected.log = m.log.With("credentials", name)
+
+	return &selected, nil
+}
@@ -4,18 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/allegro/bigcache"
 	"github.com/apex/log"
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/spf13/cast"
 
@@ -59,8 +58,9 @@ type (
 
 	provider struct {
 		schema.Provider
-		subs  map[string]Subprovider
-		cache *bigcache.BigCache
+		subs      map[string]Subprovider
+		cache     CacheStore
+		transport http.RoundTripper
 	}
 )
 
@@ -86,6 +86,13 @@ func Provider(provs ...Subprovider) plugin.ProviderFunc {
 						Optional:    true,
 						Type:        schema.TypeString,
 					},
+					"account_key": {
+						Description:      "The account switch key appended to every request, used by partners and managed service accounts",
+						Optional:         true,
+						Type:             schema.TypeString,
+						DefaultFunc:      schema.EnvDefaultFunc("AKAMAI_ACCOUNT_KEY", nil),
+						ValidateDiagFunc: validateAccountKey,
+					},
 					"config": {
 						Optional: true,
 						Type:     schema.TypeSet,
@@ -97,6 +104,36 @@ func Provider(provs ...Subprovider) plugin.ProviderFunc {
 						Default:  true,
 						Type:     schema.TypeBool,
 					},
+					"cache_type": {
+						Description:  "The cache backend to use, either memory or file",
+						Optional:     true,
+						Type:         schema.TypeString,
+						Default:      CacheTypeMemory,
+						ValidateFunc: validation.StringInSlice([]string{CacheTypeMemory, CacheTypeFile}, false),
+					},
+					"cache_path": {
+						Description: "The directory used by the file cache backend",
+						Optional:    true,
+						Type:        schema.TypeString,
+						DefaultFunc: schema.EnvDefaultFunc("AKAMAI_CACHE_PATH", nil),
+					},
+					"cache_ttl": {
+						Description:      "The default time to live of cached objects, e.g. 10m or 24h",
+						Optional:         true,
+						Type:             schema.TypeString,
+						Default:          DefaultCacheTTL.String(),
+						ValidateDiagFunc: tools.ValidateDuration(0),
+					},
+					"dry_run": {
+						Description: "Only send the reads and validation requests, every change is reported instead of being applied",
+						Optional:    true,
+						Type:        schema.TypeBool,
+						DefaultFunc: schema.EnvDefaultFunc("AKAMAI_DRY_RUN", false),
+					},
+					"rate_limit":  rateLimitSchema(),
+					"retry":       retrySchema(),
+					"credentials": credentialsSchema(),
+					"audit_log":   auditLogSchema(),
 				},
 				ResourcesMap:       make(map[string]*schema.Resource),
 				DataSourcesMap:     make(map[string]*schema.Resource),
@@ -105,7 +142,7 @@ func Provider(provs ...Subprovider) plugin.ProviderFunc {
 			subs: make(map[string]Subprovider),
 		}
 
-		cache, err := bigcache.NewBigCache(bigcache.DefaultConfig(10 * time.Minute))
+		cache, err := NewMemoryCache()
 		if err != nil {
 			panic(err)
 		}
@@ -132,6 +169,15 @@ func Provider(provs ...Subprovider) plugin.ProviderFunc {
 			instance.subs[p.Name()] = p
 		}
 
+		for name, r := range instance.ResourcesMap {
+			addCredentialsSelection(r)
+			addAuditResource(name, r)
+			addDryRunReport(name, r)
+		}
+		for _, r := range instance.DataSourcesMap {
+			addCredentialsSelection(r)
+		}
+
 		instance.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 			// generate an operation id so we can correlate all calls to this provider
 			opid := uuid.Must(uuid.NewRandom()).String()
@@ -153,61 +199,111 @@ func Provider(provs ...Subprovider) plugin.ProviderFunc {
 				return nil, diag.FromErr(err)
 			}
 
-			edgercOps := []edgegrid.Option{edgegrid.WithEnv(true)}
-
 			edgercPath, err := tools.GetStringValue("edgerc", d)
 			if err != nil && !errors.Is(err, tools.ErrNotFound) {
 				return nil, diag.FromErr(err)
 			}
-			if edgercPath == "" {
-				edgercPath = edgegrid.DefaultConfigFile
-			}
-			edgercOps = append(edgercOps, edgegrid.WithFile(edgercPath))
 			edgercSection, err := tools.GetStringValue("config_section", d)
 			if err != nil && !errors.Is(err, tools.ErrNotFound) {
 				return nil, diag.FromErr(err)
 			}
-			if err == nil {
-				edgercOps = append(edgercOps, edgegrid.WithSection(edgercSection))
+			inline, err := getInlineConfig(d)
+			if err != nil {
+				return nil, diag.FromErr(err)
 			}
-			envs, err := tools.GetSetValue("config", d)
+			accountKey, err := tools.GetStringValue("account_key", d)
 			if err != nil && !errors.Is(err, tools.ErrNotFound) {
 				return nil, diag.FromErr(err)
 			}
-			if err == nil && len(envs.List()) > 0 {
-				envsMap, ok := envs.List()[0].(map[string]interface{})
-				if !ok {
-					return nil, diag.FromErr(fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "config", "map[string]interface{}"))
-				}
-				err = setEdgegridEnvs(envsMap, edgercSection)
-				if err != nil {
-					return nil, diag.FromErr(err)
+
+			edgerc, sources, err := newEdgegridConfigBuilder(inline, accountKey, edgercPath, edgercSection).Build()
+			if err != nil {
+				return nil, diag.Diagnostics{{
+					Severity: diag.Error,
+					Summary:  "Akamai EdgeGrid configuration was not specified",
+					Detail:   fmt.Sprintf("%s\n\nSpecify the configuration using the config block, system environment variables or the location and file name containing the edgerc configuration. Default location the provider checks for is the current user’s home directory. Default configuration file name the provider checks for is .edgerc.", err),
+				}}
+			}
+			LogFromHCLog(log).Debugf("EdgeGrid credential sources:\n%s", sources)
+			diags := sources.Diagnostics()
+
+			if edgerc.AccountKey != "" {
+				if diags := validateAccountKey(edgerc.AccountKey, nil); diags != nil {
+					return nil, diags
 				}
+				log = log.With("AccountKey", edgerc.AccountKey)
 			}
 
-			edgerc, err := edgegrid.New(edgercOps...)
+			cache, cacheTTL, err := configureCache(d)
 			if err != nil {
-				return nil, diag.Errorf("Akamai EdgeGrid configuration was not specified. Specify the configuration using system environment variables or the location and file name containing the edgerc configuration. Default location the provider checks for is the current user’s home directory. Default configuration file name the provider checks for is .edgerc.")
+				return nil, diag.FromErr(err)
 			}
 
 			// PROVIDER_VERSION env value must be updated in version file, for every new release.
 			userAgent := instance.UserAgent(ProviderName, version.ProviderVersion)
 
-			sess, err := session.New(
-				session.WithSigner(edgerc),
-				session.WithUserAgent(userAgent),
-				session.WithLog(LogFromHCLog(log)),
-				session.WithHTTPTracing(cast.ToBool(os.Getenv("AKAMAI_HTTP_TRACE_ENABLED"))),
-			)
+			retryPolicy, err := getRetryPolicy(d)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+
+			transport, err := configureTransport(d, LogFromHCLog(log))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+
+			audit, err := getAuditLog(d, opid)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+
+			dryRun, err := tools.GetBoolValue("dry_run", d)
+			if err != nil && !errors.Is(err, tools.ErrNotFound) {
+				return nil, diag.FromErr(err)
+			}
+			if dryRun {
+				log = log.With("DryRun", true)
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Akamai provider in dry run mode",
+					Detail:   "Only reads and validation requests are sent to the Akamai APIs. Every change fails with an error describing the request that would have been sent.",
+				})
+			}
+
+			sess, err := newSession(transport, retryPolicy, edgerc, userAgent, dryRun, audit, LogFromHCLog(log))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+
+			credentialSets, err := getCredentialSets(d)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			credentials := make(map[string]credentialSession, len(credentialSets))
+			for _, set := range credentialSets {
+				setSess, err := newSession(transport, retryPolicy, set.config, userAgent, dryRun, audit.WithCredentials(set.name), LogFromHCLog(log.With("credentials", set.name)))
+				if err != nil {
+					return nil, diag.FromErr(err)
+				}
+				credentials[set.name] = credentialSession{
+					sess:           setSess,
+					cacheNamespace: cacheNamespace(set.config),
+				}
+			}
 
 			meta := &meta{
-				log:          log,
-				operationID:  opid,
-				sess:         sess,
-				cacheEnabled: cacheEnabled,
+				log:            log,
+				operationID:    opid,
+				sess:           sess,
+				cacheEnabled:   cacheEnabled,
+				cache:          cache,
+				cacheTTL:       cacheTTL,
+				cacheNamespace: cacheNamespace(edgerc),
+				credentials:    credentials,
+				dryRun:         dryRun,
 			}
 
-			return meta, nil
+			return meta, diags
 		}
 	})
 
@@ -216,52 +312,106 @@ func Provider(provs ...Subprovider) plugin.ProviderFunc {
 	}
 }
 
-func setEdgegridEnvs(envsMap map[string]interface{}, section string) error {
-	configEnvs := []string{"ACCESS_TOKEN", "CLIENT_TOKEN", "HOST", "CLIENT_SECRET", "MAX_BODY"}
-	prefix := "AKAMAI"
-	if section != "" {
-		prefix = fmt.Sprintf("%s_%s", prefix, strings.ToUpper(section))
+// SetHTTPTransport replaces the http transport the provider sessions are built on
+// It is meant for tests replaying recorded API exchanges, nil restores http.DefaultTransport
+func SetHTTPTransport(t http.RoundTripper) {
+	if instance == nil {
+		panic(ErrProviderNotLoaded)
 	}
-	for _, env := range configEnvs {
-		var value string
-		var ok bool
-		switch env {
-		case "ACCESS_TOKEN":
-			value, ok = envsMap["access_token"].(string)
-			if !ok {
-				return fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "access_token", "string")
-			}
-		case "CLIENT_TOKEN":
-			value, ok = envsMap["client_token"].(string)
-			if !ok {
-				return fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "client_token", "string")
-			}
-		case "HOST":
-			value, ok = envsMap["host"].(string)
-			if !ok {
-				return fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "host", "string")
-			}
-		case "CLIENT_SECRET":
-			value, ok = envsMap["client_secret"].(string)
-			if !ok {
-				return fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "client_secret", "string")
-			}
-		case "MAX_BODY":
-			maxBody, ok := envsMap["max_body"].(int)
-			if !ok {
-				return fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "max_body", "int")
-			}
-			value = strconv.Itoa(maxBody)
-		}
-		env = fmt.Sprintf("%s_%s", prefix, env)
-		if os.Getenv(env) != "" {
-			continue
-		}
-		if err := os.Setenv(env, value); err != nil {
-			return err
+	instance.transport = t
+}
+
+// configureTransport returns the http transport shared by all the EdgeGrid requests of the provider
+func configureTransport(d *schema.ResourceData, log log.Interface) (http.RoundTripper, error) {
+	transport := http.DefaultTransport
+	if instance.transport != nil {
+		transport = instance.transport
+	}
+
+	rateLimit, ok, err := getRateLimitConfig(d)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		transport = NewRateLimitTransport(transport, rateLimit, log)
+	}
+
+	return transport, nil
+}
+
+// newSession returns an API session signed with the given credentials
+// Retries are layered on top of the shared transport so each attempt is throttled and signed again,
+// the audit log records each call once with the status of its last attempt, or the dry run error
+func newSession(transport http.RoundTripper, retryPolicy RetryPolicy, signer *edgegrid.Config, userAgent string, dryRun bool, audit *AuditLog, log log.Interface) (session.Session, error) {
+	transport = NewRetryTransport(transport, retryPolicy, signer, log)
+	if dryRun {
+		transport = NewDryRunTransport(transport, log)
+	}
+	if audit != nil {
+		transport = NewAuditTransport(transport, audit, log)
+	}
+
+	return session.New(
+		session.WithClient(&http.Client{Transport: transport}),
+		session.WithSigner(signer),
+		session.WithUserAgent(userAgent),
+		session.WithLog(log),
+		session.WithHTTPTracing(cast.ToBool(os.Getenv("AKAMAI_HTTP_TRACE_ENABLED"))),
+	)
+}
+
+// NewToolSession returns an API session for the command line tools of the provider binary
+// The credentials are read from the AKAMAI_* environment variables or the edgerc section like the provider block does,
+// the requests are retried with the default policy and sent with the transport set by SetHTTPTransport, if any.
+func NewToolSession(edgerc, section string, log log.Interface) (session.Session, error) {
+	signer, _, err := newEdgegridConfigBuilder(nil, "", edgerc, section).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport
+	if instance != nil && instance.transport != nil {
+		transport = instance.transport
+	}
+
+	userAgent := fmt.Sprintf("%s/%s tools", ProviderName, version.ProviderVersion)
+	return newSession(transport, DefaultRetryPolicy, signer, userAgent, false, nil, log)
+}
+
+// configureCache returns the cache backend selected in the provider block and the default entry ttl
+func configureCache(d *schema.ResourceData) (CacheStore, time.Duration, error) {
+	ttl := DefaultCacheTTL
+	ttlValue, err := tools.GetStringValue("cache_ttl", d)
+	if err != nil && !errors.Is(err, tools.ErrNotFound) {
+		return nil, 0, err
+	}
+	if err == nil {
+		if ttl, err = time.ParseDuration(ttlValue); err != nil {
+			return nil, 0, fmt.Errorf("%w: cache_ttl: %s", ErrCacheInvalidConfig, err)
 		}
 	}
-	return nil
+
+	cacheType, err := tools.GetStringValue("cache_type", d)
+	if err != nil && !errors.Is(err, tools.ErrNotFound) {
+		return nil, 0, err
+	}
+	if cacheType != CacheTypeFile {
+		return instance.cache, ttl, nil
+	}
+
+	cachePath, err := tools.GetStringValue("cache_path", d)
+	if err != nil && !errors.Is(err, tools.ErrNotFound) {
+		return nil, 0, err
+	}
+	if cachePath == "" {
+		cachePath = DefaultCachePath()
+	}
+	cache, err := NewFileCache(cachePath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return cache, ttl, nil
 }
 
 func mergeSchema(from, to map[string]*schema.Schema) (map[string]*schema.Schema, error) {
@@ -0,0 +1,273 @@
+package akamai
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/time/rate"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+const (
+	// APIFamilyPAPI is the Property Manager API family
+	APIFamilyPAPI = "PAPI"
+
+	// APIFamilyDNS is the Edge DNS API family
+	APIFamilyDNS = "DNS"
+
+	// APIFamilyGTM is the Global Traffic Management API family
+	APIFamilyGTM = "GTM"
+
+	// APIFamilyAPPSEC is the Application Security API family
+	APIFamilyAPPSEC = "APPSEC"
+
+	// APIFamilyIAM is the Identity and Access Management API family
+	APIFamilyIAM = "IAM"
+)
+
+type (
+	// RateLimit holds the rate limiter settings for all or a single API family
+	RateLimit struct {
+		RequestsPerSecond float64
+		Burst             int
+	}
+
+	// RateLimitConfig is the client side throttling applied to every EdgeGrid request
+	RateLimitConfig struct {
+		Default     RateLimit
+		MaxInFlight int
+		APIs        map[string]RateLimit
+	}
+
+	rateLimitTransport struct {
+		next     http.RoundTripper
+		limiters map[string]*rate.Limiter
+		inFlight chan struct{}
+		log      log.Interface
+	}
+)
+
+var (
+	apiFamilies = []string{APIFamilyPAPI, APIFamilyDNS, APIFamilyGTM, APIFamilyAPPSEC, APIFamilyIAM}
+
+	apiFamilyPaths = map[string]string{
+		"/papi/":                APIFamilyPAPI,
+		"/config-dns/":          APIFamilyDNS,
+		"/config-gtm/":          APIFamilyGTM,
+		"/appsec/":              APIFamilyAPPSEC,
+		"/identity-management/": APIFamilyIAM,
+	}
+)
+
+// APIFamily returns the API family a request belongs to, or an empty string if it is unknown
+func APIFamily(r *http.Request) string {
+	for prefix, family := range apiFamilyPaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return family
+		}
+	}
+
+	return ""
+}
+
+// NewRateLimitTransport returns a http.RoundTripper which throttles the requests passed to next
+// Requests wait for a token of their API family limiter first, so a request waiting for a token does not keep another
+// from being sent, then for a free in-flight slot which is held until the response body is received
+func NewRateLimitTransport(next http.RoundTripper, cfg RateLimitConfig, log log.Interface) http.RoundTripper {
+	t := &rateLimitTransport{
+		next:     next,
+		limiters: make(map[string]*rate.Limiter),
+		log:      log,
+	}
+
+	if cfg.MaxInFlight > 0 {
+		t.inFlight = make(chan struct{}, cfg.MaxInFlight)
+	}
+	if limiter := newLimiter(cfg.Default); limiter != nil {
+		t.limiters[""] = limiter
+	}
+	// a family without a limit of its own uses the default one, a limit of 0 requests per second is unlimited
+	for family, limit := range cfg.APIs {
+		t.limiters[family] = newLimiter(limit)
+	}
+
+	return t
+}
+
+func newLimiter(limit RateLimit) *rate.Limiter {
+	if limit.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *rateLimitTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+	family := APIFamily(r)
+	start := time.Now()
+
+	limiter, ok := t.limiters[family]
+	if !ok {
+		limiter = t.limiters[""]
+	}
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.inFlight != nil {
+		select {
+		case t.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if wait := time.Since(start); wait > time.Millisecond {
+		t.log.WithFields(log.Fields{
+			"api":    family,
+			"method": r.Method,
+			"path":   r.URL.Path,
+			"wait":   wait.String(),
+		}).Debug("request delayed by rate limiter")
+	}
+
+	if t.inFlight == nil {
+		return t.next.RoundTrip(r)
+	}
+	defer func() { <-t.inFlight }()
+
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// The edgegrid clients do not close every response body, the slot is released once the body is read instead
+	// of when it is closed
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+func rateLimitSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Client side throttling applied to every request the provider sends to Akamai APIs",
+		Optional:    true,
+		Type:        schema.TypeList,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"requests_per_second": {
+					Description:  "The sustained number of requests per second, 0 means unlimited",
+					Optional:     true,
+					Type:         schema.TypeFloat,
+					ValidateFunc: validation.FloatAtLeast(0),
+				},
+				"burst": {
+					Description:  "The number of requests that can be sent at once before throttling starts",
+					Optional:     true,
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_in_flight": {
+					Description:  "The maximum number of concurrent requests, 0 means unlimited",
+					Optional:     true,
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"api": {
+					Description: "Overrides the limits for a single API family",
+					Optional:    true,
+					Type:        schema.TypeList,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Description:  "The API family, one of PAPI, DNS, GTM, APPSEC or IAM",
+								Required:     true,
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(apiFamilies, false),
+							},
+							"requests_per_second": {
+								Description:  "The sustained number of requests per second of the API family, 0 means unlimited",
+								Required:     true,
+								Type:         schema.TypeFloat,
+								ValidateFunc: validation.FloatAtLeast(0),
+							},
+							"burst": {
+								Optional:     true,
+								Type:         schema.TypeInt,
+								ValidateFunc: validation.IntAtLeast(0),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getRateLimitConfig reads the rate_limit block, ok is false if it was not configured
+func getRateLimitConfig(d tools.ResourceDataFetcher) (cfg RateLimitConfig, ok bool, err error) {
+	block, err := tools.GetListValue("rate_limit", d)
+	if err != nil {
+		if errors.Is(err, tools.ErrNotFound) {
+			return cfg, false, nil
+		}
+		return cfg, false, err
+	}
+	if len(block) == 0 || block[0] == nil {
+		return cfg, false, nil
+	}
+
+	m, ok := block[0].(map[string]interface{})
+	if !ok {
+		return cfg, false, fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "rate_limit", "map[string]interface{}")
+	}
+
+	cfg.Default = RateLimit{
+		RequestsPerSecond: m["requests_per_second"].(float64),
+		Burst:             m["burst"].(int),
+	}
+	cfg.MaxInFlight = m["max_in_flight"].(int)
+	cfg.APIs = make(map[string]RateLimit)
+
+	apis, _ := m["api"].([]interface{})
+	for _, a := range apis {
+		api, ok := a.(map[string]interface{})
+		if !ok {
+			return cfg, false, fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "rate_limit.api", "map[string]interface{}")
+		}
+		name := api["name"].(string)
+		if _, exists := cfg.APIs[name]; exists {
+			return cfg, false, fmt.Errorf("rate_limit: api %q is configured more than once", name)
+		}
+		cfg.APIs[name] = RateLimit{
+			RequestsPerSecond: api["requests_per_second"].(float64),
+			Burst:             api["burst"].(int),
+		}
+	}
+
+	return cfg, true, nil
+}
@@ -0,0 +1,191 @@
+package akamai
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIFamily(t *testing.T) {
+	tests := map[string]string{
+		"/papi/v1/properties":                   APIFamilyPAPI,
+		"/config-dns/v2/zones":                  APIFamilyDNS,
+		"/config-gtm/v1/domains":                APIFamilyGTM,
+		"/appsec/v1/configs":                    APIFamilyAPPSEC,
+		"/identity-management/v2/user-admin/ui": APIFamilyIAM,
+		"/ccu/v3/invalidate/url":                "",
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			assert.Equal(t, expected, APIFamily(req))
+		})
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	t.Run("max in flight", func(t *testing.T) {
+		var current, peak int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&current, 1)
+			defer atomic.AddInt32(&current, -1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+		}))
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRateLimitTransport(http.DefaultTransport, RateLimitConfig{MaxInFlight: 2}, Log())}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := client.Get(srv.URL + "/papi/v1/groups")
+				require.NoError(t, err)
+				resp.Body.Close()
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+	})
+
+	t.Run("per api limit", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRateLimitTransport(http.DefaultTransport, RateLimitConfig{
+			APIs: map[string]RateLimit{
+				APIFamilyDNS: {RequestsPerSecond: 20, Burst: 1},
+			},
+		}, Log())}
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			resp, err := client.Get(srv.URL + "/config-dns/v2/zones")
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(90*time.Millisecond))
+
+		start = time.Now()
+		for i := 0; i < 3; i++ {
+			resp, err := client.Get(srv.URL + "/papi/v1/groups")
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+		assert.Less(t, int64(time.Since(start)), int64(90*time.Millisecond))
+	})
+
+	t.Run("per api zero is unlimited", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRateLimitTransport(http.DefaultTransport, RateLimitConfig{
+			Default: RateLimit{RequestsPerSecond: 20, Burst: 1},
+			APIs: map[string]RateLimit{
+				APIFamilyDNS: {RequestsPerSecond: 0},
+			},
+		}, Log())}
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			resp, err := client.Get(srv.URL + "/config-dns/v2/zones")
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+		assert.Less(t, int64(time.Since(start)), int64(90*time.Millisecond))
+
+		start = time.Now()
+		for i := 0; i < 3; i++ {
+			resp, err := client.Get(srv.URL + "/papi/v1/groups")
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(90*time.Millisecond))
+	})
+
+	t.Run("slot held until the body is received", func(t *testing.T) {
+		var current, peak int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&current, 1)
+			defer atomic.AddInt32(&current, -1)
+			if n > atomic.LoadInt32(&peak) {
+				atomic.StoreInt32(&peak, n)
+			}
+			// the headers are sent at once, the body is still being sent when the client receives them
+			_, _ = w.Write([]byte("{"))
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+			_, _ = w.Write([]byte("}"))
+		}))
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRateLimitTransport(http.DefaultTransport, RateLimitConfig{MaxInFlight: 1}, Log())}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// the body is not closed, like the edgegrid session does after reading it
+				resp, err := client.Get(srv.URL + "/papi/v1/groups")
+				require.NoError(t, err)
+				body, err := ioutil.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, "{}", string(body))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&peak))
+	})
+
+	t.Run("token before slot", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRateLimitTransport(http.DefaultTransport, RateLimitConfig{
+			MaxInFlight: 1,
+			APIs: map[string]RateLimit{
+				APIFamilyDNS: {RequestsPerSecond: 2, Burst: 1},
+			},
+		}, Log())}
+
+		resp, err := client.Get(srv.URL + "/config-dns/v2/zones")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		// the second DNS request waits for a token without holding the only slot
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			resp, err := client.Get(srv.URL + "/config-dns/v2/zones")
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+		time.Sleep(20 * time.Millisecond)
+
+		start := time.Now()
+		resp, err = client.Get(srv.URL + "/papi/v1/groups")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Less(t, int64(time.Since(start)), int64(200*time.Millisecond))
+		<-done
+	})
+}
@@ -0,0 +1,281 @@
+package akamai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/apex/log"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+type (
+	// RetryPolicy describes how requests failing with a transient error are retried
+	RetryPolicy struct {
+		// MaxAttempts is the total number of attempts including the first one, 1 disables retries
+		MaxAttempts int
+
+		// BaseBackoff is the wait before the first retry, it doubles with every attempt
+		BaseBackoff time.Duration
+
+		// MaxBackoff caps the wait between two attempts, including waits requested with Retry-After
+		MaxBackoff time.Duration
+
+		// Jitter randomizes each wait between half and the full backoff
+		Jitter bool
+	}
+
+	retryTransport struct {
+		next   http.RoundTripper
+		policy RetryPolicy
+		signer edgegrid.Signer
+		log    log.Interface
+	}
+)
+
+var (
+	// DefaultRetryPolicy is the retry policy used when the provider block has no retry settings
+	DefaultRetryPolicy = RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: time.Second,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      true,
+	}
+)
+
+// Backoff returns the wait before the given retry attempt, attempts start at 1
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	backoff := float64(p.BaseBackoff) * math.Pow(2, float64(attempt-1))
+	if backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter {
+		backoff = backoff/2 + rand.Float64()*backoff/2
+	}
+
+	return time.Duration(backoff)
+}
+
+// IsRetryable tells whether a response can be retried
+// Requests which may not be idempotent are only retried on 429, as the API did not process them
+func IsRetryable(r *http.Request, resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// NewRetryTransport returns a http.RoundTripper which retries the requests failing with 429 or 5xx
+// Each retry is signed again with signer, as EdgeGrid signatures cannot be replayed
+func NewRetryTransport(next http.RoundTripper, policy RetryPolicy, signer edgegrid.Signer, log log.Interface) http.RoundTripper {
+	return &retryTransport{
+		next:   next,
+		policy: policy,
+		signer: signer,
+		log:    log,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *retryTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if t.policy.MaxAttempts <= 1 {
+		return t.next.RoundTrip(r)
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(r.Body); err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+	}
+
+	req := r
+	for attempt := 1; ; attempt++ {
+		if body != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || attempt >= t.policy.MaxAttempts || !IsRetryable(req, resp) {
+			return resp, err
+		}
+
+		wait := t.policy.Backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(resp); ok && retryAfter > wait {
+			wait = retryAfter
+		}
+		if wait > t.policy.MaxBackoff {
+			wait = t.policy.MaxBackoff
+		}
+
+		t.log.WithFields(log.Fields{
+			"api":     APIFamily(req),
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"status":  resp.StatusCode,
+			"attempt": attempt,
+			"wait":    wait.String(),
+		}).Debug("retrying request")
+
+		// drain the body so the connection can be reused
+		_, _ = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		req = t.resign(r, body)
+	}
+}
+
+// resign clones the original request with a new signature
+func (t *retryTransport) resign(r *http.Request, body []byte) *http.Request {
+	req := r.Clone(r.Context())
+	req.Header.Del("Authorization")
+
+	// the signer appends the account switch key again, drop the one added by the previous signature
+	if config, ok := t.signer.(*edgegrid.Config); ok && config.AccountKey != "" {
+		query := req.URL.Query()
+		query.Del("accountSwitchKey")
+		req.URL.RawQuery = query.Encode()
+	}
+	if body != nil {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	t.signer.SignRequest(req)
+
+	return req
+}
+
+// parseRetryAfter reads the Retry-After header, which may hold seconds or an http date
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func retrySchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Retry policy for requests failing with 429 or 5xx responses",
+		Optional:    true,
+		Type:        schema.TypeList,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"max_attempts": {
+					Description:  "The total number of attempts including the first one, 1 disables retries",
+					Optional:     true,
+					Type:         schema.TypeInt,
+					Default:      DefaultRetryPolicy.MaxAttempts,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"base_backoff": {
+					Description:      "The wait before the first retry, it doubles with every attempt",
+					Optional:         true,
+					Type:             schema.TypeString,
+					Default:          DefaultRetryPolicy.BaseBackoff.String(),
+					ValidateDiagFunc: tools.ValidateDuration(0),
+				},
+				"max_backoff": {
+					Description:      "The maximum wait between two attempts",
+					Optional:         true,
+					Type:             schema.TypeString,
+					Default:          DefaultRetryPolicy.MaxBackoff.String(),
+					ValidateDiagFunc: tools.ValidateDuration(0),
+				},
+				"jitter": {
+					Description: "Whether each wait is randomized to spread retries of concurrent requests",
+					Optional:    true,
+					Type:        schema.TypeBool,
+					Default:     DefaultRetryPolicy.Jitter,
+				},
+			},
+		},
+	}
+}
+
+// getRetryPolicy reads the retry block, DefaultRetryPolicy is returned if it is not configured
+func getRetryPolicy(d tools.ResourceDataFetcher) (RetryPolicy, error) {
+	policy := DefaultRetryPolicy
+
+	block, err := tools.GetListValue("retry", d)
+	if err != nil {
+		if errors.Is(err, tools.ErrNotFound) {
+			return policy, nil
+		}
+		return policy, err
+	}
+	if len(block) == 0 || block[0] == nil {
+		return policy, nil
+	}
+
+	m, ok := block[0].(map[string]interface{})
+	if !ok {
+		return policy, fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "retry", "map[string]interface{}")
+	}
+
+	policy.MaxAttempts = m["max_attempts"].(int)
+	policy.Jitter = m["jitter"].(bool)
+	if policy.BaseBackoff, err = time.ParseDuration(m["base_backoff"].(string)); err != nil {
+		return policy, fmt.Errorf("retry: base_backoff: %w", err)
+	}
+	if policy.MaxBackoff, err = time.ParseDuration(m["max_backoff"].(string)); err != nil {
+		return policy, fmt.Errorf("retry: max_backoff: %w", err)
+	}
+	if policy.MaxBackoff < policy.BaseBackoff {
+		return policy, fmt.Errorf("retry: max_backoff must not be lower than base_backoff")
+	}
+
+	return policy, nil
+}
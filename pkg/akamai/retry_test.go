@@ -0,0 +1,119 @@
+package akamai
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 800*time.Millisecond, policy.Backoff(4))
+	assert.Equal(t, time.Second, policy.Backoff(10))
+
+	policy.Jitter = true
+	for i := 0; i < 20; i++ {
+		backoff := policy.Backoff(2)
+		assert.True(t, backoff >= 100*time.Millisecond && backoff <= 200*time.Millisecond, backoff.String())
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := map[string]struct {
+		method   string
+		status   int
+		expected bool
+	}{
+		"GET 503":     {http.MethodGet, http.StatusServiceUnavailable, true},
+		"PUT 500":     {http.MethodPut, http.StatusInternalServerError, true},
+		"GET 429":     {http.MethodGet, http.StatusTooManyRequests, true},
+		"POST 429":    {http.MethodPost, http.StatusTooManyRequests, true},
+		"POST 503":    {http.MethodPost, http.StatusServiceUnavailable, false},
+		"GET 404":     {http.MethodGet, http.StatusNotFound, false},
+		"DELETE 501":  {http.MethodDelete, http.StatusNotImplemented, false},
+		"DELETE 504":  {http.MethodDelete, http.StatusGatewayTimeout, true},
+		"PATCH 502":   {http.MethodPatch, http.StatusBadGateway, false},
+		"GET 200":     {http.MethodGet, http.StatusOK, false},
+		"HEAD 502":    {http.MethodHead, http.StatusBadGateway, true},
+		"OPTIONS 409": {http.MethodOptions, http.StatusConflict, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/papi/v1/groups", nil)
+			assert.Equal(t, test.expected, IsRetryable(req, &http.Response{StatusCode: test.status}))
+		})
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	var calls int
+	var bodies, signatures []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		signatures = append(signatures, r.Header.Get("Authorization"))
+		assert.Equal(t, []string{"1-5C0YLB"}, r.URL.Query()["accountSwitchKey"])
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	signer := &edgegrid.Config{
+		ClientToken:  "client_token",
+		ClientSecret: "client_secret",
+		AccessToken:  "access_token",
+		AccountKey:   "1-5C0YLB",
+		MaxBody:      edgegrid.MaxBodySize,
+	}
+	policy := RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, policy, signer, Log())}
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/papi/v1/cpcodes", strings.NewReader(`{"cpcodeName":"test"}`))
+	require.NoError(t, err)
+	signer.SignRequest(req)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []string{`{"cpcodeName":"test"}`, `{"cpcodeName":"test"}`, `{"cpcodeName":"test"}`}, bodies)
+	assert.NotEqual(t, signatures[0], signatures[1])
+	assert.NotEqual(t, signatures[1], signatures[2])
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	_, ok := parseRetryAfter(resp)
+	assert.False(t, ok)
+
+	resp.Header.Set("Retry-After", "7")
+	wait, ok := parseRetryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, wait)
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	wait, ok = parseRetryAfter(resp)
+	assert.True(t, ok)
+	assert.True(t, wait > 50*time.Second && wait <= time.Minute, wait.String())
+}
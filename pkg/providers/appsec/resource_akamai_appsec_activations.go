@@ -23,6 +23,10 @@ func resourceActivations() *schema.Resource {
 		CreateContext: resourceActivationsCreate,
 		ReadContext:   resourceActivationsRead,
 		DeleteContext: resourceActivationsDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: &ActivationTimeout,
+			Delete: &ActivationTimeout,
+		},
 		Schema: map[string]*schema.Schema{
 			"config_id": {
 				Type:     schema.TypeInt,
@@ -58,6 +62,12 @@ func resourceActivations() *schema.Resource {
 				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"poll_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: tools.ValidateDuration(ActivationPollMinimum),
+				Description:      "How often the activation status is checked, like 30s or 2m",
+			},
 			"status": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -68,12 +78,15 @@ func resourceActivations() *schema.Resource {
 
 const (
 	// ActivationPollMinimum is the minumum polling interval for activation creation
-	ActivationPollMinimum = time.Minute
+	ActivationPollMinimum = 10 * time.Second
 )
 
 var (
-	// ActivationPollInterval is the interval for polling an activation status on creation
-	ActivationPollInterval = ActivationPollMinimum
+	// ActivationPollInterval is the interval for polling an activation status when the resource sets no poll_interval
+	ActivationPollInterval = time.Minute
+
+	// ActivationTimeout is the default timeout for activations and deactivations
+	ActivationTimeout = time.Minute * 90
 )
 
 func resourceActivationsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -135,7 +148,7 @@ func resourceActivationsCreate(ctx context.Context, d *schema.ResourceData, m in
 	activation, err := lookupActivation(ctx, client, createActivationsreq)
 	for activation.Status != appsec.StatusActive {
 		select {
-		case <-time.After(tools.MaxDuration(ActivationPollInterval, ActivationPollMinimum)):
+		case <-time.After(tools.PollInterval(d, ActivationPollInterval, ActivationPollMinimum)):
 			act, err := client.GetActivations(ctx, createActivationsreq)
 
 			if err != nil {
@@ -224,7 +237,7 @@ func resourceActivationsDelete(ctx context.Context, d *schema.ResourceData, m in
 	activation, err := lookupActivation(ctx, client, createActivationsreq)
 	for activation.Status != appsec.StatusDeactivated {
 		select {
-		case <-time.After(tools.MaxDuration(ActivationPollInterval, ActivationPollMinimum)):
+		case <-time.After(tools.PollInterval(d, ActivationPollInterval, ActivationPollMinimum)):
 			act, err := client.GetActivations(ctx, createActivationsreq)
 
 			if err != nil {
@@ -2,6 +2,7 @@ package gtm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 var testAccProviders map[string]*schema.Provider
@@ -40,6 +42,26 @@ func getTestProvider() *schema.Provider {
 	return testProvider
 }
 
+// testMeta configures the test provider with dummy credentials and returns its meta, for the tests calling the
+// resource functions directly
+func testMeta(t *testing.T) interface{} {
+	t.Helper()
+
+	cfg := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"config": []interface{}{map[string]interface{}{
+			"host":          "akaa-test.luna.akamaiapis.net",
+			"access_token":  "akab-access",
+			"client_token":  "akab-client",
+			"client_secret": "secret",
+		}},
+	})
+	if diags := testProvider.Configure(context.Background(), cfg); diags.HasError() {
+		t.Fatalf("could not configure provider: %v", diags)
+	}
+
+	return testProvider.Meta()
+}
+
 // Only allow one test at a time to patch the client via useClient()
 var clientLock sync.Mutex
 
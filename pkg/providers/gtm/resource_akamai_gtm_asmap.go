@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/configgtm"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
@@ -23,6 +24,11 @@ func resourceGTMv1ASmap() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceGTMv1ASmapImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: &DomainTimeout,
+			Update: &DomainTimeout,
+			Delete: &DomainTimeout,
+		},
 		Schema: map[string]*schema.Schema{
 			"domain": {
 				Type:     schema.TypeString,
@@ -33,6 +39,12 @@ func resourceGTMv1ASmap() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"poll_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: tools.ValidateDuration(time.Second),
+				Description:      "How often the propagation status is checked while waiting on completion, like 10s",
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -182,7 +194,7 @@ func resourceGTMv1ASmapCreate(ctx context.Context, d *schema.ResourceData, m int
 		return diag.FromErr(err)
 	}
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutCreate))
 		if done {
 			logger.Infof("asMap Create completed")
 		} else {
@@ -291,7 +303,7 @@ func resourceGTMv1ASmapUpdate(ctx context.Context, d *schema.ResourceData, m int
 		return diag.FromErr(err)
 	}
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutUpdate))
 		if done {
 			logger.Infof("ASmap Update completed")
 		} else {
@@ -395,7 +407,7 @@ func resourceGTMv1ASmapDelete(ctx context.Context, d *schema.ResourceData, m int
 		return diag.FromErr(err)
 	}
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutDelete))
 		if done {
 			logger.Infof("asMap Delete completed")
 		} else {
@@ -3,6 +3,7 @@ package gtm
 import (
 	"context"
 	"fmt"
+	"time"
 
 	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/configgtm"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
@@ -22,6 +23,11 @@ func resourceGTMv1Cidrmap() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceGTMv1CidrMapImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: &DomainTimeout,
+			Update: &DomainTimeout,
+			Delete: &DomainTimeout,
+		},
 		Schema: map[string]*schema.Schema{
 			"domain": {
 				Type:     schema.TypeString,
@@ -32,6 +38,12 @@ func resourceGTMv1Cidrmap() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"poll_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: tools.ValidateDuration(time.Second),
+				Description:      "How often the propagation status is checked while waiting on completion, like 10s",
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -136,7 +148,7 @@ func resourceGTMv1CidrMapCreate(ctx context.Context, d *schema.ResourceData, m i
 		return diag.FromErr(err)
 	} else {
 		if waitOnComplete {
-			done, err := waitForCompletion(ctx, domain, m)
+			done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutCreate))
 			if done {
 				logger.Infof("cidrMap Create completed")
 			} else {
@@ -246,7 +258,7 @@ func resourceGTMv1CidrMapUpdate(ctx context.Context, d *schema.ResourceData, m i
 		return diag.FromErr(err)
 	} else {
 		if waitOnComplete {
-			done, err := waitForCompletion(ctx, domain, m)
+			done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutUpdate))
 			if done {
 				logger.Infof("cidrMap Update completed")
 			} else {
@@ -350,7 +362,7 @@ func resourceGTMv1CidrMapDelete(ctx context.Context, d *schema.ResourceData, m i
 		return diag.FromErr(err)
 	} else {
 		if waitOnComplete {
-			done, err := waitForCompletion(ctx, domain, m)
+			done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutDelete))
 			if done {
 				logger.Infof("CidrMap Delete completed")
 			} else {
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
 	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
@@ -27,6 +28,11 @@ func resourceGTMv1Datacenter() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceGTMv1DatacenterImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: &DomainTimeout,
+			Update: &DomainTimeout,
+			Delete: &DomainTimeout,
+		},
 		Schema: map[string]*schema.Schema{
 			"domain": {
 				Type:     schema.TypeString,
@@ -37,6 +43,12 @@ func resourceGTMv1Datacenter() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"poll_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: tools.ValidateDuration(time.Second),
+				Description:      "How often the propagation status is checked while waiting on completion, like 10s",
+			},
 			"nickname": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -212,7 +224,7 @@ func resourceGTMv1DatacenterCreate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutCreate))
 		if done {
 			logger.Infof("Datacenter Create completed")
 		} else {
@@ -332,7 +344,7 @@ func resourceGTMv1DatacenterUpdate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutUpdate))
 		if done {
 			logger.Infof("Datacenter Update completed")
 		} else {
@@ -437,7 +449,7 @@ func resourceGTMv1DatacenterDelete(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutDelete))
 		if done {
 			logger.Infof("Datacenter Delete completed")
 		} else {
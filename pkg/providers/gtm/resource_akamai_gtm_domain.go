@@ -21,6 +21,20 @@ import (
 // Hack for Hashicorp Acceptance Tests
 var HashiAcc = false
 
+var (
+	// DomainPollInterval is the interval for polling the propagation status when the resource sets no poll_interval
+	DomainPollInterval = 5 * time.Second
+
+	// DomainTimeout is the default timeout of the operations of the domain and its child resources
+	DomainTimeout = 20 * time.Minute
+
+	// DomainCompletionWait is how long a change waits for its propagation when the resource keeps the default timeouts
+	DomainCompletionWait = 5 * time.Minute
+)
+
+// completionReadMargin is the time kept after waiting on completion to read the resource
+const completionReadMargin = 30 * time.Second
+
 func resourceGTMv1Domain() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceGTMv1DomainCreate,
@@ -30,6 +44,11 @@ func resourceGTMv1Domain() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: &DomainTimeout,
+			Update: &DomainTimeout,
+			Delete: &DomainTimeout,
+		},
 		Schema: map[string]*schema.Schema{
 			"contract": {
 				Type:     schema.TypeString,
@@ -46,6 +65,12 @@ func resourceGTMv1Domain() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"poll_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: tools.ValidateDuration(time.Second),
+				Description:      "How often the propagation status is checked while waiting on completion, like 10s",
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -281,7 +306,7 @@ func resourceGTMv1DomainCreate(ctx context.Context, d *schema.ResourceData, m in
 		}
 
 		if waitOnComplete {
-			done, err := waitForCompletion(ctx, dname, m)
+			done, err := waitForCompletion(ctx, dname, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutCreate))
 			if done {
 				logger.Infof("Domain Create completed")
 			} else {
@@ -394,7 +419,7 @@ func resourceGTMv1DomainUpdate(ctx context.Context, d *schema.ResourceData, m in
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, d.Id(), m)
+		done, err := waitForCompletion(ctx, d.Id(), m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutUpdate))
 		if done {
 			logger.Infof("Domain Update completed")
 		} else {
@@ -484,7 +509,7 @@ func resourceGTMv1DomainDelete(ctx context.Context, d *schema.ResourceData, m in
 		}
 
 		if waitOnComplete {
-			done, err := waitForCompletion(ctx, d.Id(), m)
+			done, err := waitForCompletion(ctx, d.Id(), m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutDelete))
 			if done {
 				logger.Infof("Domain Delete completed")
 			} else {
@@ -749,20 +774,22 @@ func populateTerraformState(d *schema.ResourceData, dom *gtm.Domain, m interface
 }
 
 // Util function to wait for change deployment. return true if complete. false if not - error or nil (timeout)
-func waitForCompletion(ctx context.Context, domain string, m interface{}) (bool, error) {
+// The change is pending when the operation timeout approaches, see the timeouts of the resources.
+func waitForCompletion(ctx context.Context, domain string, m interface{}, interval, wait time.Duration) (bool, error) {
 	meta := akamai.Meta(m)
 	logger := meta.Log("Akamai GTMv1", "waitForCompletion")
 
-	var defaultInterval = 5 * time.Second
-	var defaultTimeout = 300 * time.Second
-	var sleepInterval = defaultInterval // seconds. TODO:Should be configurable by user ...
-	var sleepTimeout = defaultTimeout   // seconds. TODO: Should be configurable by user ...
+	deadline := time.Now().Add(wait)
+	// leave time to read the resource before the operation times out
+	if opDeadline, ok := ctx.Deadline(); ok && opDeadline.Add(-interval-completionReadMargin).Before(deadline) {
+		deadline = opDeadline.Add(-interval - completionReadMargin)
+	}
 	if HashiAcc {
 		// Override for ACC tests
-		sleepTimeout = sleepInterval
+		deadline = time.Now().Add(interval)
 	}
-	logger.Debugf("WAIT: Sleep Interval [%v]", sleepInterval/time.Second)
-	logger.Debugf("WAIT: Sleep Timeout [%v]", sleepTimeout/time.Second)
+	logger.Debugf("WAIT: Sleep Interval [%v]", interval)
+	logger.Debugf("WAIT: Deadline [%v]", deadline)
 	for {
 		propStat, err := inst.Client(meta).GetDomainStatus(ctx, domain)
 		if err != nil {
@@ -777,15 +804,28 @@ func waitForCompletion(ctx context.Context, domain string, m interface{}) (bool,
 			logger.Debugf("WAIT: Return DENIED")
 			return false, fmt.Errorf(propStat.Message)
 		case "PENDING":
-			if sleepTimeout <= 0 {
+			if !time.Now().Before(deadline) {
 				logger.Debugf("WAIT: Return TIMED OUT")
 				return false, nil
 			}
-			time.Sleep(sleepInterval)
-			sleepTimeout -= sleepInterval
-			logger.Debugf("WAIT: Sleep Time Remaining [%v]", sleepTimeout/time.Second)
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return false, ctx.Err()
+			}
+			logger.Debugf("WAIT: Sleep Time Remaining [%v]", time.Until(deadline).Round(time.Second))
 		default:
 			return false, fmt.Errorf("unknown propagationStatus while waiting for change completion") // don't know how/why we would have broken out.
 		}
 	}
 }
+
+// completionWait returns how long an operation waits for the propagation of its change. Timeouts raised above the
+// default extend the wait up to the timeout of the operation.
+func completionWait(d *schema.ResourceData, key string) time.Duration {
+	if timeout := d.Timeout(key); timeout > DomainTimeout {
+		return timeout
+	}
+
+	return DomainCompletionWait
+}
@@ -1,14 +1,19 @@
 package gtm
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"regexp"
 	"testing"
+	"time"
 
 	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/configgtm"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var gtmTestDomain = "gtm_terra_testdomain.akadns.net"
@@ -355,3 +360,60 @@ func testAccPreCheckTF(_ *testing.T) {
 	HashiAcc = true
 
 }
+
+func TestWaitForCompletion(t *testing.T) {
+	meta := testMeta(t)
+	pending := &gtm.ResponseStatus{PropagationStatus: "PENDING"}
+	complete := &gtm.ResponseStatus{PropagationStatus: "COMPLETE"}
+
+	t.Run("complete", func(t *testing.T) {
+		client := &mockgtm{}
+		client.On("GetDomainStatus", mock.Anything, gtmTestDomain).Return(pending, nil).Once()
+		client.On("GetDomainStatus", mock.Anything, gtmTestDomain).Return(complete, nil).Once()
+
+		useClient(client, func() {
+			done, err := waitForCompletion(context.Background(), gtmTestDomain, meta, time.Millisecond, DomainCompletionWait)
+			require.NoError(t, err)
+			assert.True(t, done)
+		})
+		client.AssertExpectations(t)
+	})
+
+	t.Run("wait elapsed", func(t *testing.T) {
+		client := &mockgtm{}
+		client.On("GetDomainStatus", mock.Anything, gtmTestDomain).Return(pending, nil)
+
+		useClient(client, func() {
+			done, err := waitForCompletion(context.Background(), gtmTestDomain, meta, time.Millisecond, 20*time.Millisecond)
+			require.NoError(t, err)
+			assert.False(t, done)
+		})
+	})
+
+	t.Run("time kept to read the resource", func(t *testing.T) {
+		client := &mockgtm{}
+		client.On("GetDomainStatus", mock.Anything, gtmTestDomain).Return(pending, nil).Once()
+
+		// the operation times out before the wait, it stops at once to leave the time to read the resource
+		ctx, cancel := context.WithTimeout(context.Background(), completionReadMargin)
+		defer cancel()
+		useClient(client, func() {
+			done, err := waitForCompletion(ctx, gtmTestDomain, meta, time.Second, DomainCompletionWait)
+			require.NoError(t, err)
+			assert.False(t, done)
+		})
+		client.AssertExpectations(t)
+	})
+}
+
+func TestCompletionWait(t *testing.T) {
+	res := resourceGTMv1Domain()
+	assert.Equal(t, DomainCompletionWait, completionWait(res.Data(nil), schema.TimeoutCreate))
+
+	// the timeouts of the configuration replace the defaults of the resource
+	raised := time.Hour
+	res.Timeouts = &schema.ResourceTimeout{Create: &raised, Update: &DomainTimeout}
+	d := res.Data(nil)
+	assert.Equal(t, time.Hour, completionWait(d, schema.TimeoutCreate))
+	assert.Equal(t, DomainCompletionWait, completionWait(d, schema.TimeoutUpdate))
+}
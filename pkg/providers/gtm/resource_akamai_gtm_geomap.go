@@ -3,6 +3,7 @@ package gtm
 import (
 	"context"
 	"fmt"
+	"time"
 
 	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/configgtm"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
@@ -22,6 +23,11 @@ func resourceGTMv1Geomap() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceGTMv1GeomapImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: &DomainTimeout,
+			Update: &DomainTimeout,
+			Delete: &DomainTimeout,
+		},
 		Schema: map[string]*schema.Schema{
 			"domain": {
 				Type:     schema.TypeString,
@@ -32,6 +38,12 @@ func resourceGTMv1Geomap() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"poll_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: tools.ValidateDuration(time.Second),
+				Description:      "How often the propagation status is checked while waiting on completion, like 10s",
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -141,7 +153,7 @@ func resourceGTMv1GeomapCreate(ctx context.Context, d *schema.ResourceData, m in
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutCreate))
 		if done {
 			logger.Infof("geoMap Create completed")
 		} else {
@@ -254,7 +266,7 @@ func resourceGTMv1GeomapUpdate(ctx context.Context, d *schema.ResourceData, m in
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutUpdate))
 		if done {
 			logger.Infof("geoMap Update completed")
 		} else {
@@ -362,7 +374,7 @@ func resourceGTMv1GeomapDelete(ctx context.Context, d *schema.ResourceData, m in
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutDelete))
 		if done {
 			logger.Infof("geoMap Delete completed")
 		} else {
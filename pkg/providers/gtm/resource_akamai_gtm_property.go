@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
 	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
@@ -24,6 +25,11 @@ func resourceGTMv1Property() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceGTMv1PropertyImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: &DomainTimeout,
+			Update: &DomainTimeout,
+			Delete: &DomainTimeout,
+		},
 		Schema: map[string]*schema.Schema{
 			"domain": {
 				Type:     schema.TypeString,
@@ -34,6 +40,12 @@ func resourceGTMv1Property() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"poll_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: tools.ValidateDuration(time.Second),
+				Description:      "How often the propagation status is checked while waiting on completion, like 10s",
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -388,7 +400,7 @@ func resourceGTMv1PropertyCreate(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutCreate))
 		if done {
 			logger.Infof("Property Create completed")
 		} else {
@@ -481,7 +493,7 @@ func resourceGTMv1PropertyUpdate(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutUpdate))
 		if done {
 			logger.Infof("Property Update completed")
 		} else {
@@ -569,7 +581,7 @@ func resourceGTMv1PropertyDelete(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	if waitOnComplete {
-		done, err := waitForCompletion(ctx, domain, m)
+		done, err := waitForCompletion(ctx, domain, m, tools.PollInterval(d, DomainPollInterval, 0), completionWait(d, schema.TimeoutDelete))
 		if done {
 			logger.Infof("Property Delete completed")
 		} else {
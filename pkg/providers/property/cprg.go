@@ -0,0 +1,164 @@
+package property
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+)
+
+// CP Codes and Reporting Groups
+//
+// The edgegrid library has no client for the CP Codes and Reporting Groups API (CPRG), which renames the CP codes PAPI
+// creates and manages the reporting groups that aggregate their traffic reports. Its calls go through the session.
+// CPRG identifies CP codes and groups by number and contracts without the ctr_ prefix.
+//
+// https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html
+
+type (
+	// cprg is the subset of the CP Codes and Reporting Groups API used by akamai_cp_code and akamai_reporting_group
+	cprg interface {
+		// GetCPCode returns the CP code
+		GetCPCode(ctx context.Context, cpCodeID int) (*cprgCPCode, error)
+
+		// UpdateCPCode replaces the editable attributes of the CP code
+		UpdateCPCode(ctx context.Context, cpCode cprgCPCode) (*cprgCPCode, error)
+
+		// CreateReportingGroup creates a reporting group
+		CreateReportingGroup(ctx context.Context, group cprgReportingGroup) (*cprgReportingGroup, error)
+
+		// GetReportingGroup returns the reporting group
+		GetReportingGroup(ctx context.Context, reportingGroupID int) (*cprgReportingGroup, error)
+
+		// UpdateReportingGroup replaces the name and CP codes of the reporting group
+		UpdateReportingGroup(ctx context.Context, group cprgReportingGroup) (*cprgReportingGroup, error)
+
+		// DeleteReportingGroup deletes the reporting group, its CP codes are not changed
+		DeleteReportingGroup(ctx context.Context, reportingGroupID int) error
+	}
+
+	cprgClient struct {
+		session.Session
+	}
+
+	cprgCPCode struct {
+		CPCodeID         int               `json:"cpcodeId"`
+		CPCodeName       string            `json:"cpcodeName"`
+		Purgeable        bool              `json:"purgeable"`
+		AccountID        string            `json:"accountId,omitempty"`
+		DefaultTimeZone  string            `json:"defaultTimeZone,omitempty"`
+		OverrideTimeZone *cprgTimeZone     `json:"overrideTimeZone,omitempty"`
+		Type             string            `json:"type,omitempty"`
+		Contracts        []cprgContract    `json:"contracts"`
+		Products         []cprgProduct     `json:"products"`
+		ReportingGroups  []cprgGroupMember `json:"reportingGroups,omitempty"`
+	}
+
+	cprgTimeZone struct {
+		TimeZoneID    string `json:"timeZoneId"`
+		TimeZoneValue string `json:"timeZoneValue,omitempty"`
+	}
+
+	cprgContract struct {
+		ContractID string `json:"contractId"`
+		Status     string `json:"status,omitempty"`
+	}
+
+	cprgProduct struct {
+		ProductID   string `json:"productId"`
+		ProductName string `json:"productName,omitempty"`
+	}
+
+	cprgGroupMember struct {
+		ReportingGroupID   int    `json:"reportingGroupId"`
+		ReportingGroupName string `json:"reportingGroupName"`
+	}
+
+	cprgReportingGroup struct {
+		ReportingGroupID   int                          `json:"reportingGroupId,omitempty"`
+		ReportingGroupName string                       `json:"reportingGroupName"`
+		Contracts          []cprgReportingGroupContract `json:"contracts"`
+		AccessGroup        cprgAccessGroup              `json:"accessGroup"`
+	}
+
+	cprgReportingGroupContract struct {
+		ContractID string                     `json:"contractId"`
+		CPCodes    []cprgReportingGroupCPCode `json:"cpcodes"`
+	}
+
+	cprgReportingGroupCPCode struct {
+		CPCodeID   int    `json:"cpcodeId"`
+		CPCodeName string `json:"cpcodeName,omitempty"`
+	}
+
+	cprgAccessGroup struct {
+		GroupID    int    `json:"groupId"`
+		ContractID string `json:"contractId"`
+	}
+)
+
+func newCPRGClient(sess session.Session) cprg {
+	return &cprgClient{Session: sess}
+}
+
+func (c *cprgClient) GetCPCode(ctx context.Context, cpCodeID int) (*cprgCPCode, error) {
+	var rval cprgCPCode
+	if err := c.exec(ctx, http.MethodGet, fmt.Sprintf("/cprg/v1/cpcodes/%d", cpCodeID), nil, &rval, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &rval, nil
+}
+
+func (c *cprgClient) UpdateCPCode(ctx context.Context, cpCode cprgCPCode) (*cprgCPCode, error) {
+	var rval cprgCPCode
+	if err := c.exec(ctx, http.MethodPut, fmt.Sprintf("/cprg/v1/cpcodes/%d", cpCode.CPCodeID), cpCode, &rval, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &rval, nil
+}
+
+func (c *cprgClient) CreateReportingGroup(ctx context.Context, group cprgReportingGroup) (*cprgReportingGroup, error) {
+	var rval cprgReportingGroup
+	if err := c.exec(ctx, http.MethodPost, "/cprg/v1/reporting-groups", group, &rval, http.StatusCreated); err != nil {
+		return nil, err
+	}
+
+	return &rval, nil
+}
+
+func (c *cprgClient) GetReportingGroup(ctx context.Context, reportingGroupID int) (*cprgReportingGroup, error) {
+	var rval cprgReportingGroup
+	if err := c.exec(ctx, http.MethodGet, cprgReportingGroupPath(reportingGroupID), nil, &rval, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &rval, nil
+}
+
+func (c *cprgClient) UpdateReportingGroup(ctx context.Context, group cprgReportingGroup) (*cprgReportingGroup, error) {
+	var rval cprgReportingGroup
+	if err := c.exec(ctx, http.MethodPut, cprgReportingGroupPath(group.ReportingGroupID), group, &rval, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &rval, nil
+}
+
+func (c *cprgClient) DeleteReportingGroup(ctx context.Context, reportingGroupID int) error {
+	return c.exec(ctx, http.MethodDelete, cprgReportingGroupPath(reportingGroupID), nil, nil, http.StatusNoContent)
+}
+
+func (c *cprgClient) exec(ctx context.Context, method, path string, in, out interface{}, expected int) error {
+	var contentType string
+	if in != nil {
+		contentType = "application/json"
+	}
+	return execAPI(ctx, c.Session, ErrCPRG, method, path, contentType, in, out, expected)
+}
+
+func cprgReportingGroupPath(reportingGroupID int) string {
+	return fmt.Sprintf("/cprg/v1/reporting-groups/%d", reportingGroupID)
+}
@@ -0,0 +1,98 @@
+package property
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockcprg struct {
+	mock.Mock
+}
+
+func (c *mockcprg) GetCPCode(ctx context.Context, cpCodeID int) (*cprgCPCode, error) {
+	args := c.Called(ctx, cpCodeID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*cprgCPCode), args.Error(1)
+}
+
+func (c *mockcprg) UpdateCPCode(ctx context.Context, cpCode cprgCPCode) (*cprgCPCode, error) {
+	args := c.Called(ctx, cpCode)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*cprgCPCode), args.Error(1)
+}
+
+func (c *mockcprg) CreateReportingGroup(ctx context.Context, group cprgReportingGroup) (*cprgReportingGroup, error) {
+	args := c.Called(ctx, group)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*cprgReportingGroup), args.Error(1)
+}
+
+func (c *mockcprg) GetReportingGroup(ctx context.Context, reportingGroupID int) (*cprgReportingGroup, error) {
+	args := c.Called(ctx, reportingGroupID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*cprgReportingGroup), args.Error(1)
+}
+
+func (c *mockcprg) UpdateReportingGroup(ctx context.Context, group cprgReportingGroup) (*cprgReportingGroup, error) {
+	args := c.Called(ctx, group)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*cprgReportingGroup), args.Error(1)
+}
+
+func (c *mockcprg) DeleteReportingGroup(ctx context.Context, reportingGroupID int) error {
+	args := c.Called(ctx, reportingGroupID)
+
+	return args.Error(0)
+}
+
+func TestExpandReportingGroup(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, akamaiReportingGroupSchema, map[string]interface{}{
+		"name":        "reports",
+		"contract_id": "ctr_1-ABC",
+		"group_id":    "grp_2",
+		"cp_codes":    []interface{}{"cpc_30", "cpc_4"},
+	})
+
+	group, err := expandReportingGroup(d)
+	require.NoError(t, err)
+	assert.Equal(t, &cprgReportingGroup{
+		ReportingGroupName: "reports",
+		Contracts: []cprgReportingGroupContract{{
+			ContractID: "1-ABC",
+			CPCodes:    []cprgReportingGroupCPCode{{CPCodeID: 4}, {CPCodeID: 30}},
+		}},
+		AccessGroup: cprgAccessGroup{GroupID: 2, ContractID: "1-ABC"},
+	}, group)
+
+	assert.Equal(t, map[string]interface{}{
+		"name":        "reports",
+		"contract_id": "ctr_1-ABC",
+		"group_id":    "grp_2",
+		"cp_codes":    []interface{}{"cpc_4", "cpc_30"},
+	}, flattenReportingGroup(group))
+}
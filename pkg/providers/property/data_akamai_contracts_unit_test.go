@@ -1,47 +1,49 @@
 package property
 
 import (
-	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
-	"github.com/stretchr/testify/mock"
+	"context"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/test"
 )
 
 func TestDataContracts(t *testing.T) {
 	t.Run("list contracts", func(t *testing.T) {
-		client := &mockpapi{}
-		ctrs := papi.ContractsItems{Items: []*papi.Contract{
-			{
-				ContractID:       "ctr_test1",
-				ContractTypeName: "ctr_typ_name_test1",
-			},
-			{
-				ContractID:       "ctr_test2",
-				ContractTypeName: "ctr_typ_name_test2",
-			},
-		}}
+		// The PAPI client, session and signer run unchanged, the exchanges are replayed from the cassette. Set
+		// TEST_RECORD=1 to record it again with the credentials of the default section of ~/.edgerc.
+		rec, err := test.NewRecorder("testdata/TestDataContracts/cassette.json")
+		require.NoError(t, err)
+		akamai.SetHTTPTransport(rec)
+		defer akamai.SetHTTPTransport(nil)
 
-		client.On("GetContracts",
-			mock.Anything,
-		).Return(&papi.GetContractsResponse{Contracts: ctrs, AccountID: "act_test"}, nil)
+		// The replayed credentials get a cache namespace of their own, so the contracts cached by other tests are not used
+		cfg := map[string]interface{}{"edgerc": "~/.edgerc"}
+		if rec.Mode() == test.ModeReplay {
+			cfg = map[string]interface{}{"config": []interface{}{map[string]interface{}{
+				"host":          test.ScrubbedHost,
+				"access_token":  "akab-access-cassette",
+				"client_token":  "akab-client-cassette",
+				"client_secret": "secret",
+			}}}
+		}
+		ctx := context.Background()
+		diags := testProvider.Configure(ctx, terraform.NewResourceConfigRaw(cfg))
+		require.False(t, diags.HasError(), "%v", diags)
 
-		useClient(client, func() {
-			resource.UnitTest(t, resource.TestCase{
-				Providers: testAccProviders,
-				Steps: []resource.TestStep{{
-					Config: loadFixtureString("testdata/TestDataContracts/contracts.tf"),
-					Check: resource.ComposeAggregateTestCheckFunc(
-						resource.TestCheckResourceAttr("data.akamai_contracts.akacontracts", "id", "act_test"),
-						resource.TestCheckOutput("aka_contract_id1", "ctr_test1"),
-						resource.TestCheckOutput("aka_contract_id2", "ctr_test2"),
-						resource.TestCheckOutput("aka_contract_typ_name1", "ctr_typ_name_test1"),
-						resource.TestCheckOutput("aka_contract_typ_name2", "ctr_typ_name_test2"),
-					),
-				}},
-			})
-		})
+		// The data source is read once through the plugin SDK, a terraform run reads it a varying number of times
+		state, diags := testProvider.DataSourcesMap["akamai_contracts"].ReadDataApply(ctx, &terraform.InstanceDiff{}, testProvider.Meta())
+		require.False(t, diags.HasError(), "%v", diags)
+		require.NoError(t, rec.Stop())
 
-		client.AssertExpectations(t)
+		assert.Equal(t, "act_test", state.ID)
+		assert.Equal(t, "ctr_test1", state.Attributes["contracts.0.contract_id"])
+		assert.Equal(t, "ctr_test2", state.Attributes["contracts.1.contract_id"])
+		assert.Equal(t, "ctr_typ_name_test1", state.Attributes["contracts.0.contract_type_name"])
+		assert.Equal(t, "ctr_typ_name_test2", state.Attributes["contracts.1.contract_type_name"])
 	})
 }
@@ -0,0 +1,119 @@
+package property
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+// PAPI Edge Hostnames
+//
+// Lists the edge hostnames of a contract and group, optionally only the ones with a domain suffix.
+//
+// https://developer.akamai.com/api/core_features/property_manager/v1.html#getedgehostnames
+func dataSourceAkamaiEdgeHostnames() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataAkamaiEdgeHostnamesRead,
+		Schema: map[string]*schema.Schema{
+			"contract_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: tools.IsNotBlank,
+			},
+			"group_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: tools.IsNotBlank,
+			},
+			"domain_suffix": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(domainSuffixRegexp, "must be a lower case domain name like edgesuite.net"),
+				Description:  "Only list the edge hostnames with this domain suffix",
+			},
+			"edge_hostnames": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The edge hostnames, sorted by domain",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"edge_hostname_id": {Type: schema.TypeString, Computed: true},
+						"edge_hostname":    {Type: schema.TypeString, Computed: true},
+						"domain_prefix":    {Type: schema.TypeString, Computed: true},
+						"domain_suffix":    {Type: schema.TypeString, Computed: true},
+						"product_id":       {Type: schema.TypeString, Computed: true},
+						"secure":           {Type: schema.TypeBool, Computed: true},
+						"secure_network":   {Type: schema.TypeString, Computed: true},
+						"ip_behavior":      {Type: schema.TypeString, Computed: true},
+						"status":           {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataAkamaiEdgeHostnamesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("PAPI", "dataAkamaiEdgeHostnamesRead")
+	client := inst.Client(meta)
+	// create a context with logging for api calls
+	ctx = session.ContextWithOptions(
+		ctx,
+		session.WithContextLog(logger),
+	)
+
+	contractID := tools.AddPrefix(d.Get("contract_id").(string), "ctr_")
+	groupID := tools.AddPrefix(d.Get("group_id").(string), "grp_")
+	domainSuffix := d.Get("domain_suffix").(string)
+
+	logger.Debugf("Listing edge hostnames of contract %s and group %s", contractID, groupID)
+	res, err := client.GetEdgeHostnames(ctx, papi.GetEdgeHostnamesRequest{
+		ContractID: contractID,
+		GroupID:    groupID,
+	})
+	if err != nil {
+		return diag.Errorf("error listing edge hostnames: %s", err)
+	}
+
+	d.SetId(groupID + contractID + domainSuffix)
+	if err := d.Set("edge_hostnames", edgeHostnamesList(res.EdgeHostnames.Items, domainSuffix)); err != nil {
+		return diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())
+	}
+
+	return nil
+}
+
+// edgeHostnamesList returns the edge hostnames with the domain suffix, or all of them when it is empty, sorted by domain
+func edgeHostnamesList(items []papi.EdgeHostnameGetItem, domainSuffix string) []interface{} {
+	edgeHostnames := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if domainSuffix != "" && item.DomainSuffix != domainSuffix {
+			continue
+		}
+		edgeHostnames = append(edgeHostnames, map[string]interface{}{
+			"edge_hostname_id": item.ID,
+			"edge_hostname":    item.Domain,
+			"domain_prefix":    item.DomainPrefix,
+			"domain_suffix":    item.DomainSuffix,
+			"product_id":       item.ProductID,
+			"secure":           item.Secure,
+			"secure_network":   readSecureNetwork(item),
+			"ip_behavior":      item.IPVersionBehavior,
+			"status":           item.Status,
+		})
+	}
+	sort.Slice(edgeHostnames, func(i, j int) bool {
+		return edgeHostnames[i].(map[string]interface{})["edge_hostname"].(string) < edgeHostnames[j].(map[string]interface{})["edge_hostname"].(string)
+	})
+
+	return edgeHostnames
+}
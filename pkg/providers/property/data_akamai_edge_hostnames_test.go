@@ -0,0 +1,79 @@
+package property
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+)
+
+var edgeHostnameItems = []papi.EdgeHostnameGetItem{
+	{
+		ID: "ehn_3", Domain: "www.example.com.edgekey.net", ProductID: "prd_SPM", DomainPrefix: "www.example.com",
+		DomainSuffix: "edgekey.net", Status: "CREATED", Secure: true, IPVersionBehavior: "IPV6_COMPLIANCE",
+	},
+	{
+		ID: "ehn_1", Domain: "static.example.com.edgesuite.net", ProductID: "prd_SPM", DomainPrefix: "static.example.com",
+		DomainSuffix: "edgesuite.net", Status: "CREATED", IPVersionBehavior: "IPV4",
+	},
+	{
+		ID: "ehn_2", Domain: "api.example.com.edgekey.net", ProductID: "prd_SPM", DomainPrefix: "api.example.com",
+		DomainSuffix: "edgekey.net", Status: "PENDING", Secure: true, IPVersionBehavior: "IPV4",
+	},
+}
+
+func TestDataEdgeHostnames(t *testing.T) {
+	client := &mockpapi{}
+	client.On("GetEdgeHostnames", mock.Anything, papi.GetEdgeHostnamesRequest{ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(&papi.GetEdgeHostnamesResponse{
+			ContractID:    "ctr_1",
+			GroupID:       "grp_2",
+			EdgeHostnames: papi.EdgeHostnameItems{Items: edgeHostnameItems},
+		}, nil)
+
+	useClient(client, func() {
+		resource.UnitTest(t, resource.TestCase{
+			Providers: testAccProviders,
+			Steps: []resource.TestStep{{
+				Config: loadFixtureString("testdata/TestDataEdgeHostnames/edge_hostnames.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.akamai_edge_hostnames.ehns", "id", "grp_2ctr_1edgekey.net"),
+					resource.TestCheckResourceAttr("data.akamai_edge_hostnames.ehns", "edge_hostnames.#", "2"),
+					resource.TestCheckResourceAttr("data.akamai_edge_hostnames.ehns", "edge_hostnames.0.edge_hostname_id", "ehn_2"),
+					resource.TestCheckResourceAttr("data.akamai_edge_hostnames.ehns", "edge_hostnames.1.edge_hostname", "www.example.com.edgekey.net"),
+					resource.TestCheckResourceAttr("data.akamai_edge_hostnames.ehns", "edge_hostnames.1.secure_network", "ENHANCED_TLS"),
+				),
+			}},
+		})
+	})
+
+	client.AssertExpectations(t)
+}
+
+func TestEdgeHostnamesList(t *testing.T) {
+	list := edgeHostnamesList(edgeHostnameItems, "")
+	var domains []string
+	for _, ehn := range list {
+		domains = append(domains, ehn.(map[string]interface{})["edge_hostname"].(string))
+	}
+	assert.Equal(t, []string{"api.example.com.edgekey.net", "static.example.com.edgesuite.net", "www.example.com.edgekey.net"}, domains)
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"edge_hostname_id": "ehn_1",
+			"edge_hostname":    "static.example.com.edgesuite.net",
+			"domain_prefix":    "static.example.com",
+			"domain_suffix":    "edgesuite.net",
+			"product_id":       "prd_SPM",
+			"secure":           false,
+			"secure_network":   "",
+			"ip_behavior":      "IPV4",
+			"status":           "CREATED",
+		},
+	}, edgeHostnamesList(edgeHostnameItems, "edgesuite.net"))
+
+	assert.Empty(t, edgeHostnamesList(edgeHostnameItems, "akamaized.net"))
+}
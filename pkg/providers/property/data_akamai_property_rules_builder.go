@@ -0,0 +1,343 @@
+package property
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+var (
+	// ErrRuleTree is returned when the rule tree built from the data source blocks is not valid
+	ErrRuleTree = errors.New("invalid rule tree")
+
+	ruleVariableNameRegexp = regexp.MustCompile(`^PMUSER_[A-Z0-9_]+$`)
+)
+
+func dataSourcePropertyRulesBuilder() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataPropertyRulesBuilderRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: tools.IsNotBlank,
+				Description:      "The name of the rule, the top level rule of a property must be named default",
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"criteria_must_satisfy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(papi.RuleCriteriaMustSatisfyAll),
+				ValidateFunc: validation.StringInSlice([]string{string(papi.RuleCriteriaMustSatisfyAll), string(papi.RuleCriteriaMustSatisfyAny)}, false),
+			},
+			"is_secure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Whether the property is served over HTTPS, only allowed in the default rule",
+			},
+			"criterion": ruleBehaviorSchema("The match criteria of the rule, in order"),
+			"behavior":  ruleBehaviorSchema("The behaviors of the rule, in order"),
+			"variable": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The user defined variables, only allowed in the default rule",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringMatch(ruleVariableNameRegexp, "the name must start with PMUSER_ and only hold upper case letters, digits and underscores"),
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"hidden": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"sensitive": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"children": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The child rules in order, usually the json attribute of other akamai_property_rules_builder data sources",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsJSON,
+				},
+			},
+			"rule_format": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The rule format the rule tree is validated against, together with product_id",
+			},
+			"product_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				StateFunc:   addPrefixToState("prd_"),
+				Description: "The product whose rule format schema the rule tree is validated against, together with rule_format",
+			},
+			"rule_schema_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A local rule format JSON schema the rule tree is validated against, instead of the one fetched for the product and rule format",
+			},
+			"json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The canonical JSON of the rule tree, usable as the rules of akamai_property",
+			},
+		},
+	}
+}
+
+func ruleBehaviorSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:             schema.TypeString,
+					Required:         true,
+					ValidateDiagFunc: tools.IsNotBlank,
+				},
+				"options": {
+					Type:        schema.TypeMap,
+					Optional:    true,
+					Description: "The options, values holding a JSON literal like true, 80 or jsonencode({id = 1}) keep their JSON type",
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+func dataPropertyRulesBuilderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("PAPI", "dataPropertyRulesBuilderRead")
+
+	rule, err := ruleFromBuilder(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rulesJSON, err := json.MarshalIndent(papi.RulesUpdate{Rules: rule}, "", "  ")
+	if err != nil {
+		logger.Debugf("Creating rule tree resulted in invalid JSON: %s", err)
+		return diag.FromErr(fmt.Errorf("invalid JSON result: %w", err))
+	}
+
+	if diags := validateBuilderRules(ctx, meta, d, rulesJSON); diags.HasError() {
+		return diags
+	}
+
+	sum := sha1.Sum(rulesJSON)
+	d.SetId(hex.EncodeToString(sum[:]))
+	if err := d.Set("json", string(rulesJSON)); err != nil {
+		return diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())
+	}
+
+	return nil
+}
+
+// validateBuilderRules validates the rule tree against the rule format schema of the product, or the schema file,
+// nothing is validated when neither is set
+func validateBuilderRules(ctx context.Context, meta akamai.OperationMeta, d *schema.ResourceData, rulesJSON []byte) diag.Diagnostics {
+	ruleFormat := d.Get("rule_format").(string)
+	productID := d.Get("product_id").(string)
+	schemaFile := d.Get("rule_schema_file").(string)
+	if schemaFile == "" && (ruleFormat == "" || productID == "") {
+		return nil
+	}
+
+	ruleSchema, err := loadRuleSchema(ctx, meta, schemaFile, tools.AddPrefix(productID, "prd_"), ruleFormat)
+	if err != nil {
+		if errors.Is(err, errRuleSchemaNotFound) {
+			meta.Log("PAPI", "validateBuilderRules").Warnf("no rule format schema for product %s and rule format %s, rules are not validated", productID, ruleFormat)
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	ruleErrors, err := ruleSchema.Validate(rulesJSON)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	schemaName := ruleFormat
+	if schemaFile != "" {
+		schemaName = schemaFile
+	}
+	var diags diag.Diagnostics
+	for _, e := range ruleErrors {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("%s %s", ErrRulesNotValid, schemaName),
+			Detail:   e.String(),
+		})
+	}
+	return diags
+}
+
+// ruleFromBuilder builds the rule described by the data source blocks
+func ruleFromBuilder(d *schema.ResourceData) (papi.Rules, error) {
+	rule := papi.Rules{
+		Name:                d.Get("name").(string),
+		Comment:             d.Get("comment").(string),
+		CriteriaMustSatisfy: papi.RuleCriteriaMustSatisfy(d.Get("criteria_must_satisfy").(string)),
+		Options:             papi.RuleOptions{IsSecure: d.Get("is_secure").(bool)},
+	}
+
+	var err error
+	if rule.Criteria, err = ruleBehaviorsFromList(d.Get("criterion").([]interface{})); err != nil {
+		return rule, fmt.Errorf("%w: criterion: %s", ErrRuleTree, err)
+	}
+	if rule.Behaviors, err = ruleBehaviorsFromList(d.Get("behavior").([]interface{})); err != nil {
+		return rule, fmt.Errorf("%w: behavior: %s", ErrRuleTree, err)
+	}
+
+	for _, v := range d.Get("variable").([]interface{}) {
+		variable, ok := v.(map[string]interface{})
+		if !ok {
+			return rule, fmt.Errorf("%w: %s, %q", tools.ErrInvalidType, "variable", "map[string]interface{}")
+		}
+		rule.Variables = append(rule.Variables, papi.RuleVariable{
+			Name:        variable["name"].(string),
+			Value:       variable["value"].(string),
+			Description: variable["description"].(string),
+			Hidden:      variable["hidden"].(bool),
+			Sensitive:   variable["sensitive"].(bool),
+		})
+	}
+
+	if rule.Name != "default" {
+		if len(rule.Variables) > 0 {
+			return rule, fmt.Errorf("%w: rule %q: variables are only allowed in the default rule", ErrRuleTree, rule.Name)
+		}
+		if rule.Options.IsSecure {
+			return rule, fmt.Errorf("%w: rule %q: is_secure is only allowed in the default rule", ErrRuleTree, rule.Name)
+		}
+	}
+
+	for i, c := range d.Get("children").([]interface{}) {
+		child, err := ruleFromJSON(c.(string))
+		if err != nil {
+			return rule, fmt.Errorf("%w: children.%d: %s", ErrRuleTree, i, err)
+		}
+		if child.Name == "default" {
+			return rule, fmt.Errorf("%w: children.%d: the default rule must be the top level rule", ErrRuleTree, i)
+		}
+		rule.Children = append(rule.Children, child)
+	}
+
+	return rule, nil
+}
+
+// ruleBehaviorsFromList converts behavior or criterion blocks to rule behaviors
+func ruleBehaviorsFromList(list []interface{}) ([]papi.RuleBehavior, error) {
+	var behaviors []papi.RuleBehavior
+	for i, v := range list {
+		block, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %d, %q", tools.ErrInvalidType, i, "map[string]interface{}")
+		}
+
+		options := make(papi.RuleOptionsMap)
+		for name, value := range block["options"].(map[string]interface{}) {
+			decoded, err := ruleOptionValue(value.(string))
+			if err != nil {
+				return nil, fmt.Errorf("%d: option %q: %s", i, name, err)
+			}
+			options[name] = decoded
+		}
+
+		behaviors = append(behaviors, papi.RuleBehavior{
+			Name:    block["name"].(string),
+			Options: options,
+		})
+	}
+
+	return behaviors, nil
+}
+
+// ruleOptionValue decodes an option value, values holding a JSON literal keep their JSON type
+// so true, 80 or {"id": 1} are sent as a boolean, a number and an object while other values stay strings
+func ruleOptionValue(value string) (interface{}, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return value, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			return nil, fmt.Errorf("not valid JSON: %s", err)
+		}
+		return value, nil
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return value, nil
+	}
+
+	return decoded, nil
+}
+
+// ruleFromJSON decodes a rule, given either as the rule object or wrapped in a rules object
+func ruleFromJSON(data string) (papi.Rules, error) {
+	var wrapped struct {
+		Rules *papi.Rules `json:"rules"`
+		Name  string      `json:"name"`
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(data)))
+	dec.UseNumber()
+	if err := dec.Decode(&wrapped); err != nil {
+		return papi.Rules{}, err
+	}
+	if wrapped.Rules != nil && wrapped.Name == "" {
+		return *wrapped.Rules, nil
+	}
+
+	var rule papi.Rules
+	dec = json.NewDecoder(bytes.NewReader([]byte(data)))
+	dec.UseNumber()
+	if err := dec.Decode(&rule); err != nil {
+		return rule, err
+	}
+	if rule.Name == "" {
+		return rule, fmt.Errorf("the rule has no name")
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,139 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+)
+
+func TestDataPropertyRulesBuilder(t *testing.T) {
+	t.Run("rule tree built from blocks and children", func(t *testing.T) {
+		client := mockpapi{}
+		useClient(&client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{
+					{
+						Config: loadFixtureString("testdata/TestDSRulesBuilder/rules_builder.tf"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("data.akamai_property_rules_builder.default", "json", loadFixtureString("testdata/TestDSRulesBuilder/rules_out.json")),
+						),
+					},
+				},
+			})
+		})
+	})
+	t.Run("variables outside of the default rule", func(t *testing.T) {
+		client := mockpapi{}
+		useClient(&client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{
+					{
+						Config:      loadFixtureString("testdata/TestDSRulesBuilder/variable_in_child.tf"),
+						ExpectError: regexp.MustCompile(`rule "Offload": variables are only allowed in the default rule`),
+					},
+				},
+			})
+		})
+	})
+	t.Run("option not valid for the rule format", func(t *testing.T) {
+		client := mockpapi{}
+		useClient(&client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{
+					{
+						Config:      loadFixtureString("testdata/TestDSRulesBuilder/invalid_option.tf"),
+						ExpectError: regexp.MustCompile(`/rules/behaviors/0/options/originType: "ORIGIN" is not one of`),
+					},
+				},
+			})
+		})
+	})
+	t.Run("default rule as a child", func(t *testing.T) {
+		client := mockpapi{}
+		useClient(&client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{
+					{
+						Config:      loadFixtureString("testdata/TestDSRulesBuilder/default_as_child.tf"),
+						ExpectError: regexp.MustCompile(`children.0: the default rule must be the top level rule`),
+					},
+				},
+			})
+		})
+	})
+}
+
+func TestValidateBuilderRules(t *testing.T) {
+	meta := testMeta(t)
+	builder := dataSourcePropertyRulesBuilder()
+	rules := []byte(`{"rules":{"name":"default","behaviors":[{"name":"origin","options":{"originType":"ORIGIN","hostname":"origin.example.com","cacheKey":"ORIGIN_HOSTNAME"}}]}}`)
+
+	t.Run("errors located with JSON pointers", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, builder.Schema, map[string]interface{}{
+			"name":             "default",
+			"rule_schema_file": "testdata/TestRuleSchema/schema.json",
+		})
+
+		diags := validateBuilderRules(context.Background(), akamai.Meta(meta), d, rules)
+		require.Len(t, diags, 2)
+		assert.Equal(t, diag.Error, diags[0].Severity)
+		assert.Equal(t, "rules do not match the rule format schema testdata/TestRuleSchema/schema.json", diags[0].Summary)
+		assert.Equal(t, "/rules/behaviors/0/options/cacheKey: unknown option", diags[0].Detail)
+		assert.Equal(t, `/rules/behaviors/0/options/originType: "ORIGIN" is not one of ["CUSTOMER","NET_STORAGE"]`, diags[1].Detail)
+	})
+
+	t.Run("not validated without a schema", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, builder.Schema, map[string]interface{}{
+			"name":        "default",
+			"rule_format": "v2020-11-02",
+		})
+
+		assert.Empty(t, validateBuilderRules(context.Background(), akamai.Meta(meta), d, rules))
+	})
+}
+
+func TestRuleOptionValue(t *testing.T) {
+	tests := map[string]struct {
+		value     string
+		expected  string
+		withError bool
+	}{
+		"string":             {value: "origin.example.com", expected: `"origin.example.com"`},
+		"number":             {value: "80", expected: `80`},
+		"large number":       {value: "12345678901234567890", expected: `12345678901234567890`},
+		"boolean":            {value: "true", expected: `true`},
+		"object":             {value: `{"id":12345}`, expected: `{"id":12345}`},
+		"list":               {value: `["a","b"]`, expected: `["a","b"]`},
+		"quoted number":      {value: `"80"`, expected: `"80"`},
+		"number with suffix": {value: "80s", expected: `"80s"`},
+		"empty":              {value: "", expected: `""`},
+		"invalid object":     {value: `{"id":}`, withError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, err := ruleOptionValue(test.value)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			encoded, err := json.Marshal(value)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(encoded))
+		})
+	}
+}
@@ -1,20 +1,14 @@
 package property
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"os"
-	"path/filepath"
-	"regexp"
 	"strconv"
-	"strings"
-	"text/template"
 
+	"github.com/apex/log"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -85,14 +79,10 @@ func dataSourcePropertyRulesTemplate() *schema.Resource {
 	}
 }
 
-const (
-	leftDelim  = "@+#"
-	rightDelim = "#+@"
-)
-
 func dataAkamaiPropertyRulesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	meta := akamai.Meta(m)
 	logger := meta.Log("PAPI", "dataAkamaiPropertyRulesRead")
+	ctx = log.NewContext(ctx, logger)
 	file, err := tools.GetStringValue("template_file", d)
 	if err != nil {
 		return diag.FromErr(err)
@@ -123,64 +113,21 @@ func dataAkamaiPropertyRulesRead(ctx context.Context, d *schema.ResourceData, m
 			return diag.FromErr(err)
 		}
 	}
-	templateStr, err := convertToTemplate(file)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	tmpl, err := template.New("main").Delims(leftDelim, rightDelim).Option("missingkey=error").Parse(templateStr)
+	tmpl, err := newRulesTemplate(file)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	dir := filepath.Dir(file)
-	templateFiles := make(map[string]string)
-	err = filepath.Walk(dir,
-		func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			if !info.IsDir() && path != file {
-				logger.Debugf("Template snippet found: %s", path)
-				templateFiles[strings.TrimPrefix(path, fmt.Sprintf("%s/", dir))] = path
-			}
-			return nil
-		})
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	for name, f := range templateFiles {
-		templateStr, err := convertToTemplate(f)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		tmpl, err = tmpl.New(name).Delims(leftDelim, rightDelim).Option("missingkey=error").Parse(templateStr)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-	}
-	wr := bytes.Buffer{}
-	err = tmpl.ExecuteTemplate(&wr, "main", varsMap)
+	rules, err := tmpl.execute(ctx, varsMap)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(file)
-	formatted := bytes.Buffer{}
-	result := wr.Bytes()
-	err = json.Indent(&formatted, result, "", "  ")
-	if err != nil {
-		logger.Debugf("Creating rule tree resulted in invalid JSON: %s\nError: %s", result, err)
-		return diag.FromErr(fmt.Errorf("invalid JSON result: %w", err))
-	}
-	if err := d.Set("json", formatted.String()); err != nil {
+	if err := d.Set("json", rules); err != nil {
 		return diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())
 	}
 	return nil
 }
 
-var (
-	includeRegexp = regexp.MustCompile(`"#include:.+"`)
-	varRegexp     = regexp.MustCompile(`"\${.+}"`)
-)
-
 var (
 	// ErrReadFile is used to specify error while reading a file.
 	ErrReadFile = errors.New("reading file")
@@ -190,32 +137,12 @@ var (
 	ErrFormatValue = errors.New("formatting value")
 	// ErrUnknownType is used to specify unknown error.
 	ErrUnknownType = errors.New("unknown 'type' value")
+	// ErrTemplateExpression is used to specify an invalid "${...}" expression in a rules template.
+	ErrTemplateExpression = errors.New("invalid template expression")
+	// ErrTemplateInclude is used to specify an invalid include statement in a rules template.
+	ErrTemplateInclude = errors.New("invalid template include")
 )
 
-func convertToTemplate(path string) (string, error) {
-	builder := strings.Builder{}
-	f, err := os.Open(path)
-	if err != nil {
-		return "", fmt.Errorf("%w: %s", ErrReadFile, err)
-	}
-	defer f.Close()
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if includeStatement := includeRegexp.Find(line); len(includeStatement) > 0 {
-			templateName := bytes.TrimPrefix(bytes.TrimSuffix(includeStatement, []byte(`"`)), []byte(`"#include:`))
-			line = includeRegexp.ReplaceAll(line, []byte(fmt.Sprintf(`%stemplate "%s" .%s`, leftDelim, templateName, rightDelim)))
-		}
-		if varStatement := varRegexp.Find(line); len(varStatement) > 0 {
-			varName := bytes.TrimPrefix(bytes.TrimSuffix(varStatement, []byte(`}"`)), []byte(`"${env`))
-			line = varRegexp.ReplaceAll(line, []byte(fmt.Sprintf("%s%s%s", leftDelim, varName, rightDelim)))
-		}
-		builder.Write(line)
-		builder.WriteString("\n")
-	}
-	return builder.String(), nil
-}
-
 func convertToTypedMap(vars []interface{}) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 	for _, variable := range vars {
@@ -256,6 +183,18 @@ func convertToTypedMap(vars []interface{}) (map[string]interface{}, error) {
 				return nil, fmt.Errorf("%w: 'jsonBlock` argument is not a valid json object: %s: %s", ErrUnmarshal, varNameStr, valueStr)
 			}
 			result[varNameStr] = valueStr
+		case "list":
+			var target []interface{}
+			if err := json.Unmarshal([]byte(valueStr), &target); err != nil {
+				return nil, fmt.Errorf("%w: 'list' argument is not a valid json array: %s: %s", ErrUnmarshal, varNameStr, valueStr)
+			}
+			result[varNameStr] = valueStr
+		case "map":
+			var target map[string]interface{}
+			if err := json.Unmarshal([]byte(valueStr), &target); err != nil {
+				return nil, fmt.Errorf("%w: 'map' argument is not a valid json object: %s: %s", ErrUnmarshal, varNameStr, valueStr)
+			}
+			result[varNameStr] = valueStr
 		case "number":
 			num, err := strconv.ParseFloat(valueStr, 64)
 			if err != nil {
@@ -329,7 +268,7 @@ func formatValue(val interface{}) (interface{}, error) {
 	switch v := val.(type) {
 	case string:
 		return fmt.Sprintf(`"%s"`, v), nil
-	case map[string]interface{}:
+	case map[string]interface{}, []interface{}:
 		jsonBlock, err := json.Marshal(v)
 		if err != nil {
 			return nil, err
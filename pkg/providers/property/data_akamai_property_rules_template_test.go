@@ -60,6 +60,22 @@ func TestDataAkamaiPropertyRulesRead(t *testing.T) {
 			})
 		})
 	})
+	t.Run("list and map variables with template functions", func(t *testing.T) {
+		client := mockpapi{}
+		useClient(&client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{
+					{
+						Config: loadFixtureString("testdata/TestDSRulesTemplate/template_vars_list_map.tf"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("data.akamai_property_rules_template.test", "json", loadFixtureString("testdata/TestRulesTemplate/rules_no_waf.json")),
+						),
+					},
+				},
+			})
+		})
+	})
 	t.Run("error setting both ,ap and file variables", func(t *testing.T) {
 		client := mockpapi{}
 		useClient(&client, func() {
@@ -82,7 +98,7 @@ func TestDataAkamaiPropertyRulesRead(t *testing.T) {
 				Steps: []resource.TestStep{
 					{
 						Config:      loadFixtureString("testdata/TestDSRulesTemplate/template_vars_invalid_type.tf"),
-						ExpectError: regexp.MustCompile(`'type' has invalid value: should be 'bool', 'number', 'string' or 'jsonBlock'`),
+						ExpectError: regexp.MustCompile(`'type' has invalid value: should be 'bool', 'number', 'string', 'jsonBlock', 'list' or 'map'`),
 					},
 				},
 			})
@@ -174,6 +190,10 @@ func TestFormatValue(t *testing.T) {
 			given:    map[string]interface{}{"string": "value", "num": 1, "map": map[string]interface{}{"bool": true}},
 			expected: `{"map":{"bool":true},"num":1,"string":"value"}`,
 		},
+		"list": {
+			given:    []interface{}{"a", 1, map[string]interface{}{"b": true}},
+			expected: `["a",1,{"b":true}]`,
+		},
 		"number": {
 			given:    1.23,
 			expected: 1.23,
@@ -201,7 +221,7 @@ func TestFormatValue(t *testing.T) {
 }
 
 func TestGetValuesFromMap(t *testing.T) {
-	variablesPath := "testData/TestDSRulesTemplate/variables"
+	variablesPath := "testdata/TestDSRulesTemplate/variables"
 	tests := map[string]struct {
 		definitionsFile string
 		valuesFile      string
@@ -276,12 +296,16 @@ func TestConvertToTypedMap(t *testing.T) {
 				map[string]interface{}{"name": "testNum", "type": "number", "value": "1.23"},
 				map[string]interface{}{"name": "testJSON", "type": "jsonBlock", "value": `{"abc": "cba", "number":1}`},
 				map[string]interface{}{"name": "testBool", "type": "bool", "value": "true"},
+				map[string]interface{}{"name": "testList", "type": "list", "value": `["a", 1]`},
+				map[string]interface{}{"name": "testMap", "type": "map", "value": `{"a": 1}`},
 			},
 			expected: map[string]interface{}{
 				"testString": `"test"`,
 				"testNum":    1.23,
 				"testJSON":   `{"abc": "cba", "number":1}`,
 				"testBool":   true,
+				"testList":   `["a", 1]`,
+				"testMap":    `{"a": 1}`,
 			},
 		},
 		"invalid values slice": {
@@ -330,6 +354,18 @@ func TestConvertToTypedMap(t *testing.T) {
 			},
 			withError: ErrUnmarshal,
 		},
+		"list has invalid json": {
+			givenVars: []interface{}{
+				map[string]interface{}{"name": "testList", "type": "list", "value": `{"a": 1}`},
+			},
+			withError: ErrUnmarshal,
+		},
+		"map has invalid json": {
+			givenVars: []interface{}{
+				map[string]interface{}{"name": "testMap", "type": "map", "value": `["a"]`},
+			},
+			withError: ErrUnmarshal,
+		},
 		"number is invalid": {
 			givenVars: []interface{}{
 				map[string]interface{}{"name": "test", "type": "number", "value": "abc"},
@@ -364,15 +400,22 @@ func TestConvertToTypedMap(t *testing.T) {
 }
 
 func TestConvertToTemplate(t *testing.T) {
-	templates := "testData/TestDSRulesTemplate/rules/templates"
+	templates := "testdata/TestDSRulesTemplate/rules/templates"
 	tests := map[string]struct {
-		givenFile    string
-		expectedFile string
-		withError    error
+		givenFile        string
+		expectedFile     string
+		expectedIncludes []string
+		withError        error
 	}{
 		"valid conversion": {
-			givenFile:    "template_in.json",
-			expectedFile: "template_out.json",
+			givenFile:        "template_in.json",
+			expectedFile:     "template_out.json",
+			expectedIncludes: []string{"snippets/some-template.json"},
+		},
+		"glob, relative and conditional includes with functions": {
+			givenFile:        "snippets/template_ext_in.json",
+			expectedFile:     "snippets/template_ext_out.json",
+			expectedIncludes: []string{"snippets/some-template.json", "snippets/sub/another-template.json", "snippets/sub/another-template.json"},
 		},
 		"plain JSON passed": {
 			givenFile:    "plain_json.json",
@@ -382,10 +425,14 @@ func TestConvertToTemplate(t *testing.T) {
 			givenFile: "invalid.json",
 			withError: ErrReadFile,
 		},
+		"unknown function": {
+			givenFile: "snippets/template_unknown_func.json",
+			withError: ErrTemplateExpression,
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			res, err := convertToTemplate(fmt.Sprintf("%s/%s", templates, test.givenFile))
+			res, includes, err := convertToTemplate(templates, test.givenFile)
 			if test.withError != nil {
 				assert.True(t, errors.Is(err, test.withError), "want: %s; got: %s", test.withError, err)
 				return
@@ -393,6 +440,7 @@ func TestConvertToTemplate(t *testing.T) {
 			require.NoError(t, err)
 			expected := loadFixtureString(fmt.Sprintf("%s/%s", templates, test.expectedFile))
 			assert.Equal(t, expected, res)
+			assert.Equal(t, test.expectedIncludes, includes)
 		})
 	}
 }
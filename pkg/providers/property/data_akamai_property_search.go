@@ -0,0 +1,172 @@
+package property
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+var propertySearchKeys = []string{"property_name", "hostname", "edge_hostname"}
+
+// PAPI bulk search
+//
+// Finds properties across all contracts and groups of the account by property name, or by the hostname or edge
+// hostname for which a version is active or is the latest version.
+//
+// https://developer.akamai.com/api/core_features/property_manager/v1.html#postfindbyvalue
+func dataSourceAkamaiPropertySearch() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataAkamaiPropertySearchRead,
+		Schema: map[string]*schema.Schema{
+			"property_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     propertySearchKeys,
+				ValidateDiagFunc: tools.IsNotBlank,
+			},
+			"hostname": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     propertySearchKeys,
+				ValidateDiagFunc: tools.IsNotBlank,
+			},
+			"edge_hostname": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     propertySearchKeys,
+				ValidateDiagFunc: tools.IsNotBlank,
+			},
+			"properties": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching properties, sorted by name",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"property_id":        {Type: schema.TypeString, Computed: true},
+						"property_name":      {Type: schema.TypeString, Computed: true},
+						"contract_id":        {Type: schema.TypeString, Computed: true},
+						"group_id":           {Type: schema.TypeString, Computed: true},
+						"latest_version":     {Type: schema.TypeInt, Computed: true},
+						"staging_version":    {Type: schema.TypeInt, Computed: true},
+						"production_version": {Type: schema.TypeInt, Computed: true},
+						"hostnames": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"edge_hostnames": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataAkamaiPropertySearchRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("PAPI", "dataAkamaiPropertySearchRead")
+	client := inst.Client(meta)
+	// create a context with logging for api calls
+	ctx = session.ContextWithOptions(
+		ctx,
+		session.WithContextLog(logger),
+	)
+
+	req := papi.SearchRequest{}
+	for attr, key := range map[string]string{
+		"property_name": papi.SearchKeyPropertyName,
+		"hostname":      papi.SearchKeyHostname,
+		"edge_hostname": papi.SearchKeyEdgeHostname,
+	} {
+		if value, err := tools.GetStringValue(attr, d); err == nil {
+			req = papi.SearchRequest{Key: key, Value: value}
+		}
+	}
+
+	logger.Debugf("Searching properties by %s %q", req.Key, req.Value)
+	res, err := client.SearchProperties(ctx, req)
+	if err != nil {
+		return diag.Errorf("error searching properties: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", req.Key, req.Value))
+	if err := d.Set("properties", searchResultProperties(res.Versions.Items)); err != nil {
+		return diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())
+	}
+
+	return nil
+}
+
+// searchResultProperties groups the property versions found by the search by property
+func searchResultProperties(items []papi.SearchItem) []interface{} {
+	type found struct {
+		property      map[string]interface{}
+		hostnames     map[string]interface{}
+		edgeHostnames map[string]interface{}
+	}
+
+	byID := make(map[string]*found)
+	for _, item := range items {
+		f, ok := byID[item.PropertyID]
+		if !ok {
+			f = &found{
+				property: map[string]interface{}{
+					"property_id":        item.PropertyID,
+					"property_name":      item.PropertyName,
+					"contract_id":        item.ContractID,
+					"group_id":           item.GroupID,
+					"latest_version":     0,
+					"staging_version":    0,
+					"production_version": 0,
+				},
+				hostnames:     make(map[string]interface{}),
+				edgeHostnames: make(map[string]interface{}),
+			}
+			byID[item.PropertyID] = f
+		}
+
+		if item.PropertyVersion > f.property["latest_version"].(int) {
+			f.property["latest_version"] = item.PropertyVersion
+		}
+		if item.StagingStatus == string(papi.VersionStatusActive) {
+			f.property["staging_version"] = item.PropertyVersion
+		}
+		if item.ProductionStatus == string(papi.VersionStatusActive) {
+			f.property["production_version"] = item.PropertyVersion
+		}
+		if item.Hostname != "" {
+			f.hostnames[item.Hostname] = nil
+		}
+		if item.EdgeHostname != "" {
+			f.edgeHostnames[item.EdgeHostname] = nil
+		}
+	}
+
+	properties := make([]interface{}, 0, len(byID))
+	for _, f := range byID {
+		f.property["hostnames"] = sortedKeys(f.hostnames)
+		f.property["edge_hostnames"] = sortedKeys(f.edgeHostnames)
+		properties = append(properties, f.property)
+	}
+	sort.Slice(properties, func(i, j int) bool {
+		a, b := properties[i].(map[string]interface{}), properties[j].(map[string]interface{})
+		if a["property_name"] != b["property_name"] {
+			return a["property_name"].(string) < b["property_name"].(string)
+		}
+		return a["property_id"].(string) < b["property_id"].(string)
+	})
+
+	return properties
+}
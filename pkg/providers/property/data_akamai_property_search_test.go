@@ -0,0 +1,110 @@
+package property
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+)
+
+var searchItems = []papi.SearchItem{
+	{
+		PropertyID: "prp_2", PropertyName: "www", ContractID: "ctr_1", GroupID: "grp_2",
+		PropertyVersion: 5, StagingStatus: "INACTIVE", ProductionStatus: "INACTIVE",
+		Hostname: "www.example.com", EdgeHostname: "www.example.com.edgesuite.net",
+	},
+	{
+		PropertyID: "prp_2", PropertyName: "www", ContractID: "ctr_1", GroupID: "grp_2",
+		PropertyVersion: 4, StagingStatus: "ACTIVE", ProductionStatus: "INACTIVE",
+		Hostname: "www.example.com", EdgeHostname: "www.example.com.edgekey.net",
+	},
+	{
+		PropertyID: "prp_2", PropertyName: "www", ContractID: "ctr_1", GroupID: "grp_2",
+		PropertyVersion: 3, StagingStatus: "INACTIVE", ProductionStatus: "ACTIVE",
+		Hostname: "www.example.com", EdgeHostname: "www.example.com.edgekey.net",
+	},
+	{
+		PropertyID: "prp_1", PropertyName: "legacy", ContractID: "ctr_2", GroupID: "grp_3",
+		PropertyVersion: 9, StagingStatus: "ACTIVE", ProductionStatus: "INACTIVE",
+		Hostname: "www.example.com", EdgeHostname: "legacy.example.com.edgesuite.net",
+	},
+}
+
+func TestDataPropertySearch(t *testing.T) {
+	t.Run("search by hostname", func(t *testing.T) {
+		client := &mockpapi{}
+		client.On("SearchProperties",
+			mock.Anything,
+			papi.SearchRequest{Key: papi.SearchKeyHostname, Value: "www.example.com"},
+		).Return(&papi.SearchResponse{Versions: papi.SearchItems{Items: searchItems}}, nil)
+
+		useClient(client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{{
+					Config: loadFixtureString("testdata/TestDataPropertySearch/hostname.tf"),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("data.akamai_property_search.search", "id", "hostname:www.example.com"),
+						resource.TestCheckResourceAttr("data.akamai_property_search.search", "properties.#", "2"),
+						resource.TestCheckResourceAttr("data.akamai_property_search.search", "properties.0.property_id", "prp_1"),
+						resource.TestCheckResourceAttr("data.akamai_property_search.search", "properties.1.property_id", "prp_2"),
+						resource.TestCheckResourceAttr("data.akamai_property_search.search", "properties.1.latest_version", "5"),
+						resource.TestCheckResourceAttr("data.akamai_property_search.search", "properties.1.staging_version", "4"),
+						resource.TestCheckResourceAttr("data.akamai_property_search.search", "properties.1.production_version", "3"),
+						resource.TestCheckResourceAttr("data.akamai_property_search.search", "properties.1.edge_hostnames.#", "2"),
+					),
+				}},
+			})
+		})
+
+		client.AssertExpectations(t)
+	})
+
+	t.Run("search key is required", func(t *testing.T) {
+		client := &mockpapi{}
+		useClient(client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{{
+					Config:      loadFixtureString("testdata/TestDataPropertySearch/no_key.tf"),
+					ExpectError: regexp.MustCompile(`one of .*property_name.* must be specified`),
+				}},
+			})
+		})
+
+		client.AssertExpectations(t)
+	})
+}
+
+func TestSearchResultProperties(t *testing.T) {
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"property_id":        "prp_1",
+			"property_name":      "legacy",
+			"contract_id":        "ctr_2",
+			"group_id":           "grp_3",
+			"latest_version":     9,
+			"staging_version":    9,
+			"production_version": 0,
+			"hostnames":          []string{"www.example.com"},
+			"edge_hostnames":     []string{"legacy.example.com.edgesuite.net"},
+		},
+		map[string]interface{}{
+			"property_id":        "prp_2",
+			"property_name":      "www",
+			"contract_id":        "ctr_1",
+			"group_id":           "grp_2",
+			"latest_version":     5,
+			"staging_version":    4,
+			"production_version": 3,
+			"hostnames":          []string{"www.example.com"},
+			"edge_hostnames":     []string{"www.example.com.edgekey.net", "www.example.com.edgesuite.net"},
+		},
+	}, searchResultProperties(searchItems))
+
+	assert.Empty(t, searchResultProperties(nil))
+}
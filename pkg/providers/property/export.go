@@ -0,0 +1,694 @@
+package property
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+)
+
+// Property export
+//
+// ExportProperties brings existing properties under Terraform: it writes the akamai_property, akamai_edge_hostname,
+// akamai_cp_code and akamai_property_activation configuration of the properties, their rules as snippets of an
+// akamai_property_rules_template and a script with the terraform import commands of the resources.
+//
+// The files written in the export directory are:
+//
+//   * property_<name>.tf with the property, its rules template and its activations
+//   * edge_hostnames.tf and cp_codes.tf with the edge hostnames and CP codes the properties use
+//   * property-snippets/<name>/main.json with an include of a snippet per top level rule
+//   * import.sh with the terraform import commands
+//
+// A property which cannot be exported is noted at the top of import.sh and the export continues with the next one.
+
+// ExportOptions selects the properties to export and the directory their configuration is written to
+type ExportOptions struct {
+	// ContractID and GroupID select all the properties of a group
+	ContractID string
+	GroupID    string
+
+	// PropertyName selects a single property, it is searched across the contracts and groups of the account
+	PropertyName string
+
+	// Dir is the directory the files are written to, it is created when missing
+	Dir string
+}
+
+type (
+	propertyExporter struct {
+		client papi.PAPI
+		dir    string
+
+		// names are the used resource names by resource type
+		names map[string]map[string]bool
+
+		cpCodes       []*exportedCPCode
+		edgeHostnames []*exportedEdgeHostname
+
+		// the CP codes and edge hostnames of each contract and group are listed once
+		cpCodeLists       map[string][]papi.CPCode
+		edgeHostnameLists map[string][]papi.EdgeHostnameGetItem
+
+		imports []string
+		files   []string
+
+		// failures are the notes of the properties which could not be exported
+		failures []string
+	}
+
+	// exportMark is the state of the exporter before a property, it is restored when the property is not exported
+	exportMark struct {
+		cpCodes       int
+		edgeHostnames int
+		imports       int
+		files         int
+	}
+
+	exportedCPCode struct {
+		name       string
+		cpCode     papi.CPCode
+		contractID string
+		groupID    string
+	}
+
+	exportedEdgeHostname struct {
+		name         string
+		edgeHostname papi.EdgeHostnameGetItem
+		contractID   string
+		groupID      string
+	}
+)
+
+var (
+	exportNameRegexp    = regexp.MustCompile(`[^a-z0-9]+`)
+	exportSnippetRegexp = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+)
+
+// ExportProperties writes the configuration of the selected properties and returns the paths of the written files
+// and the notes of the properties which could not be exported
+func ExportProperties(ctx context.Context, sess session.Session, opts ExportOptions) ([]string, []string, error) {
+	return exportProperties(ctx, papi.Client(sess), opts)
+}
+
+func exportProperties(ctx context.Context, client papi.PAPI, opts ExportOptions) ([]string, []string, error) {
+	e := &propertyExporter{
+		client:            client,
+		dir:               opts.Dir,
+		names:             make(map[string]map[string]bool),
+		cpCodeLists:       make(map[string][]papi.CPCode),
+		edgeHostnameLists: make(map[string][]papi.EdgeHostnameGetItem),
+	}
+
+	properties, err := e.selectProperties(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(e.dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrPropertyExport, err)
+	}
+
+	logger := log.FromContext(ctx)
+	for _, property := range properties {
+		mark := e.mark()
+		if err := e.exportProperty(ctx, property); err != nil {
+			note := fmt.Sprintf("property %s is not exported: %s", property.PropertyName, err)
+			logger.Warn(note)
+			e.failures = append(e.failures, note)
+			if err := e.restore(mark); err != nil {
+				return nil, nil, fmt.Errorf("%w: %s", ErrPropertyExport, err)
+			}
+		}
+	}
+
+	if err := e.writeShared(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrPropertyExport, err)
+	}
+
+	return e.files, e.failures, nil
+}
+
+// selectProperties returns the properties to export sorted by name
+func (e *propertyExporter) selectProperties(ctx context.Context, opts ExportOptions) ([]papi.Property, error) {
+	var properties []papi.Property
+	switch {
+	case opts.PropertyName != "":
+		item, err := searchProperty(ctx, e.client, papi.SearchRequest{Key: papi.SearchKeyPropertyName, Value: opts.PropertyName})
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, papi.Property{
+			PropertyID:   item.PropertyID,
+			PropertyName: item.PropertyName,
+			ContractID:   item.ContractID,
+			GroupID:      item.GroupID,
+		})
+
+	case opts.ContractID != "" && opts.GroupID != "":
+		res, err := e.client.GetProperties(ctx, papi.GetPropertiesRequest{ContractID: opts.ContractID, GroupID: opts.GroupID})
+		if err != nil {
+			return nil, err
+		}
+		for _, property := range res.Properties.Items {
+			properties = append(properties, *property)
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: either a property name or a contract and a group are required", ErrPropertyExport)
+	}
+
+	sort.Slice(properties, func(i, j int) bool {
+		return properties[i].PropertyName < properties[j].PropertyName
+	})
+
+	return properties, nil
+}
+
+func (e *propertyExporter) exportProperty(ctx context.Context, selected papi.Property) error {
+	logger := log.FromContext(ctx).WithField("property", selected.PropertyName)
+
+	property, err := fetchProperty(ctx, e.client, selected.PropertyID, selected.GroupID, selected.ContractID)
+	if err != nil {
+		return err
+	}
+	version, err := e.client.GetPropertyVersion(ctx, papi.GetPropertyVersionRequest{
+		PropertyID:      property.PropertyID,
+		PropertyVersion: property.LatestVersion,
+		ContractID:      property.ContractID,
+		GroupID:         property.GroupID,
+	})
+	if err != nil {
+		return err
+	}
+	hostnames, err := fetchPropertyHostnames(ctx, e.client, *property)
+	if err != nil {
+		return err
+	}
+	rules, ruleFormat, _, _, err := fetchPropertyRules(ctx, e.client, *property)
+	if err != nil {
+		return err
+	}
+
+	name := e.resourceName("akamai_property", property.PropertyName)
+	var notes []string
+
+	snippetsDir := filepath.Join("property-snippets", name)
+	if err := e.writeRulesSnippets(snippetsDir, rules); err != nil {
+		return err
+	}
+
+	// the hostnames refer to the exported edge hostnames, the edge hostnames of other groups are kept as is
+	hostnameRefs := make(map[string]string, len(hostnames))
+	for _, hostname := range hostnames {
+		ref, err := e.edgeHostnameRef(ctx, property.ContractID, property.GroupID, hostname.CnameTo)
+		if err != nil {
+			return err
+		}
+		if ref == "" {
+			ref = hclString(hostname.CnameTo)
+			notes = append(notes, fmt.Sprintf("edge hostname %s is not in the group of the property, it is not exported", hostname.CnameTo))
+		}
+		hostnameRefs[hostname.CnameFrom] = ref
+	}
+
+	for _, cpCodeID := range ruleCPCodes(rules.Rules) {
+		found, err := e.exportCPCode(ctx, property.ContractID, property.GroupID, cpCodeID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			notes = append(notes, fmt.Sprintf("CP code cpc_%d is not in the group of the property, it is not exported", cpCodeID))
+		}
+	}
+
+	activations, activationNotes, err := e.activations(ctx, *property, name)
+	if err != nil {
+		return err
+	}
+	notes = append(notes, activationNotes...)
+
+	var b bytes.Buffer
+	for _, note := range notes {
+		logger.Warn(note)
+		fmt.Fprintf(&b, "# NOTE: %s\n", note)
+	}
+	if len(notes) > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "data \"akamai_property_rules_template\" %q {\n", name)
+	fmt.Fprintf(&b, "  template_file = abspath(%s)\n", hclString("${path.module}/"+filepath.ToSlash(filepath.Join(snippetsDir, "main.json"))))
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "resource \"akamai_property\" %q {\n", name)
+	writeHCLAttrs(&b, "  ", [][2]string{
+		{"name", hclString(property.PropertyName)},
+		{"contract_id", hclString(property.ContractID)},
+		{"group_id", hclString(property.GroupID)},
+		{"product_id", hclString(version.Version.ProductID)},
+		{"rule_format", hclString(ruleFormat)},
+	})
+	writeHCLMap(&b, "  ", "hostnames", hostnameRefs)
+	fmt.Fprintf(&b, "  rules = data.akamai_property_rules_template.%s.json\n", name)
+	b.WriteString("}\n")
+	b.WriteString(activations)
+
+	if err := e.writeFile(fmt.Sprintf("property_%s.tf", name), b.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	e.imports = append(e.imports, fmt.Sprintf("terraform import akamai_property.%s %s,%s,%s",
+		name, property.PropertyID, property.ContractID, property.GroupID))
+
+	return nil
+}
+
+// activations returns the configuration of the activations of the versions active on staging and production
+// The notification emails of the last activation of each network are the contacts, an active version without an
+// activation to take them from is noted and not exported
+func (e *propertyExporter) activations(ctx context.Context, property papi.Property, name string) (string, []string, error) {
+	networks := []struct {
+		network papi.ActivationNetwork
+		version *int
+	}{
+		{papi.ActivationNetworkStaging, property.StagingVersion},
+		{papi.ActivationNetworkProduction, property.ProductionVersion},
+	}
+
+	var b bytes.Buffer
+	var notes []string
+	var res *papi.GetActivationsResponse
+	for _, n := range networks {
+		if n.version == nil || *n.version == 0 {
+			continue
+		}
+		if res == nil {
+			var err error
+			res, err = e.client.GetActivations(ctx, papi.GetActivationsRequest{
+				PropertyID: property.PropertyID,
+				ContractID: property.ContractID,
+				GroupID:    property.GroupID,
+			})
+			if err != nil {
+				return "", nil, err
+			}
+		}
+
+		var contacts []string
+		var submitted string
+		for _, a := range res.Activations.Items {
+			if a.Network == n.network && a.PropertyVersion == *n.version && a.ActivationType == papi.ActivationTypeActivate &&
+				a.SubmitDate >= submitted {
+				contacts, submitted = a.NotifyEmails, a.SubmitDate
+			}
+		}
+		if len(contacts) == 0 {
+			notes = append(notes, fmt.Sprintf("version %d is active on %s without an activation to take the contacts from, its activation is not exported",
+				*n.version, n.network))
+			continue
+		}
+		contact := make([]string, 0, len(contacts))
+		for _, c := range contacts {
+			contact = append(contact, hclString(c))
+		}
+
+		fmt.Fprintf(&b, "\nresource \"akamai_property_activation\" %q {\n", name+"_"+strings.ToLower(string(n.network)))
+		writeHCLAttrs(&b, "  ", [][2]string{
+			{"property_id", fmt.Sprintf("akamai_property.%s.id", name)},
+			{"version", strconv.Itoa(*n.version)},
+			{"network", hclString(string(n.network))},
+			{"contact", "[" + strings.Join(contact, ", ") + "]"},
+		})
+		b.WriteString("}\n")
+	}
+
+	return b.String(), notes, nil
+}
+
+// edgeHostnameRef returns the reference to the exported edge hostname with the domain, it is empty when the edge
+// hostname is not in the group
+func (e *propertyExporter) edgeHostnameRef(ctx context.Context, contractID, groupID, domain string) (string, error) {
+	for _, exported := range e.edgeHostnames {
+		if exported.edgeHostname.Domain == domain {
+			return fmt.Sprintf("akamai_edge_hostname.%s.edge_hostname", exported.name), nil
+		}
+	}
+
+	key := contractID + "," + groupID
+	items, ok := e.edgeHostnameLists[key]
+	if !ok {
+		res, err := e.client.GetEdgeHostnames(ctx, papi.GetEdgeHostnamesRequest{ContractID: contractID, GroupID: groupID})
+		if err != nil {
+			return "", err
+		}
+		items = res.EdgeHostnames.Items
+		e.edgeHostnameLists[key] = items
+	}
+
+	for _, item := range items {
+		if item.Domain != domain {
+			continue
+		}
+		exported := &exportedEdgeHostname{
+			name:         e.resourceName("akamai_edge_hostname", domain),
+			edgeHostname: item,
+			contractID:   contractID,
+			groupID:      groupID,
+		}
+		e.edgeHostnames = append(e.edgeHostnames, exported)
+		e.imports = append(e.imports, fmt.Sprintf("terraform import akamai_edge_hostname.%s %s,%s,%s",
+			exported.name, item.ID, contractID, groupID))
+
+		return fmt.Sprintf("akamai_edge_hostname.%s.edge_hostname", exported.name), nil
+	}
+
+	return "", nil
+}
+
+// exportCPCode records the CP code for cp_codes.tf, it returns false when the CP code is not in the group
+func (e *propertyExporter) exportCPCode(ctx context.Context, contractID, groupID string, cpCodeID int) (bool, error) {
+	id := fmt.Sprintf("cpc_%d", cpCodeID)
+	for _, exported := range e.cpCodes {
+		if exported.cpCode.ID == id {
+			return true, nil
+		}
+	}
+
+	key := contractID + "," + groupID
+	items, ok := e.cpCodeLists[key]
+	if !ok {
+		res, err := e.client.GetCPCodes(ctx, papi.GetCPCodesRequest{ContractID: contractID, GroupID: groupID})
+		if err != nil {
+			return false, err
+		}
+		items = res.CPCodes.Items
+		e.cpCodeLists[key] = items
+	}
+
+	for _, item := range items {
+		if item.ID != id {
+			continue
+		}
+		exported := &exportedCPCode{
+			name:       e.resourceName("akamai_cp_code", item.Name),
+			cpCode:     item,
+			contractID: contractID,
+			groupID:    groupID,
+		}
+		e.cpCodes = append(e.cpCodes, exported)
+		e.imports = append(e.imports, fmt.Sprintf("terraform import akamai_cp_code.%s %s,%s,%s",
+			exported.name, item.ID, contractID, groupID))
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// writeShared writes the edge hostnames, the CP codes and the import script once all the properties are exported
+func (e *propertyExporter) writeShared() error {
+	if len(e.edgeHostnames) > 0 {
+		var b bytes.Buffer
+		for i, exported := range e.edgeHostnames {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			item := exported.edgeHostname
+			fmt.Fprintf(&b, "resource \"akamai_edge_hostname\" %q {\n", exported.name)
+			writeHCLAttrs(&b, "  ", [][2]string{
+				{"contract_id", hclString(exported.contractID)},
+				{"group_id", hclString(exported.groupID)},
+				{"product_id", hclString(item.ProductID)},
+				{"edge_hostname", hclString(item.Domain)},
+				{"ip_behavior", hclString(item.IPVersionBehavior)},
+			})
+			b.WriteString("}\n")
+		}
+		if err := e.writeFile("edge_hostnames.tf", b.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+
+	if len(e.cpCodes) > 0 {
+		var b bytes.Buffer
+		for i, exported := range e.cpCodes {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			var product string
+			if len(exported.cpCode.ProductIDs) > 0 {
+				product = exported.cpCode.ProductIDs[0]
+			}
+			fmt.Fprintf(&b, "resource \"akamai_cp_code\" %q {\n", exported.name)
+			writeHCLAttrs(&b, "  ", [][2]string{
+				{"name", hclString(exported.cpCode.Name)},
+				{"contract_id", hclString(exported.contractID)},
+				{"group_id", hclString(exported.groupID)},
+				{"product", hclString(product)},
+			})
+			b.WriteString("}\n")
+		}
+		if err := e.writeFile("cp_codes.tf", b.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Imports the exported resources into the Terraform state, run it from the export directory after terraform init.\n")
+	b.WriteString("# The akamai_property_activation resources are not imported, applying them reuses the current activations.\n")
+	b.WriteString("set -e\n\n")
+	for _, note := range e.failures {
+		fmt.Fprintf(&b, "# NOTE: %s\n", note)
+	}
+	if len(e.failures) > 0 {
+		b.WriteString("\n")
+	}
+	for _, command := range e.imports {
+		b.WriteString(command + "\n")
+	}
+
+	return e.writeFile("import.sh", b.Bytes(), 0755)
+}
+
+// writeRulesSnippets writes the rules as a main template including a snippet per top level rule
+func (e *propertyExporter) writeRulesSnippets(dir string, rules papi.RulesUpdate) error {
+	encoded, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	// numbers are kept as they are written by PAPI
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var tree map[string]interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return err
+	}
+
+	root, _ := tree["rules"].(map[string]interface{})
+	children, _ := root["children"].([]interface{})
+	used := make(map[string]bool)
+	for i, child := range children {
+		name, _ := child.(map[string]interface{})["name"].(string)
+		file := uniqueName(used, strings.Trim(exportSnippetRegexp.ReplaceAllString(name, "_"), "_"), "rule") + ".json"
+
+		snippet, err := marshalSnippet(child)
+		if err != nil {
+			return err
+		}
+		if err := e.writeFile(filepath.Join(dir, file), snippet, 0644); err != nil {
+			return err
+		}
+		children[i] = "#include:" + file
+	}
+
+	main, err := marshalSnippet(tree)
+	if err != nil {
+		return err
+	}
+
+	return e.writeFile(filepath.Join(dir, "main.json"), main, 0644)
+}
+
+// mark returns the state of the exporter before a property is exported
+func (e *propertyExporter) mark() exportMark {
+	return exportMark{
+		cpCodes:       len(e.cpCodes),
+		edgeHostnames: len(e.edgeHostnames),
+		imports:       len(e.imports),
+		files:         len(e.files),
+	}
+}
+
+// restore drops the resources, the import commands and the files of a property which could not be exported
+func (e *propertyExporter) restore(m exportMark) error {
+	for _, exported := range e.cpCodes[m.cpCodes:] {
+		delete(e.names["akamai_cp_code"], exported.name)
+	}
+	for _, exported := range e.edgeHostnames[m.edgeHostnames:] {
+		delete(e.names["akamai_edge_hostname"], exported.name)
+	}
+	e.cpCodes = e.cpCodes[:m.cpCodes]
+	e.edgeHostnames = e.edgeHostnames[:m.edgeHostnames]
+	e.imports = e.imports[:m.imports]
+
+	for _, path := range e.files[m.files:] {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		// the snippets directory of the property is removed once it is empty
+		if dir := filepath.Dir(path); dir != filepath.Clean(e.dir) {
+			_ = os.Remove(dir)
+		}
+	}
+	e.files = e.files[:m.files]
+
+	return nil
+}
+
+func (e *propertyExporter) writeFile(name string, content []byte, mode os.FileMode) error {
+	path := filepath.Join(e.dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, content, mode); err != nil {
+		return err
+	}
+	e.files = append(e.files, path)
+
+	return nil
+}
+
+// resourceName returns a Terraform resource name derived from the label which is unique for the resource type
+func (e *propertyExporter) resourceName(resourceType, label string) string {
+	used, ok := e.names[resourceType]
+	if !ok {
+		used = make(map[string]bool)
+		e.names[resourceType] = used
+	}
+
+	name := strings.Trim(exportNameRegexp.ReplaceAllString(strings.ToLower(label), "_"), "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+
+	return uniqueName(used, name, strings.TrimPrefix(resourceType, "akamai_"))
+}
+
+// uniqueName returns the name, or the fallback when it is empty, suffixed by a number when it is already used
+func uniqueName(used map[string]bool, name, fallback string) string {
+	if name == "" {
+		name = fallback
+	}
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	used[unique] = true
+
+	return unique
+}
+
+// ruleCPCodes returns the sorted IDs of the CP codes of the cpCode behaviors of the rule tree
+func ruleCPCodes(rules papi.Rules) []int {
+	found := make(map[int]bool)
+	var walk func(papi.Rules)
+	walk = func(rule papi.Rules) {
+		for _, behavior := range rule.Behaviors {
+			if behavior.Name != "cpCode" {
+				continue
+			}
+			value, _ := behavior.Options["value"].(map[string]interface{})
+			switch id := value["id"].(type) {
+			case float64:
+				found[int(id)] = true
+			case int:
+				found[id] = true
+			case json.Number:
+				if i, err := id.Int64(); err == nil {
+					found[int(i)] = true
+				}
+			}
+		}
+		for _, child := range rule.Children {
+			walk(child)
+		}
+	}
+	walk(rules)
+
+	ids := make([]int, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+// marshalSnippet returns the indented JSON of the snippet without escaping the HTML characters of the rules
+func marshalSnippet(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// hclString returns the HCL string literal of s, template sequences of s are escaped except ${path.module}
+func hclString(s string) string {
+	quoted := strconv.Quote(s)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	quoted = strings.ReplaceAll(quoted, "%{", "%%{")
+
+	return strings.ReplaceAll(quoted, "$${path.module}", "${path.module}")
+}
+
+// writeHCLAttrs writes the attributes with their equal signs aligned like terraform fmt does
+func writeHCLAttrs(b *bytes.Buffer, indent string, attrs [][2]string) {
+	width := 0
+	for _, attr := range attrs {
+		if len(attr[0]) > width {
+			width = len(attr[0])
+		}
+	}
+	for _, attr := range attrs {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, attr[0], attr[1])
+	}
+}
+
+// writeHCLMap writes a map attribute with its keys sorted
+func writeHCLMap(b *bytes.Buffer, indent, name string, values map[string]string) {
+	if len(values) == 0 {
+		fmt.Fprintf(b, "%s%s = {}\n", indent, name)
+		return
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([][2]string, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, [2]string{hclString(key), values[key]})
+	}
+	fmt.Fprintf(b, "%s%s = {\n", indent, name)
+	writeHCLAttrs(b, indent+"  ", attrs)
+	fmt.Fprintf(b, "%s}\n", indent)
+}
@@ -0,0 +1,233 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportProperties(t *testing.T) {
+	staging := 2
+	property := papi.Property{
+		PropertyID:     "prp_1",
+		PropertyName:   "www.example.com",
+		ContractID:     "ctr_1",
+		GroupID:        "grp_2",
+		LatestVersion:  3,
+		StagingVersion: &staging,
+	}
+	rules := papi.Rules{
+		Name:      "default",
+		Behaviors: []papi.RuleBehavior{{Name: "cpCode", Options: papi.RuleOptionsMap{"value": map[string]interface{}{"id": float64(123)}}}},
+		Children: []papi.Rules{
+			{Name: "Static <content>", Behaviors: []papi.RuleBehavior{{Name: "caching", Options: papi.RuleOptionsMap{"ttl": "1d"}}}},
+			{Name: "Images", Behaviors: []papi.RuleBehavior{{Name: "cpCode", Options: papi.RuleOptionsMap{"value": map[string]interface{}{"id": float64(456)}}}}},
+		},
+	}
+
+	client := &mockpapi{}
+	client.On("GetProperties", AnyCTX, papi.GetPropertiesRequest{ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(&papi.GetPropertiesResponse{Properties: papi.PropertiesItems{Items: []*papi.Property{&property}}}, nil).Once()
+	client.On("GetProperty", AnyCTX, papi.GetPropertyRequest{PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(&papi.GetPropertyResponse{Property: &property}, nil).Once()
+	client.On("GetPropertyVersion", AnyCTX, mock.AnythingOfType("papi.GetPropertyVersionRequest")).
+		Return(&papi.GetPropertyVersionsResponse{Version: papi.PropertyVersionGetItem{ProductID: "prd_Fresca"}}, nil).Once()
+	client.On("GetPropertyVersionHostnames", AnyCTX, mock.AnythingOfType("papi.GetPropertyVersionHostnamesRequest")).
+		Return(&papi.GetPropertyVersionHostnamesResponse{Hostnames: papi.HostnameResponseItems{Items: []papi.Hostname{
+			{CnameFrom: "www.example.com", CnameTo: "www.example.com.edgesuite.net"},
+			{CnameFrom: "static.example.com", CnameTo: "other.example.com.edgesuite.net"},
+		}}}, nil).Once()
+	client.On("GetRuleTree", AnyCTX, mock.AnythingOfType("papi.GetRuleTreeRequest")).
+		Return(&papi.GetRuleTreeResponse{RuleFormat: "v2020-11-02", Rules: rules}, nil).Once()
+	client.On("GetEdgeHostnames", AnyCTX, papi.GetEdgeHostnamesRequest{ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(&papi.GetEdgeHostnamesResponse{EdgeHostnames: papi.EdgeHostnameItems{Items: []papi.EdgeHostnameGetItem{
+			{ID: "ehn_5", Domain: "www.example.com.edgesuite.net", ProductID: "prd_Fresca", IPVersionBehavior: "IPV4"},
+		}}}, nil).Once()
+	client.On("GetCPCodes", AnyCTX, papi.GetCPCodesRequest{ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(&papi.GetCPCodesResponse{CPCodes: papi.CPCodeItems{Items: []papi.CPCode{
+			{ID: "cpc_123", Name: "Example site", ProductIDs: []string{"prd_Fresca"}},
+		}}}, nil).Once()
+	client.On("GetActivations", AnyCTX, papi.GetActivationsRequest{PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(&papi.GetActivationsResponse{Activations: papi.ActivationsItems{Items: []*papi.Activation{
+			{ActivationType: papi.ActivationTypeActivate, Network: papi.ActivationNetworkStaging, PropertyVersion: 2, SubmitDate: "2021-01-02", NotifyEmails: []string{"ops@example.com"}},
+			{ActivationType: papi.ActivationTypeActivate, Network: papi.ActivationNetworkStaging, PropertyVersion: 2, SubmitDate: "2021-01-01", NotifyEmails: []string{"old@example.com"}},
+		}}}, nil).Once()
+
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files, failures, err := exportProperties(context.Background(), client, ExportOptions{ContractID: "ctr_1", GroupID: "grp_2", Dir: dir})
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+	client.AssertExpectations(t)
+
+	read := func(name string) string {
+		content, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(content)
+	}
+	assert.Contains(t, files, filepath.Join(dir, "property_www_example_com.tf"))
+	assert.Equal(t, `# NOTE: edge hostname other.example.com.edgesuite.net is not in the group of the property, it is not exported
+# NOTE: CP code cpc_456 is not in the group of the property, it is not exported
+
+data "akamai_property_rules_template" "www_example_com" {
+  template_file = abspath("${path.module}/property-snippets/www_example_com/main.json")
+}
+
+resource "akamai_property" "www_example_com" {
+  name        = "www.example.com"
+  contract_id = "ctr_1"
+  group_id    = "grp_2"
+  product_id  = "prd_Fresca"
+  rule_format = "v2020-11-02"
+  hostnames = {
+    "static.example.com" = "other.example.com.edgesuite.net"
+    "www.example.com"    = akamai_edge_hostname.www_example_com_edgesuite_net.edge_hostname
+  }
+  rules = data.akamai_property_rules_template.www_example_com.json
+}
+
+resource "akamai_property_activation" "www_example_com_staging" {
+  property_id = akamai_property.www_example_com.id
+  version     = 2
+  network     = "STAGING"
+  contact     = ["ops@example.com"]
+}
+`, read("property_www_example_com.tf"))
+	assert.Equal(t, `resource "akamai_edge_hostname" "www_example_com_edgesuite_net" {
+  contract_id   = "ctr_1"
+  group_id      = "grp_2"
+  product_id    = "prd_Fresca"
+  edge_hostname = "www.example.com.edgesuite.net"
+  ip_behavior   = "IPV4"
+}
+`, read("edge_hostnames.tf"))
+	assert.Equal(t, `resource "akamai_cp_code" "example_site" {
+  name        = "Example site"
+  contract_id = "ctr_1"
+  group_id    = "grp_2"
+  product     = "prd_Fresca"
+}
+`, read("cp_codes.tf"))
+	assert.Contains(t, read("import.sh"), `
+terraform import akamai_edge_hostname.www_example_com_edgesuite_net ehn_5,ctr_1,grp_2
+terraform import akamai_cp_code.example_site cpc_123,ctr_1,grp_2
+terraform import akamai_property.www_example_com prp_1,ctr_1,grp_2
+`)
+
+	// the snippets render the rule tree of the property
+	assert.Contains(t, read("property-snippets/www_example_com/main.json"), `"#include:Static_content.json"`)
+	tmpl, err := newRulesTemplate(filepath.Join(dir, "property-snippets/www_example_com/main.json"))
+	require.NoError(t, err)
+	rendered, err := tmpl.execute(context.Background(), nil)
+	require.NoError(t, err)
+	var got papi.RulesUpdate
+	require.NoError(t, json.Unmarshal([]byte(rendered), &got))
+	assert.Equal(t, papi.RulesUpdate{Rules: rules}, got)
+}
+
+func TestExportPropertiesSelection(t *testing.T) {
+	client := &mockpapi{}
+	_, _, err := exportProperties(context.Background(), client, ExportOptions{ContractID: "ctr_1"})
+	assert.True(t, errors.Is(err, ErrPropertyExport))
+
+	client.On("SearchProperties", AnyCTX, papi.SearchRequest{Key: papi.SearchKeyPropertyName, Value: "missing"}).
+		Return(&papi.SearchResponse{}, nil).Once()
+	_, _, err = exportProperties(context.Background(), client, ExportOptions{PropertyName: "missing"})
+	assert.True(t, errors.Is(err, ErrPropertyNotFound))
+
+	// the search returns the versions of the properties, two properties with the name are ambiguous
+	client.On("SearchProperties", AnyCTX, papi.SearchRequest{Key: papi.SearchKeyPropertyName, Value: "www.example.com"}).
+		Return(&papi.SearchResponse{Versions: papi.SearchItems{Items: []papi.SearchItem{
+			{PropertyID: "prp_1", PropertyName: "www.example.com", ContractID: "ctr_1", GroupID: "grp_1"},
+			{PropertyID: "prp_1", PropertyName: "www.example.com", ContractID: "ctr_1", GroupID: "grp_1"},
+			{PropertyID: "prp_2", PropertyName: "www.example.com", ContractID: "ctr_2", GroupID: "grp_2"},
+		}}}, nil).Once()
+	_, _, err = exportProperties(context.Background(), client, ExportOptions{PropertyName: "www.example.com"})
+	require.True(t, errors.Is(err, ErrPropertyImportAmbiguous), "want %v, got %v", ErrPropertyImportAmbiguous, err)
+	assert.Contains(t, err.Error(), "prp_1, prp_2")
+	client.AssertExpectations(t)
+}
+
+func TestExportPropertiesFailures(t *testing.T) {
+	production := 1
+	failing := papi.Property{PropertyID: "prp_1", PropertyName: "a.example.com", ContractID: "ctr_1", GroupID: "grp_2", LatestVersion: 1, ProductionVersion: &production}
+	exported := papi.Property{PropertyID: "prp_2", PropertyName: "b.example.com", ContractID: "ctr_1", GroupID: "grp_2", LatestVersion: 1, ProductionVersion: &production}
+	cpCodeRules := papi.Rules{
+		Name:      "default",
+		Behaviors: []papi.RuleBehavior{{Name: "cpCode", Options: papi.RuleOptionsMap{"value": map[string]interface{}{"id": float64(123)}}}},
+	}
+	client := &mockpapi{}
+	client.On("GetProperties", AnyCTX, papi.GetPropertiesRequest{ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(&papi.GetPropertiesResponse{Properties: papi.PropertiesItems{Items: []*papi.Property{&exported, &failing}}}, nil).Once()
+	for _, property := range []*papi.Property{&failing, &exported} {
+		client.On("GetProperty", AnyCTX, papi.GetPropertyRequest{PropertyID: property.PropertyID, ContractID: "ctr_1", GroupID: "grp_2"}).
+			Return(&papi.GetPropertyResponse{Property: property}, nil).Once()
+	}
+	client.On("GetPropertyVersion", AnyCTX, mock.AnythingOfType("papi.GetPropertyVersionRequest")).
+		Return(&papi.GetPropertyVersionsResponse{Version: papi.PropertyVersionGetItem{ProductID: "prd_Fresca"}}, nil).Twice()
+	client.On("GetPropertyVersionHostnames", AnyCTX, mock.AnythingOfType("papi.GetPropertyVersionHostnamesRequest")).
+		Return(&papi.GetPropertyVersionHostnamesResponse{}, nil).Twice()
+	client.On("GetRuleTree", AnyCTX, mock.MatchedBy(func(req papi.GetRuleTreeRequest) bool { return req.PropertyID == "prp_1" })).
+		Return(&papi.GetRuleTreeResponse{RuleFormat: "v2020-11-02", Rules: cpCodeRules}, nil).Once()
+	client.On("GetRuleTree", AnyCTX, mock.MatchedBy(func(req papi.GetRuleTreeRequest) bool { return req.PropertyID == "prp_2" })).
+		Return(&papi.GetRuleTreeResponse{RuleFormat: "v2020-11-02", Rules: papi.Rules{Name: "default"}}, nil).Once()
+	client.On("GetCPCodes", AnyCTX, papi.GetCPCodesRequest{ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(&papi.GetCPCodesResponse{CPCodes: papi.CPCodeItems{Items: []papi.CPCode{{ID: "cpc_123", Name: "Example site"}}}}, nil).Once()
+	client.On("GetActivations", AnyCTX, papi.GetActivationsRequest{PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(nil, errors.New("oops")).Once()
+	// the active version was activated before the activations which are returned
+	client.On("GetActivations", AnyCTX, papi.GetActivationsRequest{PropertyID: "prp_2", ContractID: "ctr_1", GroupID: "grp_2"}).
+		Return(&papi.GetActivationsResponse{}, nil).Once()
+
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files, failures, err := exportProperties(context.Background(), client, ExportOptions{ContractID: "ctr_1", GroupID: "grp_2", Dir: dir})
+	require.NoError(t, err)
+	client.AssertExpectations(t)
+
+	assert.Equal(t, []string{"property a.example.com is not exported: oops"}, failures)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "property-snippets/b_example_com/main.json"),
+		filepath.Join(dir, "property_b_example_com.tf"),
+		filepath.Join(dir, "import.sh"),
+	}, files)
+	_, err = os.Stat(filepath.Join(dir, "property-snippets/a_example_com"))
+	assert.True(t, os.IsNotExist(err))
+
+	property, err := ioutil.ReadFile(filepath.Join(dir, "property_b_example_com.tf"))
+	require.NoError(t, err)
+	assert.Contains(t, string(property), "# NOTE: version 1 is active on PRODUCTION without an activation to take the contacts from, its activation is not exported\n")
+	assert.NotContains(t, string(property), "akamai_property_activation")
+
+	imports, err := ioutil.ReadFile(filepath.Join(dir, "import.sh"))
+	require.NoError(t, err)
+	assert.Contains(t, string(imports), "# NOTE: property a.example.com is not exported: oops\n\nterraform import akamai_property.b_example_com prp_2,ctr_1,grp_2\n")
+	assert.NotContains(t, string(imports), "akamai_cp_code")
+}
+
+func TestExportResourceName(t *testing.T) {
+	e := &propertyExporter{names: make(map[string]map[string]bool)}
+	assert.Equal(t, "www_example_com", e.resourceName("akamai_property", "www.example.com"))
+	assert.Equal(t, "www_example_com_2", e.resourceName("akamai_property", "WWW Example-com"))
+	assert.Equal(t, "www_example_com", e.resourceName("akamai_cp_code", "www.example.com"))
+	assert.Equal(t, "_1st_site", e.resourceName("akamai_property", "1st site"))
+	assert.Equal(t, "property", e.resourceName("akamai_property", "!!!"))
+}
+
+func TestHCLString(t *testing.T) {
+	assert.Equal(t, `"a \"b\" $${c} %%{d}"`, hclString(`a "b" ${c} %{d}`))
+	assert.Equal(t, `"${path.module}/main.json"`, hclString("${path.module}/main.json"))
+}
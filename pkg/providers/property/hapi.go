@@ -0,0 +1,142 @@
+package property
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+// HAPI Edge Hostnames
+//
+// The edgegrid library has no client for the Edge Hostnames API (HAPI), which changes and deletes the edge hostnames
+// PAPI creates. Its calls go through the session. HAPI addresses an edge hostname by record name and DNS zone, like
+// the record www.example.com in the zone edgesuite.net, and applies changes asynchronously through change requests.
+//
+// https://developer.akamai.com/api/core_features/edge_hostnames/v1.html
+
+type (
+	// hapi is the subset of the Edge Hostnames API used by akamai_edge_hostname
+	hapi interface {
+		// GetEdgeHostname returns the edge hostname of the record in the DNS zone
+		GetEdgeHostname(ctx context.Context, recordName, dnsZone string) (*hapiEdgeHostname, error)
+
+		// UpdateEdgeHostname applies a JSON patch to the edge hostname, the change completes asynchronously
+		UpdateEdgeHostname(ctx context.Context, r hapiUpdateEdgeHostnameRequest) (*hapiChangeRequest, error)
+
+		// DeleteEdgeHostname deletes the edge hostname, the deletion completes asynchronously
+		DeleteEdgeHostname(ctx context.Context, r hapiDeleteEdgeHostnameRequest) (*hapiChangeRequest, error)
+
+		// GetChangeRequest returns the status of a change request
+		GetChangeRequest(ctx context.Context, changeID int) (*hapiChangeRequest, error)
+	}
+
+	hapiClient struct {
+		session.Session
+	}
+
+	hapiEdgeHostname struct {
+		EdgeHostnameID    int    `json:"edgeHostnameId"`
+		RecordName        string `json:"recordName"`
+		DNSZone           string `json:"dnsZone"`
+		SecurityType      string `json:"securityType"`
+		UseDefaultTTL     bool   `json:"useDefaultTtl"`
+		UseDefaultMap     bool   `json:"useDefaultMap"`
+		TTL               int    `json:"ttl"`
+		Map               string `json:"map"`
+		SlotNumber        int    `json:"slotNumber,omitempty"`
+		IPVersionBehavior string `json:"ipVersionBehavior"`
+		Comments          string `json:"comments,omitempty"`
+	}
+
+	hapiChangeRequest struct {
+		ChangeID      int    `json:"changeId"`
+		Action        string `json:"action"`
+		Status        string `json:"status"`
+		StatusMessage string `json:"statusMessage,omitempty"`
+	}
+
+	hapiUpdateEdgeHostnameRequest struct {
+		RecordName        string
+		DNSZone           string
+		Patch             []tools.JSONPatchOperation
+		Comments          string
+		StatusUpdateEmail []string
+	}
+
+	hapiDeleteEdgeHostnameRequest struct {
+		RecordName        string
+		DNSZone           string
+		Comments          string
+		StatusUpdateEmail []string
+	}
+)
+
+const (
+	hapiChangeStatusSucceeded = "SUCCEEDED"
+	hapiChangeStatusFailed    = "FAILED"
+)
+
+func newHAPIClient(sess session.Session) hapi {
+	return &hapiClient{Session: sess}
+}
+
+func (c *hapiClient) GetEdgeHostname(ctx context.Context, recordName, dnsZone string) (*hapiEdgeHostname, error) {
+	var rval hapiEdgeHostname
+	if err := execAPI(ctx, c.Session, ErrHAPI, http.MethodGet, hapiEdgeHostnamePath(recordName, dnsZone, "", nil), "", nil, &rval, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &rval, nil
+}
+
+func (c *hapiClient) UpdateEdgeHostname(ctx context.Context, r hapiUpdateEdgeHostnameRequest) (*hapiChangeRequest, error) {
+	var rval hapiChangeRequest
+	path := hapiEdgeHostnamePath(r.RecordName, r.DNSZone, r.Comments, r.StatusUpdateEmail)
+	if err := execAPI(ctx, c.Session, ErrHAPI, http.MethodPatch, path, "application/json-patch+json", r.Patch, &rval, http.StatusAccepted); err != nil {
+		return nil, err
+	}
+
+	return &rval, nil
+}
+
+func (c *hapiClient) DeleteEdgeHostname(ctx context.Context, r hapiDeleteEdgeHostnameRequest) (*hapiChangeRequest, error) {
+	var rval hapiChangeRequest
+	path := hapiEdgeHostnamePath(r.RecordName, r.DNSZone, r.Comments, r.StatusUpdateEmail)
+	if err := execAPI(ctx, c.Session, ErrHAPI, http.MethodDelete, path, "", nil, &rval, http.StatusAccepted); err != nil {
+		return nil, err
+	}
+
+	return &rval, nil
+}
+
+func (c *hapiClient) GetChangeRequest(ctx context.Context, changeID int) (*hapiChangeRequest, error) {
+	var rval hapiChangeRequest
+	if err := execAPI(ctx, c.Session, ErrHAPI, http.MethodGet, fmt.Sprintf("/hapi/v1/change-requests/%d", changeID), "", nil, &rval, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &rval, nil
+}
+
+func hapiEdgeHostnamePath(recordName, dnsZone, comments string, statusUpdateEmail []string) string {
+	path := fmt.Sprintf("/hapi/v1/edge-hostnames/%s/%s", url.PathEscape(recordName), url.PathEscape(dnsZone))
+
+	query := url.Values{}
+	if comments != "" {
+		query.Set("comments", comments)
+	}
+	if len(statusUpdateEmail) > 0 {
+		query.Set("statusUpdateEmail", strings.Join(statusUpdateEmail, ","))
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	return path
+}
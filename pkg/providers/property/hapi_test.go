@@ -0,0 +1,60 @@
+package property
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockhapi struct {
+	mock.Mock
+}
+
+func (h *mockhapi) GetEdgeHostname(ctx context.Context, recordName, dnsZone string) (*hapiEdgeHostname, error) {
+	args := h.Called(ctx, recordName, dnsZone)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*hapiEdgeHostname), args.Error(1)
+}
+
+func (h *mockhapi) UpdateEdgeHostname(ctx context.Context, r hapiUpdateEdgeHostnameRequest) (*hapiChangeRequest, error) {
+	args := h.Called(ctx, r)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*hapiChangeRequest), args.Error(1)
+}
+
+func (h *mockhapi) DeleteEdgeHostname(ctx context.Context, r hapiDeleteEdgeHostnameRequest) (*hapiChangeRequest, error) {
+	args := h.Called(ctx, r)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*hapiChangeRequest), args.Error(1)
+}
+
+func (h *mockhapi) GetChangeRequest(ctx context.Context, changeID int) (*hapiChangeRequest, error) {
+	args := h.Called(ctx, changeID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*hapiChangeRequest), args.Error(1)
+}
+
+func TestHAPIEdgeHostnamePath(t *testing.T) {
+	assert.Equal(t, "/hapi/v1/edge-hostnames/www.example.com/edgesuite.net",
+		hapiEdgeHostnamePath("www.example.com", "edgesuite.net", "", nil))
+	assert.Equal(t, "/hapi/v1/edge-hostnames/www.example.com/edgekey.net?comments=ipv6+rollout&statusUpdateEmail=a%40example.com%2Cb%40example.com",
+		hapiEdgeHostnamePath("www.example.com", "edgekey.net", "ipv6 rollout", []string{"a@example.com", "b@example.com"}))
+}
@@ -0,0 +1,63 @@
+package property
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+)
+
+// PAPI Activations with a compliance record
+//
+// Accounts with a change management process require a compliance record on the production activations. The
+// edgegrid library does not send the record, the activations with a record go through the session.
+//
+// https://developer.akamai.com/api/core_features/property_manager/v1.html#postpropertyactivations
+
+type (
+	// papiCompliance is the subset of PAPI used to create the activations with a compliance record
+	papiCompliance interface {
+		// CreateActivation creates the activation with its compliance record
+		CreateActivation(ctx context.Context, propertyID string, activation activationWithCompliance) (*papi.CreateActivationResponse, error)
+	}
+
+	papiComplianceClient struct {
+		session.Session
+	}
+
+	// complianceRecord is the change management record of an activation
+	complianceRecord struct {
+		NoncomplianceReason string `json:"noncomplianceReason"`
+		TicketID            string `json:"ticketId,omitempty"`
+		PeerReviewedBy      string `json:"peerReviewedBy,omitempty"`
+		CustomerEmail       string `json:"customerEmail,omitempty"`
+	}
+
+	// activationWithCompliance is the activation request body with a compliance record
+	activationWithCompliance struct {
+		papi.Activation
+		ComplianceRecord *complianceRecord `json:"complianceRecord"`
+	}
+)
+
+func newPAPIComplianceClient(sess session.Session) papiCompliance {
+	return &papiComplianceClient{Session: sess}
+}
+
+func (c *papiComplianceClient) CreateActivation(ctx context.Context, propertyID string, activation activationWithCompliance) (*papi.CreateActivationResponse, error) {
+	var rval papi.CreateActivationResponse
+	path := fmt.Sprintf("/papi/v1/properties/%s/activations", propertyID)
+	if err := execAPI(ctx, c.Session, ErrActivationCreate, http.MethodPost, path, "application/json", activation, &rval, http.StatusCreated); err != nil {
+		return nil, err
+	}
+
+	id, err := papi.ResponseLinkParse(rval.ActivationLink)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrActivationCreate, err)
+	}
+	rval.ActivationID = id
+
+	return &rval, nil
+}
@@ -0,0 +1,89 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockcompliance struct {
+	mock.Mock
+}
+
+func (c *mockcompliance) CreateActivation(ctx context.Context, propertyID string, activation activationWithCompliance) (*papi.CreateActivationResponse, error) {
+	args := c.Called(ctx, propertyID, activation)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*papi.CreateActivationResponse), args.Error(1)
+}
+
+func TestPAPIComplianceClient(t *testing.T) {
+	var body map[string]interface{}
+	status := http.StatusCreated
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/papi/v1/properties/prp_1/activations", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusCreated {
+			_, _ = w.Write([]byte(`{"activationLink": "/papi/v1/properties/prp_1/activations/atv_1?contractId=ctr_1&groupId=grp_1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"type": "compliance", "title": "Missing compliance record", "detail": "a ticket is required"}`))
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	sess, err := session.New(
+		session.WithClient(srv.Client()),
+		session.WithSigner(&edgegrid.Config{Host: u.Host, MaxBody: edgegrid.MaxBodySize}),
+	)
+	require.NoError(t, err)
+	client := newPAPIComplianceClient(sess)
+
+	activation := activationWithCompliance{
+		Activation: papi.Activation{
+			ActivationType:  papi.ActivationTypeActivate,
+			Network:         papi.ActivationNetworkProduction,
+			PropertyVersion: 2,
+			NotifyEmails:    []string{"user@example.com"},
+		},
+		ComplianceRecord: &complianceRecord{NoncomplianceReason: ComplianceReasonNone, TicketID: "CHG-123"},
+	}
+
+	t.Run("activation created with the record", func(t *testing.T) {
+		res, err := client.CreateActivation(context.Background(), "prp_1", activation)
+		require.NoError(t, err)
+		assert.Equal(t, "atv_1", res.ActivationID)
+		assert.Equal(t, map[string]interface{}{"noncomplianceReason": "NONE", "ticketId": "CHG-123"}, body["complianceRecord"])
+		assert.Equal(t, "PRODUCTION", body["network"])
+	})
+
+	t.Run("problem response", func(t *testing.T) {
+		status = http.StatusBadRequest
+		_, err := client.CreateActivation(context.Background(), "prp_1", activation)
+		require.True(t, errors.Is(err, ErrActivationCreate))
+		var e *apiError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, http.StatusBadRequest, e.StatusCode)
+		assert.Equal(t, "Missing compliance record", e.Title)
+	})
+}
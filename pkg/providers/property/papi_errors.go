@@ -57,11 +57,40 @@ var (
 	ErrPropertyNotFound = errors.New("property not found")
 	// ErrRulesNotFound is returned when no rules were found
 	ErrRulesNotFound = errors.New("property rules not found")
+	// ErrPropertyImportAmbiguous is returned when the name or hostname of an import or an export matches several properties
+	ErrPropertyImportAmbiguous = errors.New("several properties match")
 
 	// PAPI property version errors
 
 	// ErrVersionCreate represents an error while creating new property version
 	ErrVersionCreate = errors.New("creating property version")
+	// ErrVersionNote represents an error while setting the notes of a property version
+	ErrVersionNote = errors.New("setting property version notes")
+	// ErrVersionNotFound is returned when a property version does not exist anymore
+	ErrVersionNotFound = errors.New("property version not found")
+
+	// PAPI Activation errors
+
+	// ErrActivationCreate represents an error while creating an activation
+	ErrActivationCreate = errors.New("creating activation")
+	// ErrActivationAborted is returned when the activation was aborted
+	ErrActivationAborted = errors.New("activation request aborted")
+	// ErrActivationFailed is returned when the activation failed downstream from the API
+	ErrActivationFailed = errors.New("activation request failed in downstream system")
+	// ErrActivationRollback is returned when the rollback of a failed activation did not complete
+	ErrActivationRollback = errors.New("activation rollback")
+	// ErrRollbackVersion is returned when the rollback version is the version to activate
+	ErrRollbackVersion = errors.New("rollback_to_version must differ from version")
+
+	// PAPI rules patch errors
+
+	// ErrRulesPatch represents an error while patching the rules of a property
+	ErrRulesPatch = errors.New("patching property rules")
+
+	// PAPI property export errors
+
+	// ErrPropertyExport represents an error while exporting the configuration of properties
+	ErrPropertyExport = errors.New("exporting properties")
 
 	// PAPI rule format errors
 
@@ -71,6 +100,28 @@ var (
 	// ErrEdgeHostnameNotFound is returned when no edgehostname were found
 	ErrEdgeHostnameNotFound = errors.New("unable to find edge hostname")
 
+	// HAPI edge hostname errors
+
+	// ErrHAPI represents an error response or a failed request of the Edge Hostnames API
+	ErrHAPI = errors.New("edge hostnames API")
+	// ErrEdgeHostnameUpdate represents an error while changing an edge hostname
+	ErrEdgeHostnameUpdate = errors.New("updating edge hostname")
+	// ErrEdgeHostnameDelete represents an error while deleting an edge hostname
+	ErrEdgeHostnameDelete = errors.New("deleting edge hostname")
+	// ErrEdgeHostnameInUse is returned when an edge hostname to delete is still referenced by properties
+	ErrEdgeHostnameInUse = errors.New("edge hostname is used by properties")
+	// ErrEdgeHostnameSecureNetwork is returned when the domain suffix of an edge hostname does not support its secure network
+	ErrEdgeHostnameSecureNetwork = errors.New("unsupported edge hostname domain suffix and secure network")
+
+	// CPRG CP code and reporting group errors
+
+	// ErrCPRG represents an error response or a failed request of the CP Codes and Reporting Groups API
+	ErrCPRG = errors.New("CP codes and reporting groups API")
+	// ErrCPCodeUpdate represents an error while renaming a CP code
+	ErrCPCodeUpdate = errors.New("updating CP code")
+	// ErrReportingGroupID is returned when a reporting group ID or a CP code ID of a reporting group is not a number
+	ErrReportingGroupID = errors.New("invalid reporting group or CP code ID")
+
 	// DiagWarnActivationTimeout returned on activation poll timeout
 	DiagWarnActivationTimeout = diag.Diagnostic{
 		Severity: diag.Warning,
@@ -0,0 +1,48 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+)
+
+// PAPI Rule format schemas
+//
+// The rules are validated during plan against the JSON schema of the product and rule format. The edgegrid library
+// does not fetch the schemas, they go through the session.
+//
+// https://developer.akamai.com/api/core_features/property_manager/v1.html#getschemaproductruleformat
+
+type (
+	// papiSchemas is the subset of PAPI used to fetch the rule format schemas
+	papiSchemas interface {
+		// GetRuleSchema returns the JSON schema of the rules of a product and rule format
+		GetRuleSchema(ctx context.Context, productID, ruleFormat string) (json.RawMessage, error)
+	}
+
+	papiSchemasClient struct {
+		session.Session
+	}
+)
+
+func newPAPISchemasClient(sess session.Session) papiSchemas {
+	return &papiSchemasClient{Session: sess}
+}
+
+func (c *papiSchemasClient) GetRuleSchema(ctx context.Context, productID, ruleFormat string) (json.RawMessage, error) {
+	var rval json.RawMessage
+	path := fmt.Sprintf("/papi/v1/schemas/products/%s/%s", productID, ruleFormat)
+	if err := execAPI(ctx, c.Session, ErrRuleSchema, http.MethodGet, path, "", nil, &rval, http.StatusOK); err != nil {
+		var e *apiError
+		if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+			return nil, errRuleSchemaNotFound
+		}
+		return nil, err
+	}
+
+	return rval, nil
+}
@@ -0,0 +1,78 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockschemas struct {
+	mock.Mock
+}
+
+func (c *mockschemas) GetRuleSchema(ctx context.Context, productID, ruleFormat string) (json.RawMessage, error) {
+	args := c.Called(ctx, productID, ruleFormat)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(json.RawMessage), args.Error(1)
+}
+
+func TestPAPISchemasClient(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/papi/v1/schemas/products/prd_Web_App_Accel/v2020-03-04", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(`{"definitions": {"catalog": {}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"type": "not_found", "title": "Not Found"}`))
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	sess, err := session.New(
+		session.WithClient(srv.Client()),
+		session.WithSigner(&edgegrid.Config{Host: u.Host, MaxBody: edgegrid.MaxBodySize}),
+	)
+	require.NoError(t, err)
+	client := newPAPISchemasClient(sess)
+
+	t.Run("schema fetched", func(t *testing.T) {
+		schema, err := client.GetRuleSchema(context.Background(), "prd_Web_App_Accel", "v2020-03-04")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"definitions": {"catalog": {}}}`, string(schema))
+	})
+
+	t.Run("schema not found", func(t *testing.T) {
+		status = http.StatusNotFound
+		_, err := client.GetRuleSchema(context.Background(), "prd_Web_App_Accel", "v2020-03-04")
+		assert.True(t, errors.Is(err, errRuleSchemaNotFound), "want %v, got %v", errRuleSchemaNotFound, err)
+	})
+
+	t.Run("problem response", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		_, err := client.GetRuleSchema(context.Background(), "prd_Web_App_Accel", "v2020-03-04")
+		require.True(t, errors.Is(err, ErrRuleSchema))
+		var e *apiError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, http.StatusInternalServerError, e.StatusCode)
+	})
+}
@@ -0,0 +1,53 @@
+package property
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+)
+
+// PAPI Property version notes
+//
+// The notes of a version are the comments of its rule tree. papi.RulesUpdate does not hold the comments, the edgegrid
+// library can not set them, so they go through the session as a JSON patch of the rule tree.
+//
+// https://developer.akamai.com/api/core_features/property_manager/v1.html#patchpropertyversionrules
+
+type (
+	// papiVersionNotes is the subset of PAPI used to set the notes of the property versions
+	papiVersionNotes interface {
+		// UpdateVersionNotes sets the notes of the latest version of the property
+		UpdateVersionNotes(ctx context.Context, property papi.Property, notes string) error
+	}
+
+	papiVersionNotesClient struct {
+		session.Session
+	}
+
+	// versionNotesPatch is a JSON patch operation of the rule tree
+	versionNotesPatch struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value string `json:"value"`
+	}
+)
+
+func newPAPIVersionNotesClient(sess session.Session) papiVersionNotes {
+	return &papiVersionNotesClient{Session: sess}
+}
+
+func (c *papiVersionNotesClient) UpdateVersionNotes(ctx context.Context, property papi.Property, notes string) error {
+	path := fmt.Sprintf(
+		"/papi/v1/properties/%s/versions/%d/rules?contractId=%s&groupId=%s&validateRules=false",
+		property.PropertyID,
+		property.LatestVersion,
+		property.ContractID,
+		property.GroupID,
+	)
+	patch := []versionNotesPatch{{Op: "add", Path: "/comments", Value: notes}}
+
+	return execAPI(ctx, c.Session, ErrVersionNote, http.MethodPatch, path, "application/json-patch+json", patch, nil, http.StatusOK)
+}
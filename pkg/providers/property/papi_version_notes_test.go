@@ -0,0 +1,86 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockversionnotes struct {
+	mock.Mock
+}
+
+func (c *mockversionnotes) UpdateVersionNotes(ctx context.Context, property papi.Property, notes string) error {
+	args := c.Called(ctx, property, notes)
+
+	return args.Error(0)
+}
+
+// ExpectUpdateVersionNotes sets up an expected call to the version notes client that sets the notes of the given
+// version
+func ExpectUpdateVersionNotes(client *mockversionnotes, PropertyID string, Version int, Notes string) *mock.Call {
+	match := func(p papi.Property) bool {
+		return p.PropertyID == PropertyID && p.LatestVersion == Version
+	}
+
+	return client.On("UpdateVersionNotes", AnyCTX, mock.MatchedBy(match), Notes).Return(nil)
+}
+
+func TestPAPIVersionNotesClient(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/papi/v1/properties/prp_1/versions/3/rules", r.URL.Path)
+		assert.Equal(t, "contractId=ctr_1&groupId=grp_1&validateRules=false", r.URL.RawQuery)
+		assert.Equal(t, "application/json-patch+json", r.Header.Get("Content-Type"))
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"op": "add", "path": "/comments", "value": "release notes"}]`, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(`{"rules": {"name": "default", "comments": "release notes"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"type": "forbidden", "title": "Forbidden"})
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	sess, err := session.New(
+		session.WithClient(srv.Client()),
+		session.WithSigner(&edgegrid.Config{Host: u.Host, MaxBody: edgegrid.MaxBodySize}),
+	)
+	require.NoError(t, err)
+	client := newPAPIVersionNotesClient(sess)
+	property := papi.Property{PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_1", LatestVersion: 3}
+
+	t.Run("notes set", func(t *testing.T) {
+		require.NoError(t, client.UpdateVersionNotes(context.Background(), property, "release notes"))
+	})
+
+	t.Run("problem response", func(t *testing.T) {
+		status = http.StatusForbidden
+		err := client.UpdateVersionNotes(context.Background(), property, "release notes")
+		require.True(t, errors.Is(err, ErrVersionNote))
+		var e *apiError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, http.StatusForbidden, e.StatusCode)
+		assert.Equal(t, "Forbidden", e.Title)
+	})
+}
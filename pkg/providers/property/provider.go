@@ -21,7 +21,12 @@ type (
 	provider struct {
 		*schema.Provider
 
-		client papi.PAPI
+		client        papi.PAPI
+		edgeHostnames hapi
+		cpCodes       cprg
+		compliance    papiCompliance
+		schemas       papiSchemas
+		versionNotes  papiVersionNotes
 	}
 
 	// Option is a papi provider option
@@ -75,21 +80,27 @@ func Provider() *schema.Provider {
 			"akamai_contract":                dataSourcePropertyContract(),
 			"akamai_contracts":               dataSourceAkamaiContracts(),
 			"akamai_cp_code":                 dataSourceCPCode(),
+			"akamai_edge_hostnames":          dataSourceAkamaiEdgeHostnames(),
 			"akamai_group":                   dataSourcePropertyGroup(),
 			"akamai_groups":                  dataSourcePropertyMultipleGroups(),
 			"akamai_property_rules":          dataPropertyRules(),
 			"akamai_property_rule_formats":   dataPropertyRuleFormats(),
 			"akamai_property":                dataSourceAkamaiProperty(),
 			"akamai_property_rules_template": dataSourcePropertyRulesTemplate(),
+			"akamai_property_rules_builder":  dataSourcePropertyRulesBuilder(),
 			"akamai_properties":              dataSourceAkamaiProperties(),
 			"akamai_property_products":       dataSourceAkamaiPropertyProducts(),
+			"akamai_property_search":         dataSourceAkamaiPropertySearch(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"akamai_cp_code":             resourceCPCode(),
-			"akamai_edge_hostname":       resourceSecureEdgeHostName(),
-			"akamai_property":            resourceProperty(),
-			"akamai_property_variables":  resourcePropertyVariables(),
-			"akamai_property_activation": resourcePropertyActivation(),
+			"akamai_cp_code":              resourceCPCode(),
+			"akamai_edge_hostname":        resourceSecureEdgeHostName(),
+			"akamai_property":             resourceProperty(),
+			"akamai_property_variables":   resourcePropertyVariables(),
+			"akamai_property_activation":  resourcePropertyActivation(),
+			"akamai_property_version":     resourcePropertyVersion(),
+			"akamai_property_rules_patch": resourcePropertyRulesPatch(),
+			"akamai_reporting_group":      resourceReportingGroup(),
 		},
 	}
 	return provider
@@ -110,6 +121,46 @@ func (p *provider) Client(meta akamai.OperationMeta) papi.PAPI {
 	return papi.Client(meta.Session())
 }
 
+// HAPIClient returns the Edge Hostnames API interface
+func (p *provider) HAPIClient(meta akamai.OperationMeta) hapi {
+	if p.edgeHostnames != nil {
+		return p.edgeHostnames
+	}
+	return newHAPIClient(meta.Session())
+}
+
+// CPRGClient returns the CP Codes and Reporting Groups API interface
+func (p *provider) CPRGClient(meta akamai.OperationMeta) cprg {
+	if p.cpCodes != nil {
+		return p.cpCodes
+	}
+	return newCPRGClient(meta.Session())
+}
+
+// ComplianceClient returns the interface creating the PAPI activations with a compliance record
+func (p *provider) ComplianceClient(meta akamai.OperationMeta) papiCompliance {
+	if p.compliance != nil {
+		return p.compliance
+	}
+	return newPAPIComplianceClient(meta.Session())
+}
+
+// SchemasClient returns the interface fetching the PAPI rule format schemas
+func (p *provider) SchemasClient(meta akamai.OperationMeta) papiSchemas {
+	if p.schemas != nil {
+		return p.schemas
+	}
+	return newPAPISchemasClient(meta.Session())
+}
+
+// VersionNotesClient returns the interface setting the notes of the PAPI property versions
+func (p *provider) VersionNotesClient(meta akamai.OperationMeta) papiVersionNotes {
+	if p.versionNotes != nil {
+		return p.versionNotes
+	}
+	return newPAPIVersionNotesClient(meta.Session())
+}
+
 func getPAPIV1Service(d *schema.ResourceData) error {
 	var inlineConfig *schema.Set
 	for _, key := range []string{"property", "config"} {
@@ -0,0 +1,89 @@
+package property
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/test/apiserver"
+)
+
+// TestProviderAPIServer runs the provider end to end against the local stand-in of the Akamai APIs, the requests are
+// signed and sent by the sessions of the provider and the PAPI client like they are for the real API
+func TestProviderAPIServer(t *testing.T) {
+	ctx := context.Background()
+	srv := apiserver.NewServer()
+	defer srv.Close()
+
+	// The stand-in has a self-signed certificate, its client trusts it
+	akamai.SetHTTPTransport(srv.Client().Transport)
+	defer akamai.SetHTTPTransport(nil)
+
+	diags := testProvider.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"config": []interface{}{map[string]interface{}{
+			"host":          srv.Host(),
+			"access_token":  "akab-access-token-xxx-xxxxxxxxxxxxxxxx",
+			"client_token":  "akab-client-token-xxx-xxxxxxxxxxxxxxxx",
+			"client_secret": "client-secret-xxxxxxxxxxxxxxxxxxxxxxxxxxxxxx=",
+		}},
+	}))
+	require.False(t, diags.HasError(), "%v", diags)
+	meta := testProvider.Meta()
+
+	// apply plans the configuration against the state, applies it and checks that nothing is planned afterwards
+	apply := func(t *testing.T, name string, state *terraform.InstanceState, cfg map[string]interface{}) *terraform.InstanceState {
+		res := testProvider.ResourcesMap[name]
+		rc := terraform.NewResourceConfigRaw(cfg)
+
+		diff, err := res.SimpleDiff(ctx, state, rc, meta)
+		require.NoError(t, err)
+		state, diags := res.Apply(ctx, state, diff, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		state, diags = res.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		diff, err = res.SimpleDiff(ctx, state, rc, meta)
+		require.NoError(t, err)
+		for k, attr := range diff.Attributes {
+			assert.True(t, attr.NewComputed, "unexpected change of %s: %v", k, attr)
+		}
+
+		return state
+	}
+
+	t.Run("contracts", func(t *testing.T) {
+		state, diags := testProvider.DataSourcesMap["akamai_contracts"].ReadDataApply(ctx, &terraform.InstanceDiff{}, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, apiserver.ContractID, state.Attributes["contracts.0.contract_id"])
+	})
+
+	t.Run("cp code", func(t *testing.T) {
+		state := apply(t, "akamai_cp_code", nil, map[string]interface{}{
+			"name":        "test cpcode",
+			"contract_id": apiserver.ContractID,
+			"group_id":    apiserver.GroupID,
+			"product":     "prd_Web_App_Accel",
+		})
+		assert.NotEmpty(t, state.ID)
+		assert.Equal(t, "test cpcode", state.Attributes["name"])
+	})
+
+	t.Run("property", func(t *testing.T) {
+		cfg := map[string]interface{}{
+			"name":        "test property",
+			"contract_id": apiserver.ContractID,
+			"group_id":    apiserver.GroupID,
+			"product_id":  "prd_Web_App_Accel",
+		}
+		state := apply(t, "akamai_property", nil, cfg)
+		assert.Equal(t, "1", state.Attributes["latest_version"])
+
+		cfg["hostnames"] = map[string]interface{}{"www.example.com": "www.example.com.edgesuite.net"}
+		state = apply(t, "akamai_property", state, cfg)
+		assert.Equal(t, "www.example.com.edgesuite.net", state.Attributes["hostnames.www.example.com"])
+	})
+}
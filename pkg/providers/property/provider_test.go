@@ -1,6 +1,7 @@
 package property
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 var testAccProviders map[string]*schema.Provider
@@ -32,6 +34,26 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+// testMeta configures the test provider with dummy credentials and returns its meta, for the tests driving a resource
+// through the plugin SDK when the import state must carry over to the next apply
+func testMeta(t *testing.T) interface{} {
+	t.Helper()
+
+	cfg := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"config": []interface{}{map[string]interface{}{
+			"host":          "akaa-test.luna.akamaiapis.net",
+			"access_token":  "akab-access",
+			"client_token":  "akab-client",
+			"client_secret": "secret",
+		}},
+	})
+	if diags := testProvider.Configure(context.Background(), cfg); diags.HasError() {
+		t.Fatalf("could not configure provider: %v", diags)
+	}
+
+	return testProvider.Meta()
+}
+
 func testAccPreCheck(t *testing.T) {
 	TODO(t, "Check not implemented")
 }
@@ -57,6 +79,56 @@ func useClient(client papi.PAPI, f func()) {
 	f()
 }
 
+// Only allow one test at a time to patch the HAPI client via useHAPIClient(), it is usually nested in useClient()
+var hapiClientLock sync.Mutex
+
+// useHAPIClient swaps out the HAPI client on the global instance for the duration of the given func
+func useHAPIClient(client hapi, f func()) {
+	hapiClientLock.Lock()
+	orig := inst.edgeHostnames
+	inst.edgeHostnames = client
+
+	defer func() {
+		inst.edgeHostnames = orig
+		hapiClientLock.Unlock()
+	}()
+
+	f()
+}
+
+var cprgClientLock sync.Mutex
+
+// useCPRGClient swaps out the CPRG client on the global instance for the duration of the given func
+func useCPRGClient(client cprg, f func()) {
+	cprgClientLock.Lock()
+	orig := inst.cpCodes
+	inst.cpCodes = client
+
+	defer func() {
+		inst.cpCodes = orig
+		cprgClientLock.Unlock()
+	}()
+
+	f()
+}
+
+var complianceClientLock sync.Mutex
+
+// useComplianceClient swaps out the client of the activations with a compliance record on the global instance for the
+// duration of the given func
+func useComplianceClient(client papiCompliance, f func()) {
+	complianceClientLock.Lock()
+	orig := inst.compliance
+	inst.compliance = client
+
+	defer func() {
+		inst.compliance = orig
+		complianceClientLock.Unlock()
+	}()
+
+	f()
+}
+
 // TODO marks a test as being in a "pending" state and logs a message telling the user why. Such tests are expected to
 // fail for the time being and may exist for the sake of unfinished/future features or to document known buggy cases
 // that won't be fixed right away. The failure of a pending test is not considered an error and the test will therefore
@@ -130,3 +202,37 @@ type T struct{ *testing.T }
 func (t T) FailNow() {
 	t.T.Fatalf("FAIL: %s", t.T.Name())
 }
+
+var schemasClientLock sync.Mutex
+
+// useSchemasClient swaps out the client of the rule format schemas on the global instance for the duration of the
+// given func
+func useSchemasClient(client papiSchemas, f func()) {
+	schemasClientLock.Lock()
+	orig := inst.schemas
+	inst.schemas = client
+
+	defer func() {
+		inst.schemas = orig
+		schemasClientLock.Unlock()
+	}()
+
+	f()
+}
+
+var versionNotesClientLock sync.Mutex
+
+// useVersionNotesClient swaps out the client of the property version notes on the global instance for the duration of
+// the given func
+func useVersionNotesClient(client papiVersionNotes, f func()) {
+	versionNotesClientLock.Lock()
+	orig := inst.versionNotes
+	inst.versionNotes = client
+
+	defer func() {
+		inst.versionNotes = orig
+		versionNotesClientLock.Unlock()
+	}()
+
+	f()
+}
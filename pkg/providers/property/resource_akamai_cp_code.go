@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/apex/log"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -15,25 +16,30 @@ import (
 
 // PAPI CP Code
 //
+// PAPI creates the CP codes, the CP Codes and Reporting Groups API renames them.
+//
 // https://developer.akamai.com/api/luna/papi/data.html#cpcode
 // https://developer.akamai.com/api/luna/papi/resources.html#cpcodesapi
+// https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#putcpcode
 func resourceCPCode() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceCPCodeCreate,
 		ReadContext:   resourceCPCodeRead,
+		UpdateContext: resourceCPCodeUpdate,
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceCPCodeImport,
 		},
 
 		// NB: CP Codes cannot be deleted https://developer.akamai.com/api/luna/papi/resources.html#cpcodesapi
-		DeleteContext: schema.NoopContext,
+		DeleteContext: resourceCPCodeDelete,
+		CustomizeDiff: warnCPCodeReplaced,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:             schema.TypeString,
 				Required:         true,
-				ForceNew:         true,
 				ValidateDiagFunc: tools.IsNotBlank,
+				Description:      "The name of the CP code, renaming it updates the CP code in place",
 			},
 			"contract": {
 				Type:       schema.TypeString,
@@ -68,6 +74,7 @@ func resourceCPCode() *schema.Resource {
 				Required:         true,
 				ForceNew:         true,
 				ValidateDiagFunc: tools.IsNotBlank,
+				Description:      "The product of the CP code, changing it creates another CP code as CP codes cannot be deleted",
 			},
 		},
 	}
@@ -201,6 +208,74 @@ func resourceCPCodeRead(ctx context.Context, d *schema.ResourceData, m interface
 	return nil
 }
 
+func resourceCPCodeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	ctx = log.NewContext(ctx, meta.Log("CPRG", "resourceCPCodeUpdate"))
+	client := inst.CPRGClient(meta)
+
+	if !d.HasChange("name") {
+		return resourceCPCodeRead(ctx, d, m)
+	}
+
+	cpCodeID, err := tools.GetIntID(d.Id(), "cpc_")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("%w: %s", ErrCPCodeUpdate, err))
+	}
+	if err := renameCPCode(ctx, client, cpCodeID, d.Get("name").(string)); err != nil {
+		d.Partial(true)
+		return diag.FromErr(err)
+	}
+
+	return resourceCPCodeRead(ctx, d, m)
+}
+
+// renameCPCode renames the CP code through CPRG, the errors wrap ErrCPCodeUpdate
+func renameCPCode(ctx context.Context, client cprg, cpCodeID int, name string) error {
+	cpCode, err := client.GetCPCode(ctx, cpCodeID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCPCodeUpdate, err)
+	}
+
+	log.FromContext(ctx).Debugf("Renaming CP Code %d from %q to %q", cpCodeID, cpCode.CPCodeName, name)
+	cpCode.CPCodeName = name
+	if _, err := client.UpdateCPCode(ctx, *cpCode); err != nil {
+		return fmt.Errorf("%w: %s", ErrCPCodeUpdate, err)
+	}
+
+	return nil
+}
+
+// warnCPCodeReplaced warns when the plan replaces the CP code of another product, the replaced CP code is not deleted.
+// A diff can not return warnings, the warning is logged and Delete warns again when the CP code leaves the state
+func warnCPCodeReplaced(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("product") {
+		return nil
+	}
+
+	old, _ := d.GetChange("product")
+	akamai.Meta(m).Log("PAPI", "warnCPCodeReplaced").Warnf("%s is replaced: its product %s changes. %s", d.Id(), old, cpCodeNotDeletedDetail)
+	return nil
+}
+
+// resourceCPCodeDelete only removes the CP code from the state and warns about it, the CP code remains usable
+func resourceCPCodeDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("PAPI", "resourceCPCodeDelete")
+	logger.Infof("CP codes cannot be deleted - %s will only be removed from state", d.Id())
+
+	id := d.Id()
+	d.SetId("")
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("CP code %s was not deleted", id),
+		Detail:   cpCodeNotDeletedDetail,
+	}}
+}
+
+const cpCodeNotDeletedDetail = "CP codes cannot be deleted, destroying or replacing an akamai_cp_code only removes the " +
+	"CP code from the Terraform state and it remains available to properties. Creating a CP code with the same name, " +
+	"contract and group reuses it."
+
 func resourceCPCodeImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	meta := akamai.Meta(m)
 	logger := meta.Log("PAPI", "resourceCPCodeImport")
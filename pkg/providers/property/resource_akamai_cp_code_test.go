@@ -2,11 +2,15 @@ package property
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
 	"github.com/tj/assert"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -194,23 +198,34 @@ func TestResCPCode(t *testing.T) {
 		// Values are from fixture:
 		expectGet(client, "ctr_1", "grp_1", &CPCodes)
 		expectCreate(client, "test cpcode", "prd_1", "ctr_1", "grp_1", &CPCodes).Once()
-		expectCreate(client, "renamed cpcode", "prd_1", "ctr_1", "grp_1", &CPCodes).Once()
 
-		// No mock behavior for delete because there is no delete operation for CP Codes
+		// The CP code is renamed in place through CPRG
+		cprgClient := &mockcprg{}
+		defer cprgClient.AssertExpectations(t)
+
+		cprgClient.On("GetCPCode", AnyCTX, 0).Return(&cprgCPCode{CPCodeID: 0, CPCodeName: "test cpcode"}, nil).Once()
+		cprgClient.On("UpdateCPCode", AnyCTX, cprgCPCode{CPCodeID: 0, CPCodeName: "renamed cpcode"}).Run(func(args mock.Arguments) {
+			CPCodes[0].Name = args.Get(1).(cprgCPCode).CPCodeName
+		}).Return(&cprgCPCode{CPCodeID: 0, CPCodeName: "renamed cpcode"}, nil).Once()
 
 		useClient(client, func() {
-			resource.UnitTest(t, resource.TestCase{
-				Providers: testAccProviders,
-				Steps: []resource.TestStep{
-					{
-						Config: loadFixtureString("testdata/TestResCPCode/change_name_step0.tf"),
-						Check:  resource.TestCheckResourceAttr("akamai_cp_code.test", "id", "cpc_0"),
-					},
-					{
-						Config: loadFixtureString("testdata/TestResCPCode/change_name_step1.tf"),
-						Check:  resource.TestCheckResourceAttr("akamai_cp_code.test", "id", "cpc_1"),
+			useCPRGClient(cprgClient, func() {
+				resource.UnitTest(t, resource.TestCase{
+					Providers: testAccProviders,
+					Steps: []resource.TestStep{
+						{
+							Config: loadFixtureString("testdata/TestResCPCode/change_name_step0.tf"),
+							Check:  resource.TestCheckResourceAttr("akamai_cp_code.test", "id", "cpc_0"),
+						},
+						{
+							Config: loadFixtureString("testdata/TestResCPCode/change_name_step1.tf"),
+							Check: resource.ComposeAggregateTestCheckFunc(
+								resource.TestCheckResourceAttr("akamai_cp_code.test", "id", "cpc_0"),
+								resource.TestCheckResourceAttr("akamai_cp_code.test", "name", "renamed cpcode"),
+							),
+						},
 					},
-				},
+				})
 			})
 		})
 	})
@@ -324,3 +339,83 @@ func TestResCPCode(t *testing.T) {
 		client.AssertExpectations(t)
 	})
 }
+
+func TestResCPCodeNotDeleted(t *testing.T) {
+	ctx := context.Background()
+	res := testProvider.ResourcesMap["akamai_cp_code"]
+	meta := testMeta(t)
+
+	config := func(product string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":        "test cpcode",
+			"contract_id": "ctr_1",
+			"group_id":    "grp_1",
+			"product":     product,
+		})
+	}
+	state := &terraform.InstanceState{ID: "cpc_1", Attributes: map[string]string{
+		"id":          "cpc_1",
+		"name":        "test cpcode",
+		"contract_id": "ctr_1",
+		"group_id":    "grp_1",
+		"product":     "prd_1",
+	}}
+
+	// Only a replacement warns, not every validation of the configuration
+	assert.Empty(t, res.Validate(config("prd_1")))
+	diff, err := res.SimpleDiff(ctx, state, config("prd_1"), meta)
+	require.NoError(t, err)
+	assert.True(t, diff == nil || diff.Empty(), "unexpected diff: %v", diff)
+
+	diff, err = res.SimpleDiff(ctx, state, config("prd_2"), meta)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.True(t, diff.RequiresNew())
+
+	// Removing the CP code from the state warns again
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{})
+	d.SetId("cpc_1")
+	diags := res.DeleteContext(ctx, d, meta)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Equal(t, "CP code cpc_1 was not deleted", diags[0].Summary)
+	assert.Equal(t, "", d.Id())
+}
+
+func TestResCPCodeUpdateError(t *testing.T) {
+	res := testProvider.ResourcesMap["akamai_cp_code"]
+	meta := testMeta(t)
+
+	cprgClient := &mockcprg{}
+	defer cprgClient.AssertExpectations(t)
+	cprgClient.On("GetCPCode", AnyCTX, 1).Return(&cprgCPCode{CPCodeID: 1, CPCodeName: "test cpcode"}, nil).Once()
+	cprgClient.On("UpdateCPCode", AnyCTX, cprgCPCode{CPCodeID: 1, CPCodeName: "renamed cpcode"}).
+		Return(nil, &apiError{API: ErrCPRG, Method: "PUT", Path: "/cprg/v1/cpcodes/1", StatusCode: 403, Title: "Forbidden"}).Once()
+
+	useCPRGClient(cprgClient, func() {
+		d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+			"name":        "renamed cpcode",
+			"contract_id": "ctr_1",
+			"group_id":    "grp_1",
+			"product":     "prd_1",
+		})
+		d.SetId("cpc_1")
+		diags := res.UpdateContext(context.Background(), d, meta)
+		require.Len(t, diags, 1)
+
+		// the problem response of CPRG is kept in the error
+		assert.Equal(t, "updating CP code: CP codes and reporting groups API: PUT /cprg/v1/cpcodes/1: unexpected response status 403: Forbidden", diags[0].Summary)
+	})
+}
+
+func TestRenameCPCode(t *testing.T) {
+	cprgClient := &mockcprg{}
+	defer cprgClient.AssertExpectations(t)
+	cprgClient.On("GetCPCode", AnyCTX, 1).Return(&cprgCPCode{CPCodeID: 1, CPCodeName: "test cpcode"}, nil).Once()
+	cprgClient.On("UpdateCPCode", AnyCTX, cprgCPCode{CPCodeID: 1, CPCodeName: "renamed cpcode"}).
+		Return(nil, &apiError{API: ErrCPRG, Method: "PUT", Path: "/cprg/v1/cpcodes/1", StatusCode: 403, Title: "Forbidden"}).Once()
+
+	err := renameCPCode(context.Background(), cprgClient, 1, "renamed cpcode")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrCPCodeUpdate), "want %v, got %v", ErrCPCodeUpdate, err)
+}
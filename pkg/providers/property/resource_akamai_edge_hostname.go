@@ -2,12 +2,19 @@ package property
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/apex/log"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
 	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
@@ -18,14 +25,43 @@ func resourceSecureEdgeHostName() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceSecureEdgeHostNameCreate,
 		ReadContext:   resourceSecureEdgeHostNameRead,
+		UpdateContext: resourceSecureEdgeHostNameUpdate,
 		DeleteContext: resourceSecureEdgeHostNameDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceSecureEdgeHostNameImport,
 		},
-		Schema: akamaiSecureEdgeHostNameSchema,
+		Schema:        akamaiSecureEdgeHostNameSchema,
+		CustomizeDiff: validateEdgeHostnameSecureNetwork,
+		Timeouts: &schema.ResourceTimeout{
+			Create:  &EdgeHostnameTimeout,
+			Update:  &EdgeHostnameTimeout,
+			Delete:  &EdgeHostnameTimeout,
+			Default: &EdgeHostnameTimeout,
+		},
 	}
 }
 
+var (
+	// EdgeHostnamePollInterval is the interval for polling the status of an edge hostname change request
+	EdgeHostnamePollInterval = time.Minute
+
+	// EdgeHostnameTimeout is the default timeout for the edge hostname changes
+	EdgeHostnameTimeout = time.Minute * 60
+
+	// edgeHostnameSecureNetworks maps the domain suffixes PAPI secures to their secure network, the edge hostnames
+	// with other suffixes, like the China CDN or custom map suffixes, are created without a secure network
+	edgeHostnameSecureNetworks = map[string]string{
+		"edgesuite.net": papi.EHSecureNetworkStandardTLS,
+		"edgekey.net":   papi.EHSecureNetworkEnhancedTLS,
+		"akamaized.net": papi.EHSecureNetworkSharedCert,
+	}
+
+	// edgeHostnameSuffixes are the suffixes recognized in edge hostnames without domain_suffix, in matching order
+	edgeHostnameSuffixes = []string{"edgesuite.net", "edgekey.net", "akamaized.net"}
+
+	domainSuffixRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+)
+
 var akamaiSecureEdgeHostNameSchema = map[string]*schema.Schema{
 	"product": {
 		Type:          schema.TypeString,
@@ -77,10 +113,27 @@ var akamaiSecureEdgeHostNameSchema = map[string]*schema.Schema{
 		DiffSuppressFunc: suppressEdgeHostnameDomain,
 		ValidateDiagFunc: tools.IsNotBlank,
 	},
+	"domain_suffix": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringMatch(domainSuffixRegexp, "must be a lower case domain name like edgesuite.net"),
+		Description:  "The domain suffix of the edge hostname, inferred from edge_hostname when not set",
+	},
+	"secure_network": {
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		ForceNew: true,
+		ValidateFunc: validation.StringInSlice([]string{
+			papi.EHSecureNetworkStandardTLS, papi.EHSecureNetworkEnhancedTLS, papi.EHSecureNetworkSharedCert,
+		}, false),
+		Description: "The secure network of the edge hostname, inferred from the domain suffix when not set",
+	},
 	"ip_behavior": {
 		Type:     schema.TypeString,
 		Required: true,
-		ForceNew: true,
 		ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
 			v := val.(string)
 			if !strings.EqualFold(papi.EHIPVersionV4, v) && !strings.EqualFold(papi.EHIPVersionV6Performance, v) && !strings.EqualFold(papi.EHIPVersionV6Compliance, v) {
@@ -94,6 +147,30 @@ var akamaiSecureEdgeHostNameSchema = map[string]*schema.Schema{
 		Optional: true,
 		ForceNew: true,
 	},
+	"ttl": {
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		Description:  "The TTL of the edge hostname DNS record in seconds, left to the Akamai default when not set",
+	},
+	"map": {
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateDiagFunc: tools.IsNotBlank,
+		Description:      "The Akamai map the edge hostname resolves to, left to the Akamai default when not set",
+	},
+	"status_update_email": {
+		Type:        schema.TypeList,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Email addresses notified of the status of the edge hostname change requests",
+	},
+	"delete_on_destroy": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Whether destroying the resource deletes the edge hostname, it is otherwise only removed from the state",
+	},
 }
 
 func resourceSecureEdgeHostNameCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -165,20 +242,8 @@ func resourceSecureEdgeHostNameCreate(ctx context.Context, d *schema.ResourceDat
 	}
 	newHostname := papi.EdgeHostnameCreate{}
 	newHostname.ProductID = productID
-	newHostname.DomainSuffix = "edgesuite.net"
-
-	switch {
-	case strings.HasSuffix(edgeHostname, ".edgesuite.net"):
-		newHostname.DomainSuffix = "edgesuite.net"
-		newHostname.SecureNetwork = papi.EHSecureNetworkStandardTLS
-	case strings.HasSuffix(edgeHostname, ".edgekey.net"):
-		newHostname.DomainSuffix = "edgekey.net"
-		newHostname.SecureNetwork = papi.EHSecureNetworkEnhancedTLS
-	case strings.HasSuffix(edgeHostname, ".akamaized.net"):
-		newHostname.DomainSuffix = "akamaized.net"
-		newHostname.SecureNetwork = papi.EHSecureNetworkSharedCert
-	}
-	newHostname.DomainPrefix = strings.TrimSuffix(edgeHostname, "."+newHostname.DomainSuffix)
+	newHostname.DomainPrefix, newHostname.DomainSuffix = splitEdgeHostname(edgeHostname, d.Get("domain_suffix").(string))
+	newHostname.SecureNetwork = edgeHostnameSecureNetwork(edgeHostname, d.Get("domain_suffix").(string), d.Get("secure_network").(string))
 
 	// ip_behavior is required value in schema.
 	newHostname.IPVersionBehavior = strings.ToUpper(d.Get("ip_behavior").(string))
@@ -186,7 +251,7 @@ func resourceSecureEdgeHostNameCreate(ctx context.Context, d *schema.ResourceDat
 	for _, h := range edgeHostnames.EdgeHostnames.Items {
 		if h.DomainPrefix == newHostname.DomainPrefix && h.DomainSuffix == newHostname.DomainSuffix {
 			d.SetId(h.ID)
-			return nil
+			return resourceSecureEdgeHostNameApplyDefaults(ctx, d, meta)
 		}
 	}
 
@@ -195,7 +260,7 @@ func resourceSecureEdgeHostNameCreate(ctx context.Context, d *schema.ResourceDat
 		if !errors.Is(err, tools.ErrNotFound) {
 			return diag.FromErr(err)
 		}
-		if newHostname.SecureNetwork == "ENHANCED_TLS" {
+		if newHostname.SecureNetwork == papi.EHSecureNetworkEnhancedTLS {
 			return diag.FromErr(fmt.Errorf("A certificate enrollment ID is required for Enhanced TLS (edgekey.net) edge hostnames"))
 		}
 	}
@@ -213,16 +278,90 @@ func resourceSecureEdgeHostNameCreate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 	d.SetId(hostname.EdgeHostnameID)
+	return resourceSecureEdgeHostNameApplyDefaults(ctx, d, meta)
+}
+
+// resourceSecureEdgeHostNameApplyDefaults sets the ttl and map of a new edge hostname, PAPI creates edge hostnames
+// with the Akamai defaults
+func resourceSecureEdgeHostNameApplyDefaults(ctx context.Context, d *schema.ResourceData, meta akamai.OperationMeta) diag.Diagnostics {
+	logger := meta.Log("HAPI", "resourceSecureEdgeHostNameApplyDefaults")
+	ctx = log.NewContext(ctx, logger)
+
+	patch, err := edgeHostnamePatch(d, func(key string) bool { return key != "ip_behavior" })
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(patch) > 0 {
+		if err := updateEdgeHostname(ctx, inst.HAPIClient(meta), d, patch); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceSecureEdgeHostNameRead(ctx, d, meta)
 }
 
-func resourceSecureEdgeHostNameDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceSecureEdgeHostNameUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	meta := akamai.Meta(m)
-	logger := meta.Log("PAPI", "resourceSecureEdgeHostNameDelete")
-	logger.Debug("DELETING")
-	logger.Info("PAPI does not support edge hostname deletion - resource will only be removed from state")
+	logger := meta.Log("HAPI", "resourceSecureEdgeHostNameUpdate")
+	ctx = log.NewContext(ctx, logger)
+
+	patch, err := edgeHostnamePatch(d, d.HasChange)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(patch) > 0 {
+		logger.Debugf("Updating edge hostname %s: %d changes", d.Id(), len(patch))
+		if err := updateEdgeHostname(ctx, inst.HAPIClient(meta), d, patch); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceSecureEdgeHostNameRead(ctx, d, m)
+}
+
+func resourceSecureEdgeHostNameDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("HAPI", "resourceSecureEdgeHostNameDelete")
+	ctx = log.NewContext(ctx, logger)
+
+	if !d.Get("delete_on_destroy").(bool) {
+		logger.Info("delete_on_destroy is not set - edge hostname will only be removed from state")
+		d.SetId("")
+		return nil
+	}
+
+	edgeHostname := d.Get("edge_hostname").(string)
+	properties, err := findEdgeHostnameProperties(ctx, inst.Client(meta), edgeHostname)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("%w: %s", ErrEdgeHostnameDelete, err))
+	}
+	if len(properties) > 0 {
+		return diag.FromErr(fmt.Errorf("%w: %s is used by %s", ErrEdgeHostnameInUse, edgeHostname, strings.Join(properties, ", ")))
+	}
+
+	client := inst.HAPIClient(meta)
+	recordName, dnsZone := splitEdgeHostname(edgeHostname, d.Get("domain_suffix").(string))
+	change, err := client.DeleteEdgeHostname(ctx, hapiDeleteEdgeHostnameRequest{
+		RecordName:        recordName,
+		DNSZone:           dnsZone,
+		StatusUpdateEmail: edgeHostnameStatusUpdateEmail(d),
+	})
+	if err != nil {
+		var e *apiError
+		if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+			logger.Warnf("edge hostname %s is already deleted", edgeHostname)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("%w: %s", ErrEdgeHostnameDelete, err))
+	}
+	if err := waitForEdgeHostnameChange(ctx, client, change); err != nil {
+		return diag.FromErr(fmt.Errorf("%w: %s", ErrEdgeHostnameDelete, err))
+	}
+
 	d.SetId("")
-	logger.Debugf("DONE")
 	return nil
 }
 
@@ -349,7 +488,8 @@ func resourceSecureEdgeHostNameRead(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if edgeHostname != "" {
-		found, err := findEdgeHostname(edgeHostnames.EdgeHostnames, edgeHostname)
+		prefix, suffix := splitEdgeHostname(edgeHostname, d.Get("domain_suffix").(string))
+		found, err := findEdgeHostname(edgeHostnames.EdgeHostnames, prefix+"."+suffix)
 		if err != nil && !errors.Is(err, ErrEdgeHostnameNotFound) {
 			return diag.FromErr(err)
 		}
@@ -361,39 +501,242 @@ func resourceSecureEdgeHostNameRead(ctx context.Context, d *schema.ResourceData,
 	if err := d.Set("edge_hostname", defaultEdgeHostname.Domain); err != nil {
 		return diag.FromErr(fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error()))
 	}
+	attrs := map[string]interface{}{
+		"domain_suffix":  defaultEdgeHostname.DomainSuffix,
+		"secure_network": readSecureNetwork(*defaultEdgeHostname),
+	}
+	for k, v := range attrs {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error()))
+		}
+	}
+	if defaultEdgeHostname.IPVersionBehavior != "" {
+		// keep the case of the configuration, ip_behavior is not case sensitive
+		if !strings.EqualFold(d.Get("ip_behavior").(string), defaultEdgeHostname.IPVersionBehavior) {
+			if err := d.Set("ip_behavior", defaultEdgeHostname.IPVersionBehavior); err != nil {
+				return diag.FromErr(fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error()))
+			}
+		}
+	}
 	d.SetId(defaultEdgeHostname.ID)
 
+	// ttl and map are only read when managed, their Akamai defaults would otherwise show up as changes
+	if d.Get("ttl").(int) != 0 || d.Get("map").(string) != "" {
+		recordName, dnsZone := defaultEdgeHostname.DomainPrefix, defaultEdgeHostname.DomainSuffix
+		ehn, err := inst.HAPIClient(meta).GetEdgeHostname(ctx, recordName, dnsZone)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if d.Get("ttl").(int) != 0 {
+			if err := d.Set("ttl", ehn.TTL); err != nil {
+				return diag.FromErr(fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error()))
+			}
+		}
+		if d.Get("map").(string) != "" {
+			if err := d.Set("map", ehn.Map); err != nil {
+				return diag.FromErr(fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error()))
+			}
+		}
+	}
+
 	return nil
 }
 
-func suppressEdgeHostnameDomain(_, old, new string, _ *schema.ResourceData) bool {
+func suppressEdgeHostnameDomain(_, old, new string, d *schema.ResourceData) bool {
 	if old == new {
 		return true
 	}
-	if !(strings.HasSuffix(new, "edgekey.net") || strings.HasSuffix(new, "edgesuite.net") || strings.HasSuffix(new, "akamaized.net")) {
-		return old == fmt.Sprintf("%s.edgesuite.net", new)
+	var domainSuffix string
+	if d != nil {
+		domainSuffix = d.Get("domain_suffix").(string)
+	}
+	if prefix, suffix := splitEdgeHostname(new, domainSuffix); prefix == new {
+		return old == fmt.Sprintf("%s.%s", new, suffix)
 	}
 	return false
 }
 
 func findEdgeHostname(edgeHostnames papi.EdgeHostnameItems, domain string) (*papi.EdgeHostnameGetItem, error) {
-	var prefix string
-	suffix := "edgesuite.net"
-	if domain != "" {
-		if strings.HasSuffix(domain, "edgekey.net") {
-			suffix = "edgekey.net"
-		}
-		if strings.HasSuffix(domain, "akamaized.net") {
-			suffix = "akamaized.net"
-		}
-		prefix = strings.TrimSuffix(domain, "."+suffix)
-	}
+	prefix, suffix := splitEdgeHostname(domain, "")
 
 	for _, eHn := range edgeHostnames.Items {
-		if eHn.DomainPrefix == prefix && eHn.DomainSuffix == suffix {
+		if (eHn.DomainPrefix == prefix && eHn.DomainSuffix == suffix) || (domain != "" && eHn.Domain == domain) {
 			return &eHn, nil
 		}
 	}
 
 	return nil, fmt.Errorf("%w: %s", ErrEdgeHostnameNotFound, domain)
 }
+
+// edgeHostnamePatch returns the HAPI patch setting the attributes for which changed is true
+func edgeHostnamePatch(d *schema.ResourceData, changed func(key string) bool) ([]tools.JSONPatchOperation, error) {
+	var patch []tools.JSONPatchOperation
+	replace := func(path string, value interface{}) error {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		patch = append(patch, tools.JSONPatchOperation{Op: "replace", Path: path, Value: raw})
+		return nil
+	}
+
+	if changed("ip_behavior") {
+		if err := replace("/ipVersionBehavior", strings.ToUpper(d.Get("ip_behavior").(string))); err != nil {
+			return nil, err
+		}
+	}
+	if ttl := d.Get("ttl").(int); ttl != 0 && changed("ttl") {
+		if err := replace("/ttl", ttl); err != nil {
+			return nil, err
+		}
+	}
+	if m := d.Get("map").(string); m != "" && changed("map") {
+		if err := replace("/map", m); err != nil {
+			return nil, err
+		}
+	}
+
+	return patch, nil
+}
+
+// updateEdgeHostname applies the patch to the edge hostname and waits for the change request to complete
+func updateEdgeHostname(ctx context.Context, client hapi, d *schema.ResourceData, patch []tools.JSONPatchOperation) error {
+	recordName, dnsZone := splitEdgeHostname(d.Get("edge_hostname").(string), d.Get("domain_suffix").(string))
+	change, err := client.UpdateEdgeHostname(ctx, hapiUpdateEdgeHostnameRequest{
+		RecordName:        recordName,
+		DNSZone:           dnsZone,
+		Patch:             patch,
+		StatusUpdateEmail: edgeHostnameStatusUpdateEmail(d),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrEdgeHostnameUpdate, err)
+	}
+	if err := waitForEdgeHostnameChange(ctx, client, change); err != nil {
+		return fmt.Errorf("%w: %s", ErrEdgeHostnameUpdate, err)
+	}
+
+	return nil
+}
+
+// waitForEdgeHostnameChange polls the change request until it succeeds, fails or the context is done
+func waitForEdgeHostnameChange(ctx context.Context, client hapi, change *hapiChangeRequest) error {
+	logger := log.FromContext(ctx)
+
+	for change.Status != hapiChangeStatusSucceeded {
+		if change.Status == hapiChangeStatusFailed {
+			return fmt.Errorf("change request %d failed: %s", change.ChangeID, change.StatusMessage)
+		}
+		logger.Debugf("change request %d is %s", change.ChangeID, change.Status)
+
+		select {
+		case <-time.After(EdgeHostnamePollInterval):
+			next, err := client.GetChangeRequest(ctx, change.ChangeID)
+			if err != nil {
+				return err
+			}
+			change = next
+		case <-ctx.Done():
+			return fmt.Errorf("change request %d: %w", change.ChangeID, ctx.Err())
+		}
+	}
+
+	return nil
+}
+
+// findEdgeHostnameProperties returns the names of the properties whose latest or active versions use the edge hostname
+func findEdgeHostnameProperties(ctx context.Context, client papi.PAPI, edgeHostname string) ([]string, error) {
+	res, err := client.SearchProperties(ctx, papi.SearchRequest{Key: papi.SearchKeyEdgeHostname, Value: edgeHostname})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, item := range res.Versions.Items {
+		if !seen[item.PropertyName] {
+			seen[item.PropertyName] = true
+			names = append(names, item.PropertyName)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func edgeHostnameStatusUpdateEmail(d *schema.ResourceData) []string {
+	var emails []string
+	for _, email := range d.Get("status_update_email").([]interface{}) {
+		emails = append(emails, email.(string))
+	}
+	return emails
+}
+
+// splitEdgeHostname splits an edge hostname like www.example.com.edgesuite.net into its domain prefix and suffix,
+// the suffix is the domain_suffix when set or the recognized suffix of the edge hostname, it defaults to edgesuite.net
+func splitEdgeHostname(edgeHostname, domainSuffix string) (string, string) {
+	if domainSuffix != "" {
+		return strings.TrimSuffix(edgeHostname, "."+domainSuffix), domainSuffix
+	}
+	for _, suffix := range edgeHostnameSuffixes {
+		if strings.HasSuffix(edgeHostname, "."+suffix) {
+			return strings.TrimSuffix(edgeHostname, "."+suffix), suffix
+		}
+	}
+
+	return edgeHostname, "edgesuite.net"
+}
+
+// edgeHostnameSecureNetwork returns the secure network of a new edge hostname, it is inferred from the domain suffix
+// when not set. Edge hostnames without a domain suffix are not secured
+func edgeHostnameSecureNetwork(edgeHostname, domainSuffix, secureNetwork string) string {
+	if secureNetwork != "" {
+		return secureNetwork
+	}
+	prefix, suffix := splitEdgeHostname(edgeHostname, domainSuffix)
+	if prefix == edgeHostname && domainSuffix == "" {
+		return ""
+	}
+
+	return edgeHostnameSecureNetworks[suffix]
+}
+
+// readSecureNetwork returns the secure network of an existing edge hostname, PAPI only tells whether it is secure
+func readSecureNetwork(item papi.EdgeHostnameGetItem) string {
+	if !item.Secure {
+		return ""
+	}
+	return edgeHostnameSecureNetworks[item.DomainSuffix]
+}
+
+// validateEdgeHostnameSecureNetwork rejects the domain suffix and secure network combinations PAPI does not support
+func validateEdgeHostnameSecureNetwork(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	// domain_suffix and secure_network are also unknown while they are computed
+	if !d.NewValueKnown("edge_hostname") {
+		return nil
+	}
+
+	return checkEdgeHostnameSecureNetwork(d.Get("edge_hostname").(string), d.Get("domain_suffix").(string), d.Get("secure_network").(string))
+}
+
+func checkEdgeHostnameSecureNetwork(edgeHostname, domainSuffix, secureNetwork string) error {
+	if domainSuffix != "" && !strings.HasSuffix(edgeHostname, "."+domainSuffix) {
+		if prefix, suffix := splitEdgeHostname(edgeHostname, ""); prefix != edgeHostname {
+			return fmt.Errorf("%w: edge hostname %s ends with %s, not with the domain suffix %s", ErrEdgeHostnameSecureNetwork, edgeHostname, suffix, domainSuffix)
+		}
+	}
+	if secureNetwork == "" {
+		return nil
+	}
+
+	_, suffix := splitEdgeHostname(edgeHostname, domainSuffix)
+	if edgeHostnameSecureNetworks[suffix] == secureNetwork {
+		return nil
+	}
+	for s, network := range edgeHostnameSecureNetworks {
+		if network == secureNetwork {
+			return fmt.Errorf("%w: secure network %s requires the domain suffix %s, got %s", ErrEdgeHostnameSecureNetwork, secureNetwork, s, suffix)
+		}
+	}
+
+	return fmt.Errorf("%w: secure network %s", ErrEdgeHostnameSecureNetwork, secureNetwork)
+}
@@ -1,18 +1,23 @@
 package property
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
 )
 
 func TestResourceEdgeHostname(t *testing.T) {
@@ -582,6 +587,188 @@ func TestResourceEdgeHostnames_WithImport(t *testing.T) {
 	})
 }
 
+func TestResourceEdgeHostnameUpdateAndDelete(t *testing.T) {
+	interval := EdgeHostnamePollInterval
+	EdgeHostnamePollInterval = time.Millisecond
+	defer func() { EdgeHostnamePollInterval = interval }()
+
+	client := &mockpapi{}
+	hapiClient := &mockhapi{}
+
+	ipBehavior, ttl := "IPV4", 0
+	call := client.On("GetEdgeHostnames", mock.Anything, papi.GetEdgeHostnamesRequest{ContractID: "ctr_2", GroupID: "grp_2"})
+	call.Run(func(mock.Arguments) {
+		call.Return(&papi.GetEdgeHostnamesResponse{
+			ContractID: "ctr_2",
+			GroupID:    "grp_2",
+			EdgeHostnames: papi.EdgeHostnameItems{Items: []papi.EdgeHostnameGetItem{
+				{
+					ID:                "eh_1",
+					Domain:            "test.edgesuite.net",
+					ProductID:         "prd_2",
+					DomainPrefix:      "test",
+					DomainSuffix:      "edgesuite.net",
+					IPVersionBehavior: ipBehavior,
+				},
+			}},
+		}, nil)
+	})
+
+	hapiClient.On("UpdateEdgeHostname", mock.Anything, hapiUpdateEdgeHostnameRequest{
+		RecordName: "test",
+		DNSZone:    "edgesuite.net",
+		Patch: []tools.JSONPatchOperation{
+			{Op: "replace", Path: "/ipVersionBehavior", Value: json.RawMessage(`"IPV6_COMPLIANCE"`)},
+			{Op: "replace", Path: "/ttl", Value: json.RawMessage(`300`)},
+		},
+		StatusUpdateEmail: []string{"user@example.com"},
+	}).Return(&hapiChangeRequest{ChangeID: 1, Action: "EDIT", Status: "PENDING"}, nil).Run(func(mock.Arguments) {
+		ipBehavior, ttl = "IPV6_COMPLIANCE", 300
+	}).Once()
+	hapiClient.On("GetChangeRequest", mock.Anything, 1).Return(&hapiChangeRequest{ChangeID: 1, Action: "EDIT", Status: "SUCCEEDED"}, nil).Once()
+	hapiCall := hapiClient.On("GetEdgeHostname", mock.Anything, "test", "edgesuite.net")
+	hapiCall.Run(func(mock.Arguments) {
+		hapiCall.Return(&hapiEdgeHostname{RecordName: "test", DNSZone: "edgesuite.net", TTL: ttl, Map: "a;dscb.akamai.net", IPVersionBehavior: ipBehavior}, nil)
+	})
+
+	// the first deletion is refused as a property still uses the edge hostname
+	search := papi.SearchRequest{Key: papi.SearchKeyEdgeHostname, Value: "test.edgesuite.net"}
+	client.On("SearchProperties", mock.Anything, search).Return(&papi.SearchResponse{Versions: papi.SearchItems{Items: []papi.SearchItem{
+		{PropertyID: "prp_1", PropertyName: "www.example.com", PropertyVersion: 2, EdgeHostname: "test.edgesuite.net"},
+	}}}, nil).Once()
+	client.On("SearchProperties", mock.Anything, search).Return(&papi.SearchResponse{}, nil).Once()
+	hapiClient.On("DeleteEdgeHostname", mock.Anything, hapiDeleteEdgeHostnameRequest{
+		RecordName:        "test",
+		DNSZone:           "edgesuite.net",
+		StatusUpdateEmail: []string{"user@example.com"},
+	}).Return(&hapiChangeRequest{ChangeID: 2, Action: "DELETE", Status: "SUCCEEDED"}, nil).Once()
+
+	useClient(client, func() {
+		useHAPIClient(hapiClient, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{
+					{
+						Config: loadFixtureString("testdata/TestResourceEdgeHostname/update_ipv4.tf"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("akamai_edge_hostname.edgehostname", "id", "eh_1"),
+							resource.TestCheckResourceAttr("akamai_edge_hostname.edgehostname", "ip_behavior", "IPV4"),
+						),
+					},
+					{
+						Config: loadFixtureString("testdata/TestResourceEdgeHostname/update_ipv6_ttl.tf"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("akamai_edge_hostname.edgehostname", "id", "eh_1"),
+							resource.TestCheckResourceAttr("akamai_edge_hostname.edgehostname", "ip_behavior", "IPV6_COMPLIANCE"),
+							resource.TestCheckResourceAttr("akamai_edge_hostname.edgehostname", "ttl", "300"),
+						),
+					},
+					{
+						Config:      loadFixtureString("testdata/TestResourceEdgeHostname/update_ipv6_ttl.tf"),
+						Destroy:     true,
+						ExpectError: regexp.MustCompile("edge hostname is used by properties: test.edgesuite.net is used by www.example.com"),
+					},
+				},
+			})
+		})
+	})
+	client.AssertExpectations(t)
+	hapiClient.AssertExpectations(t)
+}
+
+func TestEdgeHostnamePatch(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, akamaiSecureEdgeHostNameSchema, map[string]interface{}{
+		"edge_hostname": "test.edgesuite.net",
+		"ip_behavior":   "ipv6_performance",
+		"ttl":           600,
+		"map":           "a;example.akamai.net",
+	})
+
+	patch, err := edgeHostnamePatch(d, func(string) bool { return true })
+	require.NoError(t, err)
+	assert.Equal(t, []tools.JSONPatchOperation{
+		{Op: "replace", Path: "/ipVersionBehavior", Value: json.RawMessage(`"IPV6_PERFORMANCE"`)},
+		{Op: "replace", Path: "/ttl", Value: json.RawMessage(`600`)},
+		{Op: "replace", Path: "/map", Value: json.RawMessage(`"a;example.akamai.net"`)},
+	}, patch)
+
+	patch, err = edgeHostnamePatch(d, func(key string) bool { return key == "map" })
+	require.NoError(t, err)
+	assert.Equal(t, []tools.JSONPatchOperation{
+		{Op: "replace", Path: "/map", Value: json.RawMessage(`"a;example.akamai.net"`)},
+	}, patch)
+
+	// unset ttl and map keep the Akamai defaults
+	d = schema.TestResourceDataRaw(t, akamaiSecureEdgeHostNameSchema, map[string]interface{}{
+		"edge_hostname": "test.edgesuite.net",
+		"ip_behavior":   "IPV4",
+	})
+	patch, err = edgeHostnamePatch(d, func(key string) bool { return key != "ip_behavior" })
+	require.NoError(t, err)
+	assert.Empty(t, patch)
+}
+
+func TestWaitForEdgeHostnameChange(t *testing.T) {
+	interval := EdgeHostnamePollInterval
+	EdgeHostnamePollInterval = time.Millisecond
+	defer func() { EdgeHostnamePollInterval = interval }()
+
+	t.Run("change succeeds", func(t *testing.T) {
+		client := &mockhapi{}
+		client.On("GetChangeRequest", mock.Anything, 1).Return(&hapiChangeRequest{ChangeID: 1, Status: "PENDING"}, nil).Once()
+		client.On("GetChangeRequest", mock.Anything, 1).Return(&hapiChangeRequest{ChangeID: 1, Status: "SUCCEEDED"}, nil).Once()
+
+		err := waitForEdgeHostnameChange(context.Background(), client, &hapiChangeRequest{ChangeID: 1, Status: "PENDING"})
+		require.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("change fails", func(t *testing.T) {
+		client := &mockhapi{}
+		client.On("GetChangeRequest", mock.Anything, 1).Return(&hapiChangeRequest{ChangeID: 1, Status: "FAILED", StatusMessage: "invalid map"}, nil).Once()
+
+		err := waitForEdgeHostnameChange(context.Background(), client, &hapiChangeRequest{ChangeID: 1, Status: "PENDING"})
+		require.Error(t, err)
+		assert.Equal(t, "change request 1 failed: invalid map", err.Error())
+		client.AssertExpectations(t)
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		client := &mockhapi{}
+		client.On("GetChangeRequest", mock.Anything, 1).Return(nil, &apiError{API: ErrHAPI, Method: "GET", Path: "/hapi/v1/change-requests/1", StatusCode: 500}).Once()
+
+		err := waitForEdgeHostnameChange(context.Background(), client, &hapiChangeRequest{ChangeID: 1, Status: "PENDING"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrHAPI))
+		client.AssertExpectations(t)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		EdgeHostnamePollInterval = time.Hour
+
+		err := waitForEdgeHostnameChange(ctx, &mockhapi{}, &hapiChangeRequest{ChangeID: 1, Status: "PENDING"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
+
+func TestFindEdgeHostnameProperties(t *testing.T) {
+	client := &mockpapi{}
+	client.On("SearchProperties", mock.Anything, papi.SearchRequest{Key: papi.SearchKeyEdgeHostname, Value: "test.edgesuite.net"}).
+		Return(&papi.SearchResponse{Versions: papi.SearchItems{Items: []papi.SearchItem{
+			{PropertyName: "www.example.com", PropertyVersion: 3},
+			{PropertyName: "static.example.com", PropertyVersion: 1},
+			{PropertyName: "www.example.com", PropertyVersion: 2, ProductionStatus: "ACTIVE"},
+		}}}, nil)
+
+	names, err := findEdgeHostnameProperties(context.Background(), client, "test.edgesuite.net")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"static.example.com", "www.example.com"}, names)
+	client.AssertExpectations(t)
+}
+
 func TestFindEdgeHostname(t *testing.T) {
 	tests := map[string]struct {
 		hostnames papi.EdgeHostnameItems
@@ -714,6 +901,71 @@ func TestFindEdgeHostname(t *testing.T) {
 	}
 }
 
+func TestSplitEdgeHostname(t *testing.T) {
+	tests := map[string]struct {
+		edgeHostname string
+		domainSuffix string
+		prefix       string
+		suffix       string
+	}{
+		"edgesuite.net":        {edgeHostname: "www.example.com.edgesuite.net", prefix: "www.example.com", suffix: "edgesuite.net"},
+		"edgekey.net":          {edgeHostname: "www.example.com.edgekey.net", prefix: "www.example.com", suffix: "edgekey.net"},
+		"akamaized.net":        {edgeHostname: "www.example.com.akamaized.net", prefix: "www.example.com", suffix: "akamaized.net"},
+		"no suffix":            {edgeHostname: "www.example.com", prefix: "www.example.com", suffix: "edgesuite.net"},
+		"domain suffix":        {edgeHostname: "www.example.com.example.cn", domainSuffix: "example.cn", prefix: "www.example.com", suffix: "example.cn"},
+		"domain suffix only":   {edgeHostname: "www.example.com", domainSuffix: "example.cn", prefix: "www.example.com", suffix: "example.cn"},
+		"known domain suffix":  {edgeHostname: "www.edgekey.net", domainSuffix: "edgekey.net", prefix: "www", suffix: "edgekey.net"},
+		"custom map subdomain": {edgeHostname: "www.map.edgesuite.net", domainSuffix: "map.edgesuite.net", prefix: "www", suffix: "map.edgesuite.net"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prefix, suffix := splitEdgeHostname(test.edgeHostname, test.domainSuffix)
+			assert.Equal(t, test.prefix, prefix)
+			assert.Equal(t, test.suffix, suffix)
+		})
+	}
+}
+
+func TestEdgeHostnameSecureNetwork(t *testing.T) {
+	tests := map[string]struct {
+		edgeHostname, domainSuffix, secureNetwork string
+		expected                                  string
+		withError                                 string
+	}{
+		"inferred standard TLS":      {edgeHostname: "www.example.com.edgesuite.net", expected: "STANDARD_TLS"},
+		"inferred enhanced TLS":      {edgeHostname: "www.example.com", domainSuffix: "edgekey.net", expected: "ENHANCED_TLS"},
+		"inferred shared cert":       {edgeHostname: "www.example.com.akamaized.net", expected: "SHARED_CERT"},
+		"not secured without suffix": {edgeHostname: "www.example.com"},
+		"not secured custom suffix":  {edgeHostname: "www.example.com.example.cn", domainSuffix: "example.cn"},
+		"explicit secure network":    {edgeHostname: "www.example.com.edgekey.net", secureNetwork: "ENHANCED_TLS", expected: "ENHANCED_TLS"},
+		"suffix mismatch": {
+			edgeHostname: "www.example.com.edgekey.net", domainSuffix: "edgesuite.net",
+			withError: "unsupported edge hostname domain suffix and secure network: edge hostname www.example.com.edgekey.net ends with edgekey.net, not with the domain suffix edgesuite.net",
+		},
+		"secure network mismatch": {
+			edgeHostname: "www.example.com.edgesuite.net", secureNetwork: "ENHANCED_TLS",
+			withError: "unsupported edge hostname domain suffix and secure network: secure network ENHANCED_TLS requires the domain suffix edgekey.net, got edgesuite.net",
+		},
+		"custom suffix cannot be secured": {
+			edgeHostname: "www.example.com.example.cn", domainSuffix: "example.cn", secureNetwork: "SHARED_CERT",
+			withError: "unsupported edge hostname domain suffix and secure network: secure network SHARED_CERT requires the domain suffix akamaized.net, got example.cn",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkEdgeHostnameSecureNetwork(test.edgeHostname, test.domainSuffix, test.secureNetwork)
+			if test.withError != "" {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, ErrEdgeHostnameSecureNetwork))
+				assert.Equal(t, test.withError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, edgeHostnameSecureNetwork(test.edgeHostname, test.domainSuffix, test.secureNetwork))
+		})
+	}
+}
+
 func TestSuppressEdgeHostnameDomain(t *testing.T) {
 	tests := map[string]struct {
 		old, new string
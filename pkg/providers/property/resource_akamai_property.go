@@ -7,12 +7,16 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/apex/log"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
@@ -84,6 +88,7 @@ func resourceProperty() *schema.Resource {
 		ReadContext:   resourcePropertyRead,
 		UpdateContext: resourcePropertyUpdate,
 		DeleteContext: resourcePropertyDelete,
+		CustomizeDiff: customdiff.All(validateRulesSchema, setRuleChanges),
 		Importer: &schema.ResourceImporter{
 			StateContext: resourcePropertyImport,
 		},
@@ -182,6 +187,11 @@ func resourceProperty() *schema.Resource {
 					return compactJSON([]byte(v.(string)))
 				},
 			},
+			"rule_schema_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A local rule format JSON schema the rules are validated against during plan, instead of the one fetched for the product and rule format",
+			},
 			"hostnames": {
 				Type:             schema.TypeMap,
 				Optional:         true,
@@ -189,6 +199,22 @@ func resourceProperty() *schema.Resource {
 				Description:      "Mapping of edge hostname CNAMEs to other CNAMEs",
 				DiffSuppressFunc: diffSuppressHostNames,
 			},
+			"version_notes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The notes of the property versions created or updated by the provider",
+			},
+			"create_from_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The version the next property version is based on when it is set or changed, defaults to the latest version",
+			},
+			"read_version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version whose hostnames and rules are in the state when a specific version was imported, until the next update",
+			},
 
 			// Computed
 			"latest_version": {
@@ -216,6 +242,12 @@ func resourceProperty() *schema.Resource {
 				Computed: true,
 				Elem:     papiError(),
 			},
+			"rule_changes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The changes of the rule tree in the plan, kept after the update applied them",
+			},
 
 			// Hard-deprecated attributes: These are effectively removed, but we wanted to refer users to the upgrade guide
 			"cp_code": {
@@ -376,6 +408,18 @@ func resourcePropertyCreate(ctx context.Context, d *schema.ResourceData, m inter
 		}
 	}
 
+	if Note := d.Get("version_notes").(string); Note != "" {
+		if err := updatePropertyVersionNote(ctx, inst.VersionNotesClient(meta), Property, Note); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+	}
+
+	// The state follows the latest version again once the provider wrote a version
+	if err := d.Set("read_version", 0); err != nil {
+		return diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())
+	}
+
 	return resourcePropertyRead(ctx, d, m)
 }
 
@@ -404,14 +448,20 @@ func resourcePropertyRead(ctx context.Context, d *schema.ResourceData, m interfa
 		ProductionVersion = *Property.ProductionVersion
 	}
 
+	// An import of a specific version keeps the hostnames and rules of that version until the next update
+	ReadProperty := *Property
+	if v, ok := d.GetOk("read_version"); ok {
+		ReadProperty.LatestVersion = v.(int)
+	}
+
 	// TODO: Load hostnames asynchronously
-	Hostnames, err := fetchPropertyHostnames(ctx, client, *Property)
+	Hostnames, err := fetchPropertyHostnames(ctx, client, ReadProperty)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// TODO: Load rules asynchronously
-	Rules, RuleFormat, RuleErrors, RuleWarnings, err := fetchPropertyRules(ctx, client, *Property)
+	Rules, RuleFormat, RuleErrors, RuleWarnings, err := fetchPropertyRules(ctx, client, ReadProperty)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -484,8 +534,8 @@ func resourcePropertyUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	// We only update if these attributes change.
-	if !d.HasChanges("hostnames", "rules", "rule_format") {
-		logger.Debug("No changes to hostnames, rules, or rule_format (no update required)")
+	if !d.HasChanges("hostnames", "rules", "rule_format", "version_notes", "create_from_version") {
+		logger.Debug("No changes to hostnames, rules, rule_format, version_notes or create_from_version (no update required)")
 		return nil
 	}
 
@@ -524,9 +574,30 @@ func resourcePropertyUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.FromErr(err)
 	}
 	// check latest version is editable
-	if resp.Version.ProductionStatus != papi.VersionStatusInactive || resp.Version.StagingStatus != papi.VersionStatusInactive {
-		// The latest version has been activated on either production or staging, so we need to create a new version to apply changes on
-		VersionID, err := createPropertyVersion(ctx, client, Property)
+	Editable := resp.Version.ProductionStatus == papi.VersionStatusInactive && resp.Version.StagingStatus == papi.VersionStatusInactive
+
+	// A new version is also needed when it must be based on another version than the latest one
+	// A changed create_from_version is applied once, later updates are based on the latest version again
+	var FromVersion int
+	if v, ok := d.GetOk("create_from_version"); ok && d.HasChange("create_from_version") && v.(int) != Property.LatestVersion {
+		FromVersion = v.(int)
+	} else if v, ok := d.GetOk("read_version"); ok && v.(int) != Property.LatestVersion {
+		// The state holds the hostnames and rules of an imported version, the new version is based on it
+		FromVersion = v.(int)
+	} else if !Editable {
+		FromVersion = Property.LatestVersion
+	}
+	// A version based on another version than the latest one gets the configured hostnames and rules even when they did not change
+	Rebased := FromVersion != 0 && FromVersion != Property.LatestVersion
+
+	if akamai.Meta(m).DryRun() {
+		d.Partial(true)
+		return propertyDryRunUpdate(ctx, client, d, Property, FromVersion)
+	}
+	if FromVersion != 0 {
+		// The latest version has been activated on either production or staging, or another base version was given,
+		// so we need to create a new version to apply changes on
+		VersionID, err := createPropertyVersion(ctx, client, Property, FromVersion)
 		if err != nil {
 			d.Partial(true)
 			return diag.FromErr(err)
@@ -535,7 +606,7 @@ func resourcePropertyUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	// Hostnames
-	if d.HasChange("hostnames") {
+	if d.HasChange("hostnames") || Rebased {
 		Hostnames := mapToHostnames(d.Get("hostnames").(map[string]interface{}))
 
 		if err := updatePropertyHostnames(ctx, client, Property, Hostnames); err != nil {
@@ -546,7 +617,7 @@ func resourcePropertyUpdate(ctx context.Context, d *schema.ResourceData, m inter
 
 	RuleFormat := d.Get("rule_format").(string)
 	RulesJSON := []byte(d.Get("rules").(string))
-	RulesNeedUpdate := len(RulesJSON) > 0 && d.HasChange("rules")
+	RulesNeedUpdate := len(RulesJSON) > 0 && (d.HasChange("rules") || Rebased)
 	FormatNeedsUpdate := len(RuleFormat) > 0 && d.HasChange("rule_format")
 
 	if FormatNeedsUpdate || RulesNeedUpdate {
@@ -564,11 +635,188 @@ func resourcePropertyUpdate(ctx context.Context, d *schema.ResourceData, m inter
 			d.Partial(true)
 			return diag.FromErr(err)
 		}
+
+		if d.HasChange("rules") {
+			OldRules, NewRules := d.GetChange("rules")
+			RuleChanges, err := ruleChanges(OldRules.(string), NewRules.(string))
+			if err != nil {
+				logger.Warnf("could not compare the rule trees: %s", err)
+			}
+			if err := d.Set("rule_changes", RuleChanges); err != nil {
+				return diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())
+			}
+		}
+	}
+
+	// Every version written by the provider gets the notes
+	if Note := d.Get("version_notes").(string); Note != "" || d.HasChange("version_notes") {
+		if err := updatePropertyVersionNote(ctx, inst.VersionNotesClient(akamai.Meta(m)), Property, Note); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+	}
+
+	// The state follows the latest version again once the provider wrote a version
+	if err := d.Set("read_version", 0); err != nil {
+		return diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())
 	}
 
 	return resourcePropertyRead(ctx, d, m)
 }
 
+// validateRulesSchema validates the rules against the JSON schema of the rule format during plan
+// Validation is skipped when the rules, the product or the rule format are not known yet
+func validateRulesSchema(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta := akamai.Meta(m)
+	logger := meta.Log("PAPI", "validateRulesSchema")
+
+	if !d.HasChange("rules") && !d.HasChange("rule_format") && !d.HasChange("rule_schema_file") {
+		return nil
+	}
+	for _, attr := range []string{"rules", "rule_format", "rule_schema_file"} {
+		if !d.NewValueKnown(attr) {
+			logger.Debugf("%s is not known yet, rules are not validated", attr)
+			return nil
+		}
+	}
+
+	RulesJSON := d.Get("rules").(string)
+	RuleFormat := d.Get("rule_format").(string)
+	SchemaFile := d.Get("rule_schema_file").(string)
+
+	// Only one of product_id and product is set, the other one is computed and not known on create
+	var ProductID string
+	for _, attr := range []string{"product_id", "product"} {
+		if d.NewValueKnown(attr) && ProductID == "" {
+			ProductID = d.Get(attr).(string)
+		}
+	}
+	if RulesJSON == "" || (SchemaFile == "" && (RuleFormat == "" || ProductID == "")) {
+		return nil
+	}
+
+	RuleSchema, err := loadRuleSchema(ctx, meta, SchemaFile, tools.AddPrefix(ProductID, "prd_"), RuleFormat)
+	if err != nil {
+		if errors.Is(err, errRuleSchemaNotFound) {
+			logger.Warnf("no rule format schema for product %s and rule format %s, rules are not validated", ProductID, RuleFormat)
+			return nil
+		}
+		return err
+	}
+
+	RuleErrors, err := RuleSchema.Validate([]byte(RulesJSON))
+	if err != nil {
+		return err
+	}
+	if len(RuleErrors) == 0 {
+		return nil
+	}
+
+	SchemaName := RuleFormat
+	if SchemaFile != "" {
+		SchemaName = SchemaFile
+	}
+	lines := make([]string, 0, len(RuleErrors))
+	for _, e := range RuleErrors {
+		lines = append(lines, "  "+e.String())
+	}
+	return fmt.Errorf("%w %s:\n%s", ErrRulesNotValid, SchemaName, strings.Join(lines, "\n"))
+}
+
+// setRuleChanges lists the changes of the rule tree in the plan, the update stores the same list
+func setRuleChanges(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	logger := akamai.Meta(m).Log("PAPI", "setRuleChanges")
+
+	if d.Id() == "" || !d.HasChange("rules") {
+		return nil
+	}
+	if !d.NewValueKnown("rules") {
+		return d.SetNewComputed("rule_changes")
+	}
+
+	OldRules, NewRules := d.GetChange("rules")
+	RuleChanges, err := ruleChanges(OldRules.(string), NewRules.(string))
+	if err != nil {
+		logger.Warnf("could not compare the rule trees: %s", err)
+		return d.SetNewComputed("rule_changes")
+	}
+
+	return d.SetNew("rule_changes", RuleChanges)
+}
+
+// propertyDryRunUpdate validates the new rules without saving them and reports the changes the update would make
+// A FromVersion other than zero is the version a new version would be based on
+func propertyDryRunUpdate(ctx context.Context, client papi.PAPI, d *schema.ResourceData, Property papi.Property, FromVersion int) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var changes []string
+
+	Version := Property.LatestVersion
+	if FromVersion != 0 {
+		Version++
+		changes = append(changes, fmt.Sprintf("create version %d from version %d", Version, FromVersion))
+	}
+
+	if d.HasChange("hostnames") {
+		var Hostnames []string
+		for _, h := range mapToHostnames(d.Get("hostnames").(map[string]interface{})) {
+			Hostnames = append(Hostnames, fmt.Sprintf("%s -> %s", h.CnameFrom, h.CnameTo))
+		}
+		changes = append(changes, fmt.Sprintf("set the hostnames of version %d to [%s]", Version, strings.Join(Hostnames, ", ")))
+	}
+
+	if Note := d.Get("version_notes").(string); Note != "" || d.HasChange("version_notes") {
+		changes = append(changes, fmt.Sprintf("set the notes of version %d to %q", Version, Note))
+	}
+
+	RuleFormat := d.Get("rule_format").(string)
+	RulesJSON := []byte(d.Get("rules").(string))
+	if len(RulesJSON) > 0 && d.HasChanges("rules", "rule_format") {
+		var Rules papi.RulesUpdate
+		if err := json.Unmarshal(RulesJSON, &Rules); err != nil {
+			return diag.Errorf("rules are not valid JSON: %s", err)
+		}
+
+		if RuleFormat != "" {
+			h := http.Header{"Content-Type": []string{fmt.Sprintf("application/vnd.akamai.papirules.%s+json", RuleFormat)}}
+			ctx = session.ContextWithOptions(ctx, session.WithContextHeaders(h))
+		}
+
+		// the rules are validated against the latest version, the dry run does not save them
+		res, err := client.UpdateRuleTree(ctx, papi.UpdateRulesRequest{
+			PropertyID:      Property.PropertyID,
+			PropertyVersion: Property.LatestVersion,
+			ContractID:      Property.ContractID,
+			GroupID:         Property.GroupID,
+			DryRun:          true,
+			ValidateRules:   true,
+			ValidateMode:    papi.RuleValidateModeFull,
+			Rules:           Rules,
+		})
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+		for _, e := range res.Errors {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("invalid rules: %s", e.Title),
+				Detail:   fmt.Sprintf("%s (%s)", e.Detail, e.Instance),
+			})
+		}
+		Change := fmt.Sprintf("replace the rules of version %d", Version)
+		OldRules, _ := d.GetChange("rules")
+		if RuleChanges, err := ruleChanges(OldRules.(string), string(RulesJSON)); err == nil && len(RuleChanges) > 0 {
+			Change += ":\n      " + strings.Join(RuleChanges, "\n      ")
+		}
+		changes = append(changes, Change)
+	}
+
+	return append(diags, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("dry run, property %s was not updated", Property.PropertyID),
+		Detail:   fmt.Sprintf("The update would:\n  - %s", strings.Join(changes, "\n  - ")),
+	})
+}
+
 func resourcePropertyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	ctx = log.NewContext(ctx, akamai.Meta(m).Log("PAPI", "resourcePropertyDelete"))
 	client := inst.Client(akamai.Meta(m))
@@ -586,29 +834,37 @@ func resourcePropertyDelete(ctx context.Context, d *schema.ResourceData, m inter
 
 func resourcePropertyImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	ctx = log.NewContext(ctx, akamai.Meta(m).Log("PAPI", "resourcePropertyImport"))
+	client := inst.Client(akamai.Meta(m))
 
-	// User-supplied import ID is a comma-separated list of PropertyID[,GroupID[,ContractID]]
+	// User-supplied import ID is a comma-separated list of Property[,ContractID,GroupID] where Property is a property
+	// ID, name:<property name> or a hostname of the property, optionally followed by :v<version> to import the
+	// hostnames and rules of that version instead of the latest one.
 	// ContractID and GroupID are optional as long as the PropertyID is sufficient to fetch the property
 	var PropertyID, GroupID, ContractID string
 	parts := strings.Split(d.Id(), ",")
 	if len(parts) == 2 {
 		return nil, fmt.Errorf("Either PropertyId or comma-separated list of PropertyId, contractID and groupID in that order has to be supplied in import: %s", d.Id())
 	}
-	switch len(parts) {
-	case 1:
-		PropertyID = tools.AddPrefix(parts[0], "prp_")
-	case 3:
-		PropertyID = tools.AddPrefix(parts[0], "prp_")
+	if len(parts) != 1 && len(parts) != 3 {
+		return nil, fmt.Errorf("invalid property identifier: %q", d.Id())
+	}
+
+	Property, Version, err := parsePropertyImportVersion(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	PropertyID, ContractID, GroupID, err = resolvePropertyImportID(ctx, client, Property)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 3 {
 		ContractID = tools.AddPrefix(parts[1], "ctr_")
 		GroupID = tools.AddPrefix(parts[2], "grp_")
-
-	default:
-		return nil, fmt.Errorf("invalid property identifier: %q", d.Id())
 	}
 
 	// Import only needs to set the resource ID and enough attributes that the read opertaion will function, so there's
 	// no need to fetch anything if the user gave both GroupID and ContractID
-	if GroupID != "" && ContractID != "" {
+	if GroupID != "" && ContractID != "" && Version == 0 {
 		attrs := map[string]interface{}{
 			"group_id":    GroupID,
 			"contract_id": ContractID,
@@ -623,23 +879,103 @@ func resourcePropertyImport(ctx context.Context, d *schema.ResourceData, m inter
 
 	// Missing GroupID, ContractID, or both -- Attempt to fetch them. If the PropertyID is not sufficient, PAPI
 	// will return an error.
-	Property, err := fetchProperty(ctx, inst.Client(akamai.Meta(m)), PropertyID, GroupID, ContractID)
+	Prop, err := fetchProperty(ctx, client, PropertyID, GroupID, ContractID)
 	if err != nil {
 		return nil, err
 	}
+	if Version > Prop.LatestVersion {
+		return nil, fmt.Errorf("%w: version %d of property %s", ErrVersionNotFound, Version, Prop.PropertyID)
+	}
 
 	attrs := map[string]interface{}{
-		"group_id":    Property.GroupID,
-		"contract_id": Property.ContractID,
+		"group_id":    Prop.GroupID,
+		"contract_id": Prop.ContractID,
+	}
+	if Version != 0 {
+		// The next update creates a version from the imported one
+		attrs["read_version"] = Version
 	}
 	if err := rdSetAttrs(ctx, d, attrs); err != nil {
 		return nil, err
 	}
 
-	d.SetId(Property.PropertyID)
+	d.SetId(Prop.PropertyID)
 	return []*schema.ResourceData{d}, nil
 }
 
+// propertyImportVersionRegexp matches an import ID with a version suffix like prp_123:v7
+var propertyImportVersionRegexp = regexp.MustCompile(`^(.+):v(\d+)$`)
+
+// parsePropertyImportVersion splits the version suffix from the property of an import ID, the version is zero without
+// a suffix
+func parsePropertyImportVersion(ID string) (Property string, Version int, err error) {
+	match := propertyImportVersionRegexp.FindStringSubmatch(ID)
+	if match == nil {
+		return ID, 0, nil
+	}
+
+	Version, err = strconv.Atoi(match[2])
+	if err != nil || Version < 1 {
+		return "", 0, fmt.Errorf("invalid property version in import ID: %q", ID)
+	}
+
+	return match[1], Version, nil
+}
+
+// resolvePropertyImportID returns the property of an import ID which is either a property ID, name:<property name>
+// or a hostname. The contract and group are only known when the property was searched.
+func resolvePropertyImportID(ctx context.Context, client papi.PAPI, ID string) (PropertyID, ContractID, GroupID string, err error) {
+	var req papi.SearchRequest
+	switch {
+	case strings.HasPrefix(ID, "name:"):
+		req = papi.SearchRequest{Key: papi.SearchKeyPropertyName, Value: strings.TrimPrefix(ID, "name:")}
+	case strings.Contains(ID, "."):
+		req = papi.SearchRequest{Key: papi.SearchKeyHostname, Value: ID}
+	default:
+		return tools.AddPrefix(ID, "prp_"), "", "", nil
+	}
+
+	item, err := searchProperty(ctx, client, req)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return item.PropertyID, item.ContractID, item.GroupID, nil
+}
+
+// searchProperty returns the single property matching the search, several matching properties are an error
+func searchProperty(ctx context.Context, client papi.PAPI, req papi.SearchRequest) (*papi.SearchItem, error) {
+	logger := log.FromContext(ctx).WithFields(log.Fields{"key": req.Key, "value": req.Value})
+
+	logger.Debug("searching property")
+	res, err := client.SearchProperties(ctx, req)
+	if err != nil {
+		logger.WithError(err).Error("could not search properties")
+		return nil, err
+	}
+
+	// The search returns the active and latest versions of the properties
+	found := make(map[string]papi.SearchItem)
+	for _, item := range res.Versions.Items {
+		found[item.PropertyID] = item
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s %q", ErrPropertyNotFound, req.Key, req.Value)
+	case 1:
+		for _, item := range found {
+			return &item, nil
+		}
+	}
+
+	var IDs []string
+	for id := range found {
+		IDs = append(IDs, id)
+	}
+	sort.Strings(IDs)
+	return nil, fmt.Errorf("%w: %s %q matches %s", ErrPropertyImportAmbiguous, req.Key, req.Value, strings.Join(IDs, ", "))
+}
+
 func resPropForbiddenAttrs() []string {
 	return []string{
 		"cp_code",
@@ -798,14 +1134,14 @@ func updatePropertyRules(ctx context.Context, client papi.PAPI, Property papi.Pr
 	return nil
 }
 
-// Create a new property version based on the latest version of the given property
-func createPropertyVersion(ctx context.Context, client papi.PAPI, Property papi.Property) (NewVersion int, err error) {
+// Create a new property version based on the given version of the given property
+func createPropertyVersion(ctx context.Context, client papi.PAPI, Property papi.Property, FromVersion int) (NewVersion int, err error) {
 	req := papi.CreatePropertyVersionRequest{
 		PropertyID: Property.PropertyID,
 		ContractID: Property.ContractID,
 		GroupID:    Property.GroupID,
 		Version: papi.PropertyVersionCreate{
-			CreateFromVersion: Property.LatestVersion,
+			CreateFromVersion: FromVersion,
 		},
 	}
 
@@ -849,6 +1185,23 @@ func updatePropertyHostnames(ctx context.Context, client papi.PAPI, Property pap
 	return nil
 }
 
+// Set the notes of the latest version of the given property
+func updatePropertyVersionNote(ctx context.Context, client papiVersionNotes, Property papi.Property, Note string) error {
+	logger := log.FromContext(ctx).WithFields(log.Fields{
+		"PropertyID":      Property.PropertyID,
+		"PropertyVersion": Property.LatestVersion,
+	})
+
+	logger.Debug("updating property version notes")
+	if err := client.UpdateVersionNotes(ctx, Property, Note); err != nil {
+		logger.WithError(err).Error("could not update property version notes")
+		return err
+	}
+
+	logger.Info("property version notes updated")
+	return nil
+}
+
 // Convert given hostnames to the map form that can be stored in a schema.ResourceData
 func hostnamesToMap(Hostnames []papi.Hostname) map[string]interface{} {
 	m := map[string]interface{}{}
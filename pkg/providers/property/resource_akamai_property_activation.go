@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/apex/log"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/spf13/cast"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
@@ -24,7 +28,11 @@ func resourcePropertyActivation() *schema.Resource {
 		UpdateContext: resourcePropertyActivationUpdate,
 		DeleteContext: resourcePropertyActivationDelete,
 		Schema:        akamaiPropertyActivationSchema,
+		CustomizeDiff: validateRollbackVersion,
 		Timeouts: &schema.ResourceTimeout{
+			Create:  &PropertyResourceTimeout,
+			Update:  &PropertyResourceTimeout,
+			Delete:  &PropertyResourceTimeout,
 			Default: &PropertyResourceTimeout,
 		},
 	}
@@ -32,17 +40,28 @@ func resourcePropertyActivation() *schema.Resource {
 
 const (
 	// ActivationPollMinimum is the minimum polling interval for activation creation
-	ActivationPollMinimum = time.Minute
+	ActivationPollMinimum = 10 * time.Second
 )
 
 var (
-	// ActivationPollInterval is the interval for polling an activation status on creation
-	ActivationPollInterval = ActivationPollMinimum
+	// ActivationPollInterval is the interval for polling an activation status when the resource sets no poll_interval
+	ActivationPollInterval = time.Minute
 
 	// PropertyResourceTimeout is the default timeout for the resource operations
 	PropertyResourceTimeout = time.Minute * 90
 )
 
+const (
+	// ComplianceReasonNone is the noncompliance reason of an activation following the change management process
+	ComplianceReasonNone = "NONE"
+	// ComplianceReasonOther is the noncompliance reason of an activation skipping the process for another reason
+	ComplianceReasonOther = "OTHER"
+	// ComplianceReasonNoProductionTraffic is the noncompliance reason of a property not serving production traffic
+	ComplianceReasonNoProductionTraffic = "NO_PRODUCTION_TRAFFIC"
+	// ComplianceReasonEmergency is the noncompliance reason of an emergency activation
+	ComplianceReasonEmergency = "EMERGENCY"
+)
+
 var akamaiPropertyActivationSchema = map[string]*schema.Schema{
 	"property": {
 		Type:       schema.TypeString,
@@ -90,6 +109,85 @@ var akamaiPropertyActivationSchema = map[string]*schema.Schema{
 		Type:     schema.TypeString,
 		Computed: true,
 	},
+	"poll_interval": {
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateDiagFunc: tools.ValidateDuration(ActivationPollMinimum),
+		Description:      "How often the activation status is checked, like 30s or 2m",
+	},
+	"note": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "A log message for the activation",
+	},
+	"compliance_record": {
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "The change management record sent with the activation of the version, not with deactivations or rollbacks",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"noncompliance_reason": {
+					Type:     schema.TypeString,
+					Required: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						ComplianceReasonNone, ComplianceReasonOther, ComplianceReasonNoProductionTraffic, ComplianceReasonEmergency,
+					}, false),
+					Description: "Why the activation does not follow the change management process, NONE when it does",
+				},
+				"ticket_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The ticket of the change",
+				},
+				"peer_reviewed_by": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The email address of the reviewer of the change",
+				},
+				"customer_email": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The email address of the customer contact of the change",
+				},
+			},
+		},
+	},
+	"use_fast_fallback": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Allow falling back to the previous version within an hour of the activation",
+	},
+	"acknowledge_warnings": {
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The message IDs of the activation warnings to acknowledge, all warnings are acknowledged when empty",
+	},
+	"auto_rollback": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Activate the previously active version when the activation fails or is aborted, an activation that times out is not rolled back",
+	},
+	"rollback_to_version": {
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		Description:  "The version activated when the activation fails or is aborted, instead of the previously active version",
+	},
+	"rollback_version": {
+		Type:        schema.TypeInt,
+		Computed:    true,
+		Description: "The version activated by the last rollback, zero when the activation was not rolled back",
+	},
+	"rollback_activation_id": {
+		Type:     schema.TypeString,
+		Computed: true,
+	},
+	"rollback_status": {
+		Type:     schema.TypeString,
+		Computed: true,
+	},
 }
 
 func resourcePropertyActivationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -101,7 +199,7 @@ func resourcePropertyActivationCreate(ctx context.Context, d *schema.ResourceDat
 
 	// create a context with logging for api calls
 	ctx = session.ContextWithOptions(
-		ctx,
+		log.NewContext(ctx, logger),
 		session.WithContextLog(logger),
 	)
 
@@ -174,35 +272,17 @@ func resourcePropertyActivationCreate(ctx context.Context, d *schema.ResourceDat
 	}
 
 	// we create a new property activation in case of no previous activation, or deleted activation
+	var rollbackTo int
 	if activation == nil || activation.ActivationType == papi.ActivationTypeDeactivate {
-		notifySet, err := tools.GetSetValue("contact", d)
-		if err != nil {
+		if rollbackTo, err = resolveRollbackVersion(ctx, d, client, propertyID, network, version); err != nil {
 			return diag.FromErr(err)
 		}
-		var notify []string
-		for _, contact := range notifySet.List() {
-			notify = append(notify, cast.ToString(contact))
-		}
 
-		create, err := client.CreateActivation(ctx, papi.CreateActivationRequest{
-			PropertyID: propertyID,
-			Activation: papi.Activation{
-				ActivationType:         papi.ActivationTypeActivate,
-				Network:                network,
-				PropertyVersion:        version,
-				NotifyEmails:           notify,
-				AcknowledgeAllWarnings: true,
-			},
-		})
+		record, err := complianceRecordFromConfig(d)
 		if err != nil {
-			return diag.FromErr(fmt.Errorf("create activation failed: %w", err))
+			return diag.FromErr(err)
 		}
-
-		// query the activation to retrieve the initial status
-		act, err := client.GetActivation(ctx, papi.GetActivationRequest{
-			ActivationID: create.ActivationID,
-			PropertyID:   propertyID,
-		})
+		act, err := submitActivation(ctx, meta, d, client, propertyID, network, version, record)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -221,32 +301,20 @@ func resourcePropertyActivationCreate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error()))
 	}
 
-	for activation.Status != papi.ActivationStatusActive {
-		if activation.Status == papi.ActivationStatusAborted {
-			return diag.FromErr(fmt.Errorf("activation request aborted"))
+	activation, err = waitForActivation(ctx, d, client, propertyID, activation)
+	if err != nil {
+		if rollbackTo != 0 && isActivationFailure(err) {
+			return rollbackActivation(ctx, meta, d, client, propertyID, network, activation, rollbackTo, d.Timeout(schema.TimeoutCreate), err)
 		}
-		if activation.Status == papi.ActivationStatusFailed {
-			return diag.FromErr(fmt.Errorf("activation request failed in downstream system"))
-		}
-		select {
-		case <-time.After(tools.MaxDuration(ActivationPollInterval, ActivationPollMinimum)):
-			act, err := client.GetActivation(ctx, papi.GetActivationRequest{
-				ActivationID: activation.ActivationID,
-				PropertyID:   propertyID,
-			})
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			activation = act.Activation
-
-		case <-ctx.Done():
-			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-				return diag.Diagnostics{DiagWarnActivationTimeout}
-			} else if errors.Is(ctx.Err(), context.Canceled) {
-				return diag.Diagnostics{DiagWarnActivationCanceled}
-			}
-			return diag.FromErr(fmt.Errorf("activation context terminated: %w", ctx.Err()))
+		if errors.Is(err, context.DeadlineExceeded) {
+			return diag.Diagnostics{DiagWarnActivationTimeout}
+		} else if errors.Is(err, context.Canceled) {
+			return diag.Diagnostics{DiagWarnActivationCanceled}
 		}
+		return diag.FromErr(err)
+	}
+	if err := clearRollback(d); err != nil {
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("version", activation.PropertyVersion); err != nil {
@@ -275,7 +343,7 @@ func resourcePropertyActivationDelete(ctx context.Context, d *schema.ResourceDat
 
 	// create a context with logging for api calls
 	ctx = session.ContextWithOptions(
-		ctx,
+		log.NewContext(ctx, logger),
 		session.WithContextLog(logger),
 	)
 
@@ -297,6 +365,13 @@ func resourcePropertyActivationDelete(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
+	// the activation was rolled back so the version is not active, the rollback version stays active
+	if rollbackVersion := d.Get("rollback_version").(int); rollbackVersion != 0 && rollbackVersion != version {
+		logger.Infof("activation of version %d was rolled back to version %d, nothing to deactivate", version, rollbackVersion)
+		d.SetId("")
+		return nil
+	}
+
 	activation, err := lookupActivation(ctx, client, lookupActivationRequest{
 		propertyID: propertyID,
 		version:    version,
@@ -311,25 +386,12 @@ func resourcePropertyActivationDelete(ctx context.Context, d *schema.ResourceDat
 	}
 
 	if activation == nil || activation.ActivationType == papi.ActivationTypeActivate {
-		notifySet, err := tools.GetSetValue("contact", d)
+		deactivation, err := activationFromConfig(d, papi.ActivationTypeDeactivate, network, version)
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		var notify []string
-		for _, contact := range notifySet.List() {
-			notify = append(notify, cast.ToString(contact))
-		}
 
-		deleteActivation, err := client.CreateActivation(ctx, papi.CreateActivationRequest{
-			PropertyID: propertyID,
-			Activation: papi.Activation{
-				ActivationType:         papi.ActivationTypeDeactivate,
-				Network:                network,
-				PropertyVersion:        version,
-				NotifyEmails:           notify,
-				AcknowledgeAllWarnings: true,
-			},
-		})
+		deleteActivation, err := createActivation(ctx, meta, client, propertyID, deactivation, nil)
 		if err != nil {
 			return diag.FromErr(fmt.Errorf("create deactivation failed: %w", err))
 		}
@@ -368,7 +430,7 @@ func resourcePropertyActivationDelete(ctx context.Context, d *schema.ResourceDat
 			return diag.FromErr(fmt.Errorf("deactivation request failed in downstream system"))
 		}
 		select {
-		case <-time.After(tools.MaxDuration(ActivationPollInterval, ActivationPollMinimum)):
+		case <-time.After(tools.PollInterval(d, ActivationPollInterval, ActivationPollMinimum)):
 			act, err := client.GetActivation(ctx, papi.GetActivationRequest{
 				ActivationID: activation.ActivationID,
 				PropertyID:   propertyID,
@@ -503,7 +565,7 @@ func resourcePropertyActivationUpdate(ctx context.Context, d *schema.ResourceDat
 	logger.Debug("resourcePropertyActivationUpdate call")
 	// create a context with logging for api calls
 	ctx = session.ContextWithOptions(
-		ctx,
+		log.NewContext(ctx, logger),
 		session.WithContextLog(logger),
 	)
 
@@ -570,35 +632,17 @@ func resourcePropertyActivationUpdate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
+	var rollbackTo int
 	if propertyActivation == nil {
-		notifySet, err := tools.GetSetValue("contact", d)
-		if err != nil {
+		if rollbackTo, err = resolveRollbackVersion(ctx, d, client, propertyID, network, version); err != nil {
 			return diag.FromErr(err)
 		}
-		var notify []string
-		for _, contact := range notifySet.List() {
-			notify = append(notify, cast.ToString(contact))
-		}
 
-		create, err := client.CreateActivation(ctx, papi.CreateActivationRequest{
-			PropertyID: propertyID,
-			Activation: papi.Activation{
-				ActivationType:         papi.ActivationTypeActivate,
-				Network:                network,
-				PropertyVersion:        version,
-				NotifyEmails:           notify,
-				AcknowledgeAllWarnings: true,
-			},
-		})
+		record, err := complianceRecordFromConfig(d)
 		if err != nil {
-			return diag.FromErr(fmt.Errorf("create activation failed: %w", err))
+			return diag.FromErr(err)
 		}
-
-		// query the activation to retrieve the initial status
-		act, err := client.GetActivation(ctx, papi.GetActivationRequest{
-			ActivationID: create.ActivationID,
-			PropertyID:   propertyID,
-		})
+		act, err := submitActivation(ctx, meta, d, client, propertyID, network, version, record)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -617,34 +661,22 @@ func resourcePropertyActivationUpdate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error()))
 	}
 
-	for propertyActivation.Status != papi.ActivationStatusActive {
-		if propertyActivation.Status == papi.ActivationStatusAborted {
-			return diag.FromErr(fmt.Errorf("activation request aborted"))
-		}
-		if propertyActivation.Status == papi.ActivationStatusFailed {
-			return diag.FromErr(fmt.Errorf("activation request failed in downstream system"))
-		}
-		select {
-		case <-time.After(tools.MaxDuration(ActivationPollInterval, ActivationPollMinimum)):
-			act, err := client.GetActivation(ctx, papi.GetActivationRequest{
-				ActivationID: propertyActivation.ActivationID,
-				PropertyID:   propertyID,
-			})
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			propertyActivation = act.Activation
-
-			if err := d.Set("errors", flattenErrorArray(act.Errors)); err != nil {
-				return diag.FromErr(fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error()))
-			}
-			if err := d.Set("warnings", flattenErrorArray(act.Warnings)); err != nil {
-				return diag.FromErr(fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error()))
+	propertyActivation, err = waitForActivation(ctx, d, client, propertyID, propertyActivation)
+	if err != nil {
+		if rollbackTo != 0 && isActivationFailure(err) {
+			diags := rollbackActivation(ctx, meta, d, client, propertyID, network, propertyActivation, rollbackTo, d.Timeout(schema.TimeoutUpdate), err)
+			// keep the previous version in the state so the next plan retries the activation
+			if old, _ := d.GetChange("version"); old != nil {
+				if err := d.Set("version", old); err != nil {
+					return append(diags, diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())...)
+				}
 			}
-
-		case <-ctx.Done():
-			return diag.FromErr(fmt.Errorf("activation context terminated: %w", ctx.Err()))
+			return diags
 		}
+		return diag.FromErr(err)
+	}
+	if err := clearRollback(d); err != nil {
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("version", propertyActivation.PropertyVersion); err != nil {
@@ -762,3 +794,273 @@ func networkAlias(d *schema.ResourceData) (papi.ActivationNetwork, error) {
 	}
 	return networkValue, nil
 }
+
+// activationContacts returns the email addresses notified of the activation status changes
+func activationContacts(d *schema.ResourceData) ([]string, error) {
+	notifySet, err := tools.GetSetValue("contact", d)
+	if err != nil {
+		return nil, err
+	}
+	var notify []string
+	for _, contact := range notifySet.List() {
+		notify = append(notify, cast.ToString(contact))
+	}
+
+	return notify, nil
+}
+
+// activationFromConfig returns the activation or deactivation of the version with the options of the resource
+func activationFromConfig(d *schema.ResourceData, activationType papi.ActivationType, network papi.ActivationNetwork, version int) (papi.Activation, error) {
+	notify, err := activationContacts(d)
+	if err != nil {
+		return papi.Activation{}, err
+	}
+
+	activation := papi.Activation{
+		ActivationType:         activationType,
+		Network:                network,
+		PropertyVersion:        version,
+		NotifyEmails:           notify,
+		Note:                   d.Get("note").(string),
+		UseFastFallback:        d.Get("use_fast_fallback").(bool),
+		AcknowledgeAllWarnings: true,
+	}
+
+	// only the listed warnings are acknowledged, the activation fails on any other warning
+	if warnings := d.Get("acknowledge_warnings").(*schema.Set); warnings.Len() > 0 {
+		activation.AcknowledgeAllWarnings = false
+		for _, w := range warnings.List() {
+			activation.AcknowledgeWarnings = append(activation.AcknowledgeWarnings, cast.ToString(w))
+		}
+		sort.Strings(activation.AcknowledgeWarnings)
+	}
+
+	return activation, nil
+}
+
+// complianceRecordFromConfig returns the compliance record of the resource, nil when none is configured
+func complianceRecordFromConfig(d *schema.ResourceData) (*complianceRecord, error) {
+	records, err := tools.GetListValue("compliance_record", d)
+	if err != nil {
+		if errors.Is(err, tools.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(records) == 0 || records[0] == nil {
+		return nil, nil
+	}
+
+	record := records[0].(map[string]interface{})
+	return &complianceRecord{
+		NoncomplianceReason: record["noncompliance_reason"].(string),
+		TicketID:            record["ticket_id"].(string),
+		PeerReviewedBy:      record["peer_reviewed_by"].(string),
+		CustomerEmail:       record["customer_email"].(string),
+	}, nil
+}
+
+// createActivation submits the activation, with the compliance record when one is given
+func createActivation(ctx context.Context, meta akamai.OperationMeta, client papi.PAPI, propertyID string, activation papi.Activation, record *complianceRecord) (*papi.CreateActivationResponse, error) {
+	if record == nil {
+		return client.CreateActivation(ctx, papi.CreateActivationRequest{
+			PropertyID: propertyID,
+			Activation: activation,
+		})
+	}
+
+	log.FromContext(ctx).WithFields(log.Fields{
+		"PropertyID":      propertyID,
+		"PropertyVersion": activation.PropertyVersion,
+	}).Debug("creating activation with compliance record")
+	return inst.ComplianceClient(meta).CreateActivation(ctx, propertyID, activationWithCompliance{Activation: activation, ComplianceRecord: record})
+}
+
+// submitActivation creates the activation of the version and returns its initial status, the compliance record is
+// only given to the activation requested by the configuration
+func submitActivation(ctx context.Context, meta akamai.OperationMeta, d *schema.ResourceData, client papi.PAPI, propertyID string, network papi.ActivationNetwork, version int, record *complianceRecord) (*papi.GetActivationResponse, error) {
+	activation, err := activationFromConfig(d, papi.ActivationTypeActivate, network, version)
+	if err != nil {
+		return nil, err
+	}
+
+	create, err := createActivation(ctx, meta, client, propertyID, activation, record)
+	if err != nil {
+		return nil, fmt.Errorf("create activation failed: %w", err)
+	}
+
+	// query the activation to retrieve the initial status
+	return client.GetActivation(ctx, papi.GetActivationRequest{
+		ActivationID: create.ActivationID,
+		PropertyID:   propertyID,
+	})
+}
+
+// waitForActivation polls the activation until it is active, it returns the last known activation with the error
+// when the activation fails, is aborted or the context is done
+func waitForActivation(ctx context.Context, d *schema.ResourceData, client papi.PAPI, propertyID string, activation *papi.Activation) (*papi.Activation, error) {
+	for activation.Status != papi.ActivationStatusActive {
+		if activation.Status == papi.ActivationStatusAborted {
+			return activation, ErrActivationAborted
+		}
+		if activation.Status == papi.ActivationStatusFailed {
+			return activation, ErrActivationFailed
+		}
+		select {
+		case <-time.After(tools.PollInterval(d, ActivationPollInterval, ActivationPollMinimum)):
+			act, err := client.GetActivation(ctx, papi.GetActivationRequest{
+				ActivationID: activation.ActivationID,
+				PropertyID:   propertyID,
+			})
+			if err != nil {
+				return activation, err
+			}
+			activation = act.Activation
+
+			if err := d.Set("errors", flattenErrorArray(act.Errors)); err != nil {
+				return activation, fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error())
+			}
+			if err := d.Set("warnings", flattenErrorArray(act.Warnings)); err != nil {
+				return activation, fmt.Errorf("%w: %s", tools.ErrValueSet, err.Error())
+			}
+
+		case <-ctx.Done():
+			return activation, fmt.Errorf("activation context terminated: %w", ctx.Err())
+		}
+	}
+
+	return activation, nil
+}
+
+// isActivationFailure tells whether the activation ended as failed or aborted. Only those are rolled back, an activation
+// that timed out or could not be polled may still go live.
+func isActivationFailure(err error) bool {
+	return errors.Is(err, ErrActivationFailed) || errors.Is(err, ErrActivationAborted)
+}
+
+// validateRollbackVersion rejects a rollback to the version the resource activates
+func validateRollbackVersion(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("version") || !d.NewValueKnown("rollback_to_version") {
+		return nil
+	}
+
+	version, rollbackVersion := d.Get("version").(int), d.Get("rollback_to_version").(int)
+	if rollbackVersion != 0 && rollbackVersion == version {
+		return fmt.Errorf("%w: both are %d", ErrRollbackVersion, version)
+	}
+
+	return nil
+}
+
+// resolveRollbackVersion returns the version to activate when the activation of version fails,
+// zero when the activation is not rolled back
+func resolveRollbackVersion(ctx context.Context, d *schema.ResourceData, client papi.PAPI, propertyID string, network papi.ActivationNetwork, version int) (int, error) {
+	logger := log.FromContext(ctx)
+
+	if v, ok := d.GetOk("rollback_to_version"); ok {
+		return v.(int), nil
+	}
+	if !d.Get("auto_rollback").(bool) {
+		return 0, nil
+	}
+
+	resp, err := client.GetLatestVersion(ctx, papi.GetLatestVersionRequest{
+		PropertyID:  propertyID,
+		ActivatedOn: string(network),
+	})
+	if err != nil {
+		var e *papi.Error
+		if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+			logger.Warnf("no version of %s is active on %s, the activation cannot be rolled back", propertyID, network)
+			return 0, nil
+		}
+		return 0, fmt.Errorf("%w: %s", ErrActivationRollback, err)
+	}
+
+	if resp.Version.PropertyVersion == version {
+		return 0, nil
+	}
+	return resp.Version.PropertyVersion, nil
+}
+
+// rollbackActivation activates the rollback version after the activation failed or was aborted.
+// Both activations are kept in the state and the cause is returned as an error.
+func rollbackActivation(ctx context.Context, meta akamai.OperationMeta, d *schema.ResourceData, client papi.PAPI, propertyID string, network papi.ActivationNetwork, failed *papi.Activation, rollbackTo int, timeout time.Duration, cause error) diag.Diagnostics {
+	logger := log.FromContext(ctx)
+	logger.Warnf("activation %s of version %d failed: %s, rolling back to version %d", failed.ActivationID, failed.PropertyVersion, cause, rollbackTo)
+
+	// the deadline of the failed activation may be over, the rollback gets its own
+	ctx, cancel := context.WithTimeout(detachedContext{ctx}, timeout)
+	defer cancel()
+
+	d.SetId(propertyID + ":" + string(network))
+	if err := tools.SetAttrs(d, map[string]interface{}{
+		"activation_id":    failed.ActivationID,
+		"status":           string(failed.Status),
+		"rollback_version": rollbackTo,
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	summary := fmt.Sprintf("activation of version %d on %s did not complete", failed.PropertyVersion, network)
+
+	// a version active before the failed activation is still active, lookupActivation finds it
+	rollback, err := lookupActivation(ctx, client, lookupActivationRequest{
+		propertyID: propertyID,
+		version:    rollbackTo,
+		network:    network,
+		activationType: map[papi.ActivationType]struct{}{
+			papi.ActivationTypeActivate: {},
+		},
+	})
+	if err == nil && rollback == nil {
+		var act *papi.GetActivationResponse
+		if act, err = submitActivation(ctx, meta, d, client, propertyID, network, rollbackTo, nil); err == nil {
+			rollback = act.Activation
+		}
+	}
+	if err == nil {
+		rollback, err = waitForActivation(ctx, d, client, propertyID, rollback)
+	}
+	if rollback != nil {
+		if err := tools.SetAttrs(d, map[string]interface{}{
+			"rollback_activation_id": rollback.ActivationID,
+			"rollback_status":        string(rollback.Status),
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   fmt.Sprintf("%s\n\n%s: version %d: %s", cause, ErrActivationRollback, rollbackTo, err),
+		}}
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  summary,
+		Detail:   fmt.Sprintf("%s\n\nThe activation was rolled back to version %d (activation %s).", cause, rollbackTo, rollback.ActivationID),
+	}}
+}
+
+// clearRollback resets the rollback attributes after an activation completed
+func clearRollback(d *schema.ResourceData) error {
+	return tools.SetAttrs(d, map[string]interface{}{
+		"rollback_version":       0,
+		"rollback_activation_id": "",
+		"rollback_status":        "",
+	})
+}
+
+// detachedContext keeps the values of a context, like its logger, without its deadline and cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
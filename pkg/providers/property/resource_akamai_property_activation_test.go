@@ -1,15 +1,23 @@
 package property
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/stretchr/testify/mock"
 	"log"
+	"net/http"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var testAccAkamaiPropertyActivationConfig = fmt.Sprintf(`
@@ -750,3 +758,392 @@ func testAccCheckAkamaiPropertyActivationLatest(s *terraform.State) error {
 	// TODO: rewrite for v2???
 	return nil
 }
+
+func TestResolveRollbackVersion(t *testing.T) {
+	tests := map[string]struct {
+		attrs    map[string]interface{}
+		calls    []papiCall
+		expected int
+	}{
+		"no rollback": {
+			attrs:    map[string]interface{}{},
+			expected: 0,
+		},
+		"explicit rollback version": {
+			attrs:    map[string]interface{}{"rollback_to_version": 3},
+			expected: 3,
+		},
+		"previously active version": {
+			attrs: map[string]interface{}{"auto_rollback": true},
+			calls: []papiCall{{
+				methodName:   "GetLatestVersion",
+				papiRequest:  papi.GetLatestVersionRequest{PropertyID: "prp_test", ActivatedOn: "STAGING"},
+				papiResponse: &papi.GetPropertyVersionsResponse{Version: papi.PropertyVersionGetItem{PropertyVersion: 2}},
+			}},
+			expected: 2,
+		},
+		"version already active": {
+			attrs: map[string]interface{}{"auto_rollback": true},
+			calls: []papiCall{{
+				methodName:   "GetLatestVersion",
+				papiResponse: &papi.GetPropertyVersionsResponse{Version: papi.PropertyVersionGetItem{PropertyVersion: 4}},
+			}},
+			expected: 0,
+		},
+		"no active version": {
+			attrs: map[string]interface{}{"auto_rollback": true},
+			calls: []papiCall{{
+				methodName:   "GetLatestVersion",
+				papiResponse: (*papi.GetPropertyVersionsResponse)(nil),
+				error:        &papi.Error{StatusCode: http.StatusNotFound},
+			}},
+			expected: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := mockPAPIClient(test.calls)
+			d := schema.TestResourceDataRaw(t, akamaiPropertyActivationSchema, test.attrs)
+
+			version, err := resolveRollbackVersion(context.Background(), d, client, "prp_test", papi.ActivationNetworkStaging, 4)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, version)
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestValidateRollbackVersion(t *testing.T) {
+	tests := map[string]struct {
+		rollbackVersion interface{}
+		withError       bool
+	}{
+		"no rollback version": {},
+		"earlier version":     {rollbackVersion: 3},
+		"activated version":   {rollbackVersion: 4, withError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			attrs := map[string]interface{}{"property": "prp_test", "contact": []interface{}{"user@example.com"}, "version": 4}
+			if test.rollbackVersion != nil {
+				attrs["rollback_to_version"] = test.rollbackVersion
+			}
+
+			_, err := resourcePropertyActivation().SimpleDiff(context.Background(), nil, terraform.NewResourceConfigRaw(attrs), testMeta(t))
+			if test.withError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), ErrRollbackVersion.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRollbackActivation(t *testing.T) {
+	failed := &papi.Activation{
+		ActivationID:    "atv_failed",
+		PropertyVersion: 4,
+		Network:         papi.ActivationNetworkStaging,
+		Status:          papi.ActivationStatusFailed,
+	}
+	active := &papi.Activation{
+		ActivationID:    "atv_previous",
+		ActivationType:  papi.ActivationTypeActivate,
+		PropertyID:      "prp_test",
+		PropertyVersion: 3,
+		Network:         papi.ActivationNetworkStaging,
+		Status:          papi.ActivationStatusActive,
+		SubmitDate:      "2020-10-28T15:04:05Z",
+	}
+
+	t.Run("previous version still active", func(t *testing.T) {
+		client := mockPAPIClient([]papiCall{{
+			methodName:   "GetActivations",
+			papiResponse: &papi.GetActivationsResponse{Activations: papi.ActivationsItems{Items: []*papi.Activation{active}}},
+		}})
+		d := schema.TestResourceDataRaw(t, akamaiPropertyActivationSchema, map[string]interface{}{"auto_rollback": true})
+
+		diags := rollbackActivation(context.Background(), nil, d, client, "prp_test", papi.ActivationNetworkStaging, failed, 3, time.Minute, ErrActivationFailed)
+		require.Len(t, diags, 1)
+		assert.Equal(t, diag.Error, diags[0].Severity)
+		assert.Contains(t, diags[0].Detail, ErrActivationFailed.Error())
+		assert.Contains(t, diags[0].Detail, "rolled back to version 3 (activation atv_previous)")
+
+		assert.Equal(t, "prp_test:STAGING", d.Id())
+		assert.Equal(t, "atv_failed", d.Get("activation_id"))
+		assert.Equal(t, "FAILED", d.Get("status"))
+		assert.Equal(t, 3, d.Get("rollback_version"))
+		assert.Equal(t, "atv_previous", d.Get("rollback_activation_id"))
+		assert.Equal(t, "ACTIVE", d.Get("rollback_status"))
+		client.AssertExpectations(t)
+	})
+
+	t.Run("aborted activation is rolled back by a new activation", func(t *testing.T) {
+		aborted := *failed
+		aborted.Status = papi.ActivationStatusAborted
+
+		client := mockPAPIClient([]papiCall{
+			{
+				methodName:   "GetActivations",
+				papiResponse: &papi.GetActivationsResponse{},
+			},
+			{
+				methodName:   "CreateActivation",
+				papiResponse: &papi.CreateActivationResponse{ActivationID: "atv_rollback"},
+			},
+			{
+				methodName:   "GetActivation",
+				papiRequest:  papi.GetActivationRequest{PropertyID: "prp_test", ActivationID: "atv_rollback"},
+				papiResponse: &papi.GetActivationResponse{Activation: &papi.Activation{ActivationID: "atv_rollback", PropertyVersion: 3, Status: papi.ActivationStatusActive}},
+			},
+		})
+		d := schema.TestResourceDataRaw(t, akamaiPropertyActivationSchema, map[string]interface{}{"contact": []interface{}{"user@example.com"}})
+
+		diags := rollbackActivation(context.Background(), nil, d, client, "prp_test", papi.ActivationNetworkStaging, &aborted, 3, time.Minute, ErrActivationAborted)
+		require.Len(t, diags, 1)
+		assert.Contains(t, diags[0].Detail, "rolled back to version 3 (activation atv_rollback)")
+		assert.Equal(t, "ABORTED", d.Get("status"))
+		assert.Equal(t, "atv_rollback", d.Get("rollback_activation_id"))
+		client.AssertExpectations(t)
+	})
+
+	t.Run("rollback fails", func(t *testing.T) {
+		client := mockPAPIClient([]papiCall{{
+			methodName:   "GetActivations",
+			papiResponse: (*papi.GetActivationsResponse)(nil),
+			error:        fmt.Errorf("oops"),
+		}})
+		d := schema.TestResourceDataRaw(t, akamaiPropertyActivationSchema, map[string]interface{}{})
+
+		diags := rollbackActivation(context.Background(), nil, d, client, "prp_test", papi.ActivationNetworkStaging, failed, 3, time.Minute, ErrActivationFailed)
+		require.Len(t, diags, 1)
+		assert.Contains(t, diags[0].Detail, "activation rollback: version 3: oops")
+		assert.Equal(t, 3, d.Get("rollback_version"))
+		assert.Equal(t, "", d.Get("rollback_activation_id"))
+	})
+}
+
+func TestActivationNotRolledBack(t *testing.T) {
+	pending := &papi.Activation{
+		ActivationID:    "atv_pending",
+		ActivationType:  papi.ActivationTypeActivate,
+		PropertyID:      "prp_test",
+		PropertyVersion: 4,
+		Network:         papi.ActivationNetworkStaging,
+		Status:          papi.ActivationStatusPending,
+	}
+	attrs := map[string]interface{}{
+		"property_id":         "prp_test",
+		"network":             "STAGING",
+		"version":             4,
+		"contact":             []interface{}{"user@example.com"},
+		"rollback_to_version": 3,
+		"poll_interval":       "10s",
+	}
+	submitCalls := func(poll papiCall) []papiCall {
+		return []papiCall{
+			{methodName: "GetRuleTree", papiResponse: &papi.GetRuleTreeResponse{}},
+			{methodName: "GetActivations", papiResponse: &papi.GetActivationsResponse{}},
+			{methodName: "CreateActivation", papiResponse: &papi.CreateActivationResponse{ActivationID: "atv_pending"}},
+			{methodName: "GetActivation", papiResponse: &papi.GetActivationResponse{Activation: pending}, stubOnce: true},
+			poll,
+		}
+	}
+	meta := testMeta(t)
+
+	t.Run("timeout keeps the pending activation", func(t *testing.T) {
+		client := mockPAPIClient(submitCalls(papiCall{methodName: "GetActivation", papiResponse: &papi.GetActivationResponse{Activation: pending}}))
+		d := schema.TestResourceDataRaw(t, akamaiPropertyActivationSchema, attrs)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		var diags diag.Diagnostics
+		useClient(client, func() {
+			diags = resourcePropertyActivationCreate(ctx, d, meta)
+		})
+
+		assert.Equal(t, diag.Diagnostics{DiagWarnActivationTimeout}, diags)
+		assert.Equal(t, 0, d.Get("rollback_version"))
+		client.AssertNumberOfCalls(t, "CreateActivation", 1)
+		client.AssertNotCalled(t, "CancelActivation", AnyCTX, mock.Anything)
+	})
+
+	t.Run("transient error polling the activation", func(t *testing.T) {
+		client := mockPAPIClient(submitCalls(papiCall{methodName: "GetActivation", papiResponse: (*papi.GetActivationResponse)(nil), error: fmt.Errorf("connection reset")}))
+		d := schema.TestResourceDataRaw(t, akamaiPropertyActivationSchema, attrs)
+
+		var diags diag.Diagnostics
+		useClient(client, func() {
+			diags = resourcePropertyActivationCreate(context.Background(), d, meta)
+		})
+
+		require.True(t, diags.HasError())
+		assert.Contains(t, diags[0].Summary, "connection reset")
+		assert.Equal(t, 0, d.Get("rollback_version"))
+		client.AssertNumberOfCalls(t, "CreateActivation", 1)
+		client.AssertNotCalled(t, "CancelActivation", AnyCTX, mock.Anything)
+	})
+}
+
+func TestActivationComplianceRecord(t *testing.T) {
+	failed := &papi.Activation{
+		ActivationID:    "atv_failed",
+		ActivationType:  papi.ActivationTypeActivate,
+		PropertyID:      "prp_test",
+		PropertyVersion: 4,
+		Network:         papi.ActivationNetworkProduction,
+		Status:          papi.ActivationStatusFailed,
+	}
+	rollback := &papi.Activation{
+		ActivationID:    "atv_rollback",
+		ActivationType:  papi.ActivationTypeActivate,
+		PropertyID:      "prp_test",
+		PropertyVersion: 3,
+		Network:         papi.ActivationNetworkProduction,
+		Status:          papi.ActivationStatusActive,
+	}
+	meta := testMeta(t)
+
+	t.Run("only the requested activation has the record", func(t *testing.T) {
+		client := mockPAPIClient([]papiCall{
+			{methodName: "GetRuleTree", papiResponse: &papi.GetRuleTreeResponse{}},
+			{methodName: "GetActivations", papiResponse: &papi.GetActivationsResponse{}},
+			{
+				methodName:   "GetActivation",
+				papiRequest:  papi.GetActivationRequest{PropertyID: "prp_test", ActivationID: "atv_failed"},
+				papiResponse: &papi.GetActivationResponse{Activation: failed},
+			},
+			// the rollback is created without the record through the PAPI client
+			{methodName: "CreateActivation", papiResponse: &papi.CreateActivationResponse{ActivationID: "atv_rollback"}},
+			{
+				methodName:   "GetActivation",
+				papiRequest:  papi.GetActivationRequest{PropertyID: "prp_test", ActivationID: "atv_rollback"},
+				papiResponse: &papi.GetActivationResponse{Activation: rollback},
+			},
+		})
+		compliance := &mockcompliance{}
+		compliance.On("CreateActivation", AnyCTX, "prp_test", activationWithCompliance{
+			Activation: papi.Activation{
+				ActivationType:         papi.ActivationTypeActivate,
+				Network:                papi.ActivationNetworkProduction,
+				PropertyVersion:        4,
+				NotifyEmails:           []string{"user@example.com"},
+				AcknowledgeAllWarnings: true,
+			},
+			ComplianceRecord: &complianceRecord{NoncomplianceReason: ComplianceReasonNone, TicketID: "CHG-123"},
+		}).Return(&papi.CreateActivationResponse{ActivationID: "atv_failed"}, nil).Once()
+
+		d := schema.TestResourceDataRaw(t, akamaiPropertyActivationSchema, map[string]interface{}{
+			"property_id":         "prp_test",
+			"network":             "PRODUCTION",
+			"version":             4,
+			"contact":             []interface{}{"user@example.com"},
+			"rollback_to_version": 3,
+			"poll_interval":       "10s",
+			"compliance_record": []interface{}{map[string]interface{}{
+				"noncompliance_reason": "NONE",
+				"ticket_id":            "CHG-123",
+			}},
+		})
+
+		var diags diag.Diagnostics
+		useClient(client, func() {
+			useComplianceClient(compliance, func() {
+				diags = resourcePropertyActivationCreate(context.Background(), d, meta)
+			})
+		})
+
+		require.True(t, diags.HasError())
+		assert.Contains(t, diags[0].Detail, "rolled back to version 3 (activation atv_rollback)")
+		client.AssertNumberOfCalls(t, "CreateActivation", 1)
+		client.AssertExpectations(t)
+		compliance.AssertExpectations(t)
+	})
+
+	t.Run("deactivation without the record", func(t *testing.T) {
+		client := mockPAPIClient([]papiCall{{
+			methodName: "CreateActivation",
+			papiRequest: papi.CreateActivationRequest{
+				PropertyID: "prp_test",
+				Activation: papi.Activation{ActivationType: papi.ActivationTypeDeactivate, PropertyVersion: 4},
+			},
+			papiResponse: &papi.CreateActivationResponse{ActivationID: "atv_deactivate"},
+		}})
+		compliance := &mockcompliance{}
+
+		useComplianceClient(compliance, func() {
+			res, err := createActivation(context.Background(), nil, client, "prp_test",
+				papi.Activation{ActivationType: papi.ActivationTypeDeactivate, PropertyVersion: 4}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "atv_deactivate", res.ActivationID)
+		})
+		client.AssertExpectations(t)
+		compliance.AssertNotCalled(t, "CreateActivation", AnyCTX, mock.Anything, mock.Anything)
+	})
+}
+
+func TestActivationFromConfig(t *testing.T) {
+	t.Run("defaults acknowledge all warnings", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, akamaiPropertyActivationSchema, map[string]interface{}{
+			"contact": []interface{}{"user@example.com"},
+		})
+
+		activation, err := activationFromConfig(d, papi.ActivationTypeActivate, papi.ActivationNetworkProduction, 2)
+		require.NoError(t, err)
+		assert.Equal(t, papi.Activation{
+			ActivationType:         papi.ActivationTypeActivate,
+			Network:                papi.ActivationNetworkProduction,
+			PropertyVersion:        2,
+			NotifyEmails:           []string{"user@example.com"},
+			AcknowledgeAllWarnings: true,
+		}, activation)
+
+		record, err := complianceRecordFromConfig(d)
+		require.NoError(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("note, fast fallback, warnings and compliance record", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, akamaiPropertyActivationSchema, map[string]interface{}{
+			"contact":              []interface{}{"user@example.com"},
+			"note":                 "release 42",
+			"use_fast_fallback":    true,
+			"acknowledge_warnings": []interface{}{"msg_2", "msg_1"},
+			"compliance_record": []interface{}{map[string]interface{}{
+				"noncompliance_reason": "NONE",
+				"ticket_id":            "CHG-123",
+				"peer_reviewed_by":     "reviewer@example.com",
+				"customer_email":       "customer@example.com",
+			}},
+		})
+
+		activation, err := activationFromConfig(d, papi.ActivationTypeDeactivate, papi.ActivationNetworkProduction, 2)
+		require.NoError(t, err)
+		assert.Equal(t, papi.Activation{
+			ActivationType:      papi.ActivationTypeDeactivate,
+			Network:             papi.ActivationNetworkProduction,
+			PropertyVersion:     2,
+			NotifyEmails:        []string{"user@example.com"},
+			Note:                "release 42",
+			UseFastFallback:     true,
+			AcknowledgeWarnings: []string{"msg_1", "msg_2"},
+		}, activation)
+
+		record, err := complianceRecordFromConfig(d)
+		require.NoError(t, err)
+		assert.Equal(t, &complianceRecord{
+			NoncomplianceReason: "NONE",
+			TicketID:            "CHG-123",
+			PeerReviewedBy:      "reviewer@example.com",
+			CustomerEmail:       "customer@example.com",
+		}, record)
+
+		body, err := json.Marshal(activationWithCompliance{Activation: activation, ComplianceRecord: record})
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"complianceRecord":{"noncomplianceReason":"NONE","ticketId":"CHG-123"`)
+		assert.Contains(t, string(body), `"note":"release 42"`)
+	})
+}
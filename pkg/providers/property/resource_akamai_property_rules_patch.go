@@ -0,0 +1,341 @@
+package property
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+const (
+	// RulesPatchStatusPatched is the result of a property whose rules were patched
+	RulesPatchStatusPatched = "PATCHED"
+	// RulesPatchStatusUnchanged is the result of a property whose rules already matched the patch
+	RulesPatchStatusUnchanged = "UNCHANGED"
+	// RulesPatchStatusFailed is the result of a property that could not be patched
+	RulesPatchStatusFailed = "FAILED"
+)
+
+var rulesPatchSearchKeys = []string{"search.0.property_name", "search.0.hostname", "search.0.edge_hostname"}
+
+// PAPI bulk rule tree patch
+//
+// Applies a JSON patch (RFC 6902) to the rule tree of the latest version of many properties. A new version is created
+// when the latest version is active, properties are patched independently and the result of each one is recorded.
+// Any change of the arguments applies the patch again, destroying the resource does not revert the patched versions.
+//
+// https://developer.akamai.com/api/core_features/property_manager/v1.html#patchpropertyversionrules
+func resourcePropertyRulesPatch() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePropertyRulesPatchCreate,
+		ReadContext:   schema.NoopContext,
+
+		// NB: the patched versions are kept, the patch is only removed from the state
+		DeleteContext: schema.NoopContext,
+
+		Schema: map[string]*schema.Schema{
+			"property_ids": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"property_ids", "search"},
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Description:  "The properties to patch",
+			},
+			"search": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				ForceNew:     true,
+				MaxItems:     1,
+				AtLeastOneOf: []string{"property_ids", "search"},
+				Description:  "Patch the properties found by name, hostname or edge hostname, like the akamai_property_search data source",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"property_name": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ExactlyOneOf: rulesPatchSearchKeys,
+						},
+						"hostname": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ExactlyOneOf: rulesPatchSearchKeys,
+						},
+						"edge_hostname": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ExactlyOneOf: rulesPatchSearchKeys,
+						},
+					},
+				},
+			},
+			"patch": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateJSONPatch,
+				Description:      "The JSON patch applied to the rule tree, paths start at the rule tree document like /rules/behaviors/0",
+				StateFunc: func(v interface{}) string {
+					return compactJSON([]byte(v.(string)))
+				},
+			},
+			"version_notes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The notes of the versions with patched rules",
+			},
+			"results": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The result of the patch of each property, sorted by property ID",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"property_id": {Type: schema.TypeString, Computed: true},
+						"version":     {Type: schema.TypeInt, Computed: true},
+						"status":      {Type: schema.TypeString, Computed: true},
+						"error":       {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourcePropertyRulesPatchCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("PAPI", "resourcePropertyRulesPatchCreate")
+	client := inst.Client(meta)
+	ctx = log.NewContext(ctx, logger)
+
+	ops, err := decodeJSONPatch(d.Get("patch").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	Properties, err := rulesPatchProperties(ctx, client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	Note := d.Get("version_notes").(string)
+
+	var diags diag.Diagnostics
+	results := make([]interface{}, 0, len(Properties))
+	IDs := make([]string, 0, len(Properties))
+	for _, Property := range Properties {
+		IDs = append(IDs, Property.PropertyID)
+
+		Version, Changed, err := patchPropertyRules(ctx, meta, client, Property, ops, Note)
+		result := map[string]interface{}{
+			"property_id": Property.PropertyID,
+			"version":     Version,
+			"status":      RulesPatchStatusPatched,
+			"error":       "",
+		}
+		switch {
+		case err != nil:
+			logger.WithError(err).Warnf("could not patch the rules of %s", Property.PropertyID)
+			result["status"] = RulesPatchStatusFailed
+			result["error"] = err.Error()
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("%s: %s", ErrRulesPatch, Property.PropertyID),
+				Detail:   err.Error(),
+			})
+		case !Changed:
+			result["status"] = RulesPatchStatusUnchanged
+		}
+		results = append(results, result)
+	}
+
+	d.SetId(tools.GetSHAString(strings.Join(IDs, ",") + d.Get("patch").(string)))
+	if err := d.Set("results", results); err != nil {
+		return append(diags, diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())...)
+	}
+
+	return diags
+}
+
+// rulesPatchProperties returns the listed and searched properties sorted by ID, without duplicates
+func rulesPatchProperties(ctx context.Context, client papi.PAPI, d *schema.ResourceData) ([]papi.Property, error) {
+	byID := make(map[string]papi.Property)
+
+	if set, err := tools.GetSetValue("property_ids", d); err == nil {
+		for _, id := range set.List() {
+			PropertyID := tools.AddPrefix(id.(string), "prp_")
+			byID[PropertyID] = papi.Property{PropertyID: PropertyID}
+		}
+	}
+
+	if search, err := tools.GetListValue("search", d); err == nil && len(search) > 0 && search[0] != nil {
+		var req papi.SearchRequest
+		for attr, key := range map[string]string{
+			"property_name": papi.SearchKeyPropertyName,
+			"hostname":      papi.SearchKeyHostname,
+			"edge_hostname": papi.SearchKeyEdgeHostname,
+		} {
+			if value := search[0].(map[string]interface{})[attr].(string); value != "" {
+				req = papi.SearchRequest{Key: key, Value: value}
+			}
+		}
+
+		res, err := client.SearchProperties(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: searching properties: %s", ErrRulesPatch, err)
+		}
+		for _, item := range res.Versions.Items {
+			byID[item.PropertyID] = papi.Property{
+				PropertyID: item.PropertyID,
+				ContractID: item.ContractID,
+				GroupID:    item.GroupID,
+			}
+		}
+	}
+
+	Properties := make([]papi.Property, 0, len(byID))
+	for _, Property := range byID {
+		Properties = append(Properties, Property)
+	}
+	sort.Slice(Properties, func(i, j int) bool { return Properties[i].PropertyID < Properties[j].PropertyID })
+
+	return Properties, nil
+}
+
+// patchPropertyRules applies the patch to the rules of the latest version of the property. A new version is created
+// when the latest version is active. It returns the version holding the patched rules, or the latest version when the
+// patch changes nothing.
+func patchPropertyRules(ctx context.Context, meta akamai.OperationMeta, client papi.PAPI, Property papi.Property, ops []tools.JSONPatchOperation, Note string) (int, bool, error) {
+	Fetched, err := fetchProperty(ctx, client, Property.PropertyID, Property.GroupID, Property.ContractID)
+	if err != nil {
+		return 0, false, err
+	}
+	Property = *Fetched
+
+	Rules, _, _, _, err := fetchPropertyRules(ctx, client, Property)
+	if err != nil {
+		return Property.LatestVersion, false, err
+	}
+
+	Patched, Changed, err := applyRulesPatch(Rules, ops)
+	if err != nil || !Changed {
+		return Property.LatestVersion, false, err
+	}
+
+	resp, err := client.GetPropertyVersion(ctx, papi.GetPropertyVersionRequest{
+		PropertyID:      Property.PropertyID,
+		PropertyVersion: Property.LatestVersion,
+		ContractID:      Property.ContractID,
+		GroupID:         Property.GroupID,
+	})
+	if err != nil {
+		return Property.LatestVersion, false, err
+	}
+	if resp.Version.ProductionStatus != papi.VersionStatusInactive || resp.Version.StagingStatus != papi.VersionStatusInactive {
+		Version, err := createPropertyVersion(ctx, client, Property, Property.LatestVersion)
+		if err != nil {
+			return Property.LatestVersion, false, fmt.Errorf("%w: %s", ErrVersionCreate, err)
+		}
+		Property.LatestVersion = Version
+	}
+
+	if err := updatePropertyRules(ctx, client, Property, Patched); err != nil {
+		return Property.LatestVersion, false, err
+	}
+	if Note != "" {
+		if err := updatePropertyVersionNote(ctx, inst.VersionNotesClient(meta), Property, Note); err != nil {
+			return Property.LatestVersion, true, err
+		}
+	}
+
+	return Property.LatestVersion, true, nil
+}
+
+// applyRulesPatch applies the patch to the rule tree document, the paths of the patch start at the document like /rules
+func applyRulesPatch(Rules papi.RulesUpdate, ops []tools.JSONPatchOperation) (papi.RulesUpdate, bool, error) {
+	decode := func(data []byte, v interface{}) error {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		return dec.Decode(v)
+	}
+
+	data, err := json.Marshal(Rules)
+	if err != nil {
+		return papi.RulesUpdate{}, false, err
+	}
+	var doc, original interface{}
+	if err := decode(data, &doc); err != nil {
+		return papi.RulesUpdate{}, false, err
+	}
+	if err := decode(data, &original); err != nil {
+		return papi.RulesUpdate{}, false, err
+	}
+
+	patched, err := tools.ApplyJSONPatch(doc, ops)
+	if err != nil {
+		return papi.RulesUpdate{}, false, fmt.Errorf("%w: %s", ErrRulesPatch, err)
+	}
+
+	// numbers of the patch are decoded as float64, compare the documents once encoded
+	if data, err = json.Marshal(patched); err != nil {
+		return papi.RulesUpdate{}, false, err
+	}
+	var normalized interface{}
+	if err := decode(data, &normalized); err != nil {
+		return papi.RulesUpdate{}, false, err
+	}
+	if reflect.DeepEqual(original, normalized) {
+		return Rules, false, nil
+	}
+
+	var Patched papi.RulesUpdate
+	if err := decode(data, &Patched); err != nil {
+		return papi.RulesUpdate{}, false, fmt.Errorf("%w: the patched rule tree is not valid: %s", ErrRulesPatch, err)
+	}
+	return Patched, true, nil
+}
+
+func decodeJSONPatch(patch string) ([]tools.JSONPatchOperation, error) {
+	var ops []tools.JSONPatchOperation
+	if err := json.Unmarshal([]byte(patch), &ops); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrRulesPatch, err)
+	}
+	for i, op := range ops {
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fmt.Errorf("%w: operation %d: %q requires a value", ErrRulesPatch, i, op.Op)
+			}
+		case "move", "copy":
+			if op.From == "" {
+				return nil, fmt.Errorf("%w: operation %d: %q requires from", ErrRulesPatch, i, op.Op)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("%w: operation %d: unknown operation %q", ErrRulesPatch, i, op.Op)
+		}
+	}
+	return ops, nil
+}
+
+func validateJSONPatch(v interface{}, _ cty.Path) diag.Diagnostics {
+	if _, err := decodeJSONPatch(v.(string)); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+)
+
+func patchTestRules(httpPort string) papi.RulesUpdate {
+	return papi.RulesUpdate{Rules: papi.Rules{
+		Name: "default",
+		Behaviors: []papi.RuleBehavior{{
+			Name:    "origin",
+			Options: papi.RuleOptionsMap{"hostname": "origin.example.com", "httpPort": json.Number(httpPort)},
+		}},
+	}}
+}
+
+func TestResPropertyRulesPatch(t *testing.T) {
+	t.Run("patch listed properties", func(t *testing.T) {
+		client := &mockpapi{}
+		RuleFormat := "v2020-03-04"
+
+		// prp_1 latest version is active, the patch goes to a new version
+		ExpectGetProperty(client, "prp_1", "", "", &papi.Property{PropertyID: "prp_1", LatestVersion: 1})
+		ExpectGetRuleTree(client, "prp_1", "", "", 1, &papi.RulesUpdate{Rules: patchTestRules("80").Rules}, &RuleFormat)
+		ExpectGetPropertyVersion(client, "prp_1", "", "", 1, papi.VersionStatusActive, papi.VersionStatusInactive)
+		ExpectCreatePropertyVersion(client, "prp_1", "", "", 1, 2)
+		ExpectUpdateRuleTree(client, "prp_1", "", "", 2, patchTestRules("8080"), RuleFormat)
+
+		// prp_2 is already patched
+		ExpectGetProperty(client, "prp_2", "", "", &papi.Property{PropertyID: "prp_2", LatestVersion: 4})
+		ExpectGetRuleTree(client, "prp_2", "", "", 4, &papi.RulesUpdate{Rules: patchTestRules("8080").Rules}, &RuleFormat)
+
+		useClient(client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{{
+					Config: loadFixtureString("testdata/TestResPropertyRulesPatch/patch.tf"),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("akamai_property_rules_patch.http_port", "results.#", "2"),
+						resource.TestCheckResourceAttr("akamai_property_rules_patch.http_port", "results.0.property_id", "prp_1"),
+						resource.TestCheckResourceAttr("akamai_property_rules_patch.http_port", "results.0.version", "2"),
+						resource.TestCheckResourceAttr("akamai_property_rules_patch.http_port", "results.0.status", RulesPatchStatusPatched),
+						resource.TestCheckResourceAttr("akamai_property_rules_patch.http_port", "results.1.property_id", "prp_2"),
+						resource.TestCheckResourceAttr("akamai_property_rules_patch.http_port", "results.1.version", "4"),
+						resource.TestCheckResourceAttr("akamai_property_rules_patch.http_port", "results.1.status", RulesPatchStatusUnchanged),
+					),
+				}},
+			})
+		})
+
+		client.AssertExpectations(t)
+	})
+}
+
+func TestPatchPropertyRules(t *testing.T) {
+	RuleFormat := "v2020-03-04"
+	ops, err := decodeJSONPatch(`[{"op":"replace","path":"/rules/behaviors/0/options/httpPort","value":8080}]`)
+	require.NoError(t, err)
+
+	t.Run("inactive latest version is patched in place", func(t *testing.T) {
+		client := &mockpapi{}
+		ExpectGetProperty(client, "prp_1", "grp_1", "ctr_1", &papi.Property{PropertyID: "prp_1", GroupID: "grp_1", ContractID: "ctr_1", LatestVersion: 3})
+		ExpectGetRuleTree(client, "prp_1", "grp_1", "ctr_1", 3, &papi.RulesUpdate{Rules: patchTestRules("80").Rules}, &RuleFormat)
+		ExpectGetPropertyVersion(client, "prp_1", "grp_1", "ctr_1", 3, papi.VersionStatusInactive, papi.VersionStatusInactive)
+		ExpectUpdateRuleTree(client, "prp_1", "grp_1", "ctr_1", 3, patchTestRules("8080"), RuleFormat)
+
+		Version, Changed, err := patchPropertyRules(context.Background(), nil, client, papi.Property{PropertyID: "prp_1", GroupID: "grp_1", ContractID: "ctr_1"}, ops, "")
+		require.NoError(t, err)
+		assert.True(t, Changed)
+		assert.Equal(t, 3, Version)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("notes set on the patched version", func(t *testing.T) {
+		client := &mockpapi{}
+		ExpectGetProperty(client, "prp_1", "", "", &papi.Property{PropertyID: "prp_1", LatestVersion: 1})
+		ExpectGetRuleTree(client, "prp_1", "", "", 1, &papi.RulesUpdate{Rules: patchTestRules("80").Rules}, &RuleFormat)
+		ExpectGetPropertyVersion(client, "prp_1", "", "", 1, papi.VersionStatusActive, papi.VersionStatusInactive)
+		ExpectCreatePropertyVersion(client, "prp_1", "", "", 1, 2)
+		ExpectUpdateRuleTree(client, "prp_1", "", "", 2, patchTestRules("8080"), RuleFormat)
+		notes := &mockversionnotes{}
+		ExpectUpdateVersionNotes(notes, "prp_1", 2, "http port 8080").Once()
+
+		useVersionNotesClient(notes, func() {
+			Version, Changed, err := patchPropertyRules(context.Background(), nil, client, papi.Property{PropertyID: "prp_1"}, ops, "http port 8080")
+			require.NoError(t, err)
+			assert.True(t, Changed)
+			assert.Equal(t, 2, Version)
+		})
+		client.AssertExpectations(t)
+		notes.AssertExpectations(t)
+	})
+
+	t.Run("notes that can not be set", func(t *testing.T) {
+		client := &mockpapi{}
+		ExpectGetProperty(client, "prp_1", "", "", &papi.Property{PropertyID: "prp_1", LatestVersion: 3})
+		ExpectGetRuleTree(client, "prp_1", "", "", 3, &papi.RulesUpdate{Rules: patchTestRules("80").Rules}, &RuleFormat)
+		ExpectGetPropertyVersion(client, "prp_1", "", "", 3, papi.VersionStatusInactive, papi.VersionStatusInactive)
+		ExpectUpdateRuleTree(client, "prp_1", "", "", 3, patchTestRules("8080"), RuleFormat)
+		notes := &mockversionnotes{}
+		notes.On("UpdateVersionNotes", AnyCTX, mock.Anything, mock.Anything).Return(fmt.Errorf("%w: forbidden", ErrVersionNote))
+
+		useVersionNotesClient(notes, func() {
+			Version, Changed, err := patchPropertyRules(context.Background(), nil, client, papi.Property{PropertyID: "prp_1"}, ops, "http port 8080")
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrVersionNote), "want %v, got %v", ErrVersionNote, err)
+			assert.True(t, Changed)
+			assert.Equal(t, 3, Version)
+		})
+		client.AssertExpectations(t)
+	})
+
+	t.Run("patch that does not apply", func(t *testing.T) {
+		client := &mockpapi{}
+		ExpectGetProperty(client, "prp_1", "", "", &papi.Property{PropertyID: "prp_1", LatestVersion: 3})
+		ExpectGetRuleTree(client, "prp_1", "", "", 3, &papi.RulesUpdate{Rules: papi.Rules{Name: "default"}}, &RuleFormat)
+
+		Version, Changed, err := patchPropertyRules(context.Background(), nil, client, papi.Property{PropertyID: "prp_1"}, ops, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), ErrRulesPatch.Error())
+		assert.False(t, Changed)
+		assert.Equal(t, 3, Version)
+		client.AssertExpectations(t)
+	})
+}
+
+func TestDecodeJSONPatch(t *testing.T) {
+	tests := map[string]struct {
+		patch     string
+		withError bool
+	}{
+		"valid patch":        {patch: `[{"op":"add","path":"/rules/comments","value":"x"},{"op":"remove","path":"/rules/children/0"}]`},
+		"not a list":         {patch: `{"op":"remove","path":"/rules"}`, withError: true},
+		"unknown operation":  {patch: `[{"op":"merge","path":"/rules"}]`, withError: true},
+		"missing value":      {patch: `[{"op":"replace","path":"/rules/name"}]`, withError: true},
+		"missing from":       {patch: `[{"op":"move","path":"/rules/name"}]`, withError: true},
+		"null value is kept": {patch: `[{"op":"replace","path":"/rules/comments","value":null}]`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeJSONPatch(test.patch)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
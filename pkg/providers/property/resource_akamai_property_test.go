@@ -1,12 +1,18 @@
 package property
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
 )
@@ -111,6 +117,13 @@ func TestResProperty(t *testing.T) {
 			// Depending on how much of the import ID is given, the initial property lookup may not have group/contract
 			ExpectGetProperty(State.Client, "prp_0", "grp_0", "", &State.Property).Maybe()
 			ExpectGetProperty(State.Client, "prp_0", "", "", &State.Property).Maybe()
+
+			// Imports by name or hostname search the property first
+			found := &papi.SearchResponse{Versions: papi.SearchItems{Items: []papi.SearchItem{
+				{PropertyID: "prp_0", PropertyName: "test property", ContractID: "ctr_0", GroupID: "grp_0", PropertyVersion: 1},
+			}}}
+			State.Client.On("SearchProperties", AnyCTX, papi.SearchRequest{Key: papi.SearchKeyPropertyName, Value: "test property"}).Return(found, nil).Maybe()
+			State.Client.On("SearchProperties", AnyCTX, papi.SearchRequest{Key: papi.SearchKeyHostname, Value: "from.test.domain"}).Return(found, nil).Maybe()
 		}
 	}
 
@@ -513,6 +526,8 @@ func TestResProperty(t *testing.T) {
 		AssertImportable(t, "unprefixed property_id", "0")
 		AssertImportable(t, "property_id and contract_id and group_id", "prp_0,ctr_0,grp_0")
 		AssertImportable(t, "unprefixed property_id and contract_id and group_id", "0,0,0")
+		AssertImportable(t, "property name", "name:test property")
+		AssertImportable(t, "hostname", "from.test.domain")
 
 		t.Run("property is destroyed and recreated when name is changed", func(t *testing.T) {
 			client := &mockpapi{}
@@ -738,3 +753,366 @@ func TestResProperty(t *testing.T) {
 		})
 	})
 }
+
+func TestParsePropertyImportVersion(t *testing.T) {
+	tests := map[string]struct {
+		ID       string
+		Property string
+		Version  int
+		WithErr  bool
+	}{
+		"property ID":              {ID: "prp_123", Property: "prp_123"},
+		"property ID with version": {ID: "prp_123:v7", Property: "prp_123", Version: 7},
+		"name with version":        {ID: "name:my-prop:v2", Property: "name:my-prop", Version: 2},
+		"hostname":                 {ID: "www.example.com", Property: "www.example.com"},
+		"name ending like version": {ID: "name:my-prop:vx", Property: "name:my-prop:vx"},
+		"version zero":             {ID: "prp_123:v0", WithErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			Property, Version, err := parsePropertyImportVersion(test.ID)
+			if test.WithErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Property, Property)
+			assert.Equal(t, test.Version, Version)
+		})
+	}
+}
+
+func TestResolvePropertyImportID(t *testing.T) {
+	search := func(client *mockpapi, key, value string, items ...papi.SearchItem) {
+		client.On("SearchProperties", AnyCTX, papi.SearchRequest{Key: key, Value: value}).
+			Return(&papi.SearchResponse{Versions: papi.SearchItems{Items: items}}, nil).Once()
+	}
+	item := func(PropertyID string, Version int) papi.SearchItem {
+		return papi.SearchItem{PropertyID: PropertyID, ContractID: "ctr_1", GroupID: "grp_2", PropertyVersion: Version}
+	}
+
+	t.Run("property ID", func(t *testing.T) {
+		client := &mockpapi{}
+		PropertyID, ContractID, GroupID, err := resolvePropertyImportID(context.Background(), client, "123")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prp_123", "", ""}, []string{PropertyID, ContractID, GroupID})
+		client.AssertExpectations(t)
+	})
+
+	t.Run("name with several versions", func(t *testing.T) {
+		client := &mockpapi{}
+		search(client, papi.SearchKeyPropertyName, "my-prop", item("prp_1", 3), item("prp_1", 4))
+		PropertyID, ContractID, GroupID, err := resolvePropertyImportID(context.Background(), client, "name:my-prop")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prp_1", "ctr_1", "grp_2"}, []string{PropertyID, ContractID, GroupID})
+		client.AssertExpectations(t)
+	})
+
+	t.Run("hostname not found", func(t *testing.T) {
+		client := &mockpapi{}
+		search(client, papi.SearchKeyHostname, "www.example.com")
+		_, _, _, err := resolvePropertyImportID(context.Background(), client, "www.example.com")
+		assert.True(t, errors.Is(err, ErrPropertyNotFound))
+		client.AssertExpectations(t)
+	})
+
+	t.Run("hostname of several properties", func(t *testing.T) {
+		client := &mockpapi{}
+		search(client, papi.SearchKeyHostname, "www.example.com", item("prp_2", 1), item("prp_1", 1))
+		_, _, _, err := resolvePropertyImportID(context.Background(), client, "www.example.com")
+		require.True(t, errors.Is(err, ErrPropertyImportAmbiguous))
+		assert.Contains(t, err.Error(), "prp_1, prp_2")
+		client.AssertExpectations(t)
+	})
+}
+
+// An import of a specific version can't carry over to an apply step of resource.UnitTest, the import step runs in its
+// own working directory, so the resource is driven through the plugin SDK
+func TestResPropertyImportVersion(t *testing.T) {
+	ctx := context.Background()
+	res := testProvider.ResourcesMap["akamai_property"]
+	meta := testMeta(t)
+
+	property := papi.Property{
+		PropertyID:    "prp_0",
+		PropertyName:  "test property",
+		ContractID:    "ctr_0",
+		GroupID:       "grp_0",
+		ProductID:     "prd_0",
+		LatestVersion: 2,
+	}
+	v1Hostnames := []papi.Hostname{{CnameType: "EDGE_HOSTNAME", CnameFrom: "from.test.domain", CnameTo: "to.test.domain"}}
+	v2Hostnames := []papi.Hostname{{CnameType: "EDGE_HOSTNAME", CnameFrom: "from.test.domain", CnameTo: "to2.test.domain"}}
+	v3Hostnames := []papi.Hostname{{CnameType: "EDGE_HOSTNAME", CnameFrom: "from.test.domain", CnameTo: "to3.test.domain"}}
+	rules := papi.RulesUpdate{Rules: papi.Rules{Name: "default"}}
+	ruleFormat := "v2020-01-01"
+	var v3State []papi.Hostname
+
+	client := &mockpapi{}
+	client.Test(T{t})
+	// The product is returned, an update of an imported property compares it with the configured one
+	getProperty := func(context.Context, papi.GetPropertyRequest) (*papi.GetPropertyResponse, error) {
+		p := property
+		return &papi.GetPropertyResponse{Property: &p}, nil
+	}
+	client.OnGetProperty(AnyCTX, papi.GetPropertyRequest{PropertyID: "prp_0"}, getProperty).Once()
+	client.OnGetProperty(AnyCTX, papi.GetPropertyRequest{PropertyID: "prp_0", ContractID: "ctr_0", GroupID: "grp_0"}, getProperty)
+	ExpectGetPropertyVersionHostnames(client, "prp_0", "grp_0", "ctr_0", 1, &v1Hostnames).Once()
+	ExpectGetPropertyVersionHostnames(client, "prp_0", "grp_0", "ctr_0", 2, &v2Hostnames).Maybe()
+	ExpectGetRuleTree(client, "prp_0", "grp_0", "ctr_0", 1, &rules, &ruleFormat).Once()
+
+	// The update creates version 3 from the imported version 1, not from the latest version 2
+	ExpectGetPropertyVersion(client, "prp_0", "grp_0", "ctr_0", 2, papi.VersionStatusInactive, papi.VersionStatusInactive).Once()
+	ExpectCreatePropertyVersion(client, "prp_0", "grp_0", "ctr_0", 1, 3).Once().Run(func(mock.Arguments) {
+		property.LatestVersion = 3
+	})
+	ExpectUpdatePropertyVersionHostnames(client, "prp_0", "grp_0", "ctr_0", 3, v3Hostnames).Once().Run(func(mock.Arguments) {
+		v3State = v3Hostnames
+	})
+	ExpectUpdateRuleTree(client, "prp_0", "grp_0", "ctr_0", 3, rules, ruleFormat).Once()
+	ExpectGetPropertyVersionHostnames(client, "prp_0", "grp_0", "ctr_0", 3, &v3State)
+	ExpectGetRuleTree(client, "prp_0", "grp_0", "ctr_0", 3, &rules, &ruleFormat)
+
+	cfg := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":        "test property",
+		"contract_id": "ctr_0",
+		"group_id":    "grp_0",
+		"product_id":  "prd_0",
+		"hostnames":   map[string]interface{}{"from.test.domain": "to3.test.domain"},
+	})
+
+	useClient(client, func() {
+		d := res.TestResourceData()
+		d.SetId("prp_0:v1")
+		imported, err := res.Importer.StateContext(ctx, d, meta)
+		require.NoError(t, err)
+		require.Len(t, imported, 1)
+
+		state, diags := res.RefreshWithoutUpgrade(ctx, imported[0].State(), meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, "1", state.Attributes["read_version"])
+		assert.Equal(t, "to.test.domain", state.Attributes["hostnames.from.test.domain"])
+		_, ok := state.Attributes["create_from_version"]
+		assert.False(t, ok)
+
+		diff, err := res.SimpleDiff(ctx, state, cfg, meta)
+		require.NoError(t, err)
+		_, ok = diff.Attributes["create_from_version"]
+		assert.False(t, ok, "the import must not plan a change of create_from_version")
+
+		state, diags = res.Apply(ctx, state, diff, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, "0", state.Attributes["read_version"])
+		assert.Equal(t, "3", state.Attributes["latest_version"])
+		assert.Equal(t, "to3.test.domain", state.Attributes["hostnames.from.test.domain"])
+
+		// The state follows the latest version, the configuration is applied
+		state, diags = res.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		diff, err = res.SimpleDiff(ctx, state, cfg, meta)
+		require.NoError(t, err)
+		// Only the computed attributes the configuration doesn't set are planned
+		if diff != nil {
+			for attr, attrDiff := range diff.Attributes {
+				assert.True(t, attrDiff.NewComputed, "unexpected change of %s: %#v", attr, attrDiff)
+			}
+		}
+	})
+
+	client.AssertExpectations(t)
+}
+
+func TestResPropertyCreateFromVersion(t *testing.T) {
+	ctx := context.Background()
+	res := testProvider.ResourcesMap["akamai_property"]
+	meta := testMeta(t)
+
+	property := papi.Property{
+		PropertyID:    "prp_0",
+		PropertyName:  "test property",
+		ContractID:    "ctr_0",
+		GroupID:       "grp_0",
+		ProductID:     "prd_0",
+		LatestVersion: 2,
+	}
+	hostnames := []papi.Hostname{{CnameType: "EDGE_HOSTNAME", CnameFrom: "from.test.domain", CnameTo: "to.test.domain"}}
+	updated := []papi.Hostname{{CnameType: "EDGE_HOSTNAME", CnameFrom: "from.test.domain", CnameTo: "to2.test.domain"}}
+	rules := papi.RulesUpdate{Rules: papi.Rules{Name: "default"}}
+	ruleFormat := "v2020-01-01"
+
+	client := &mockpapi{}
+	client.Test(T{t})
+	client.OnGetProperty(AnyCTX, papi.GetPropertyRequest{PropertyID: "prp_0", ContractID: "ctr_0", GroupID: "grp_0"},
+		func(context.Context, papi.GetPropertyRequest) (*papi.GetPropertyResponse, error) {
+			p := property
+			return &papi.GetPropertyResponse{Property: &p}, nil
+		})
+	ExpectGetPropertyVersionHostnames(client, "prp_0", "grp_0", "ctr_0", 2, &hostnames).Once()
+	ExpectGetRuleTree(client, "prp_0", "grp_0", "ctr_0", 2, &rules, &ruleFormat).Once()
+
+	// Setting create_from_version creates version 3 from version 1 with the configured hostnames and rules
+	ExpectGetPropertyVersion(client, "prp_0", "grp_0", "ctr_0", 2, papi.VersionStatusInactive, papi.VersionStatusInactive).Once()
+	ExpectCreatePropertyVersion(client, "prp_0", "grp_0", "ctr_0", 1, 3).Once().Run(func(mock.Arguments) {
+		property.LatestVersion = 3
+	})
+	ExpectUpdatePropertyVersionHostnames(client, "prp_0", "grp_0", "ctr_0", 3, hostnames).Once()
+	ExpectUpdateRuleTree(client, "prp_0", "grp_0", "ctr_0", 3, rules, ruleFormat).Once()
+
+	// The next change is applied to the editable latest version, not to a new version from version 1
+	ExpectGetPropertyVersion(client, "prp_0", "grp_0", "ctr_0", 3, papi.VersionStatusInactive, papi.VersionStatusInactive).Once()
+	ExpectUpdatePropertyVersionHostnames(client, "prp_0", "grp_0", "ctr_0", 3, updated).Once().Run(func(mock.Arguments) {
+		hostnames = updated
+	})
+	ExpectGetPropertyVersionHostnames(client, "prp_0", "grp_0", "ctr_0", 3, &hostnames)
+	ExpectGetRuleTree(client, "prp_0", "grp_0", "ctr_0", 3, &rules, &ruleFormat)
+
+	config := func(CnameTo string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                "test property",
+			"contract_id":         "ctr_0",
+			"group_id":            "grp_0",
+			"product_id":          "prd_0",
+			"create_from_version": 1,
+			"hostnames":           map[string]interface{}{"from.test.domain": CnameTo},
+		})
+	}
+
+	useClient(client, func() {
+		d := res.TestResourceData()
+		d.SetId("prp_0,ctr_0,grp_0")
+		imported, err := res.Importer.StateContext(ctx, d, meta)
+		require.NoError(t, err)
+		state, diags := res.RefreshWithoutUpgrade(ctx, imported[0].State(), meta)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		for _, CnameTo := range []string{"to.test.domain", "to2.test.domain"} {
+			diff, err := res.SimpleDiff(ctx, state, config(CnameTo), meta)
+			require.NoError(t, err)
+			require.NotNil(t, diff)
+			state, diags = res.Apply(ctx, state, diff, meta)
+			require.False(t, diags.HasError(), "%v", diags)
+			assert.Equal(t, "3", state.Attributes["latest_version"])
+			assert.Equal(t, CnameTo, state.Attributes["hostnames.from.test.domain"])
+		}
+	})
+
+	client.AssertExpectations(t)
+}
+
+func TestResPropertyNotes(t *testing.T) {
+	ctx := context.Background()
+	res := testProvider.ResourcesMap["akamai_property"]
+	meta := testMeta(t)
+
+	property := papi.Property{
+		PropertyID:    "prp_0",
+		PropertyName:  "test property",
+		ContractID:    "ctr_0",
+		GroupID:       "grp_0",
+		ProductID:     "prd_0",
+		LatestVersion: 2,
+	}
+	hostnames := []papi.Hostname{{CnameType: "EDGE_HOSTNAME", CnameFrom: "from.test.domain", CnameTo: "to.test.domain"}}
+	rules := papi.RulesUpdate{Rules: papi.Rules{Name: "default"}}
+	ruleFormat := "v2020-01-01"
+
+	client := &mockpapi{}
+	client.Test(T{t})
+	client.OnGetProperty(AnyCTX, papi.GetPropertyRequest{PropertyID: "prp_0", ContractID: "ctr_0", GroupID: "grp_0"},
+		func(context.Context, papi.GetPropertyRequest) (*papi.GetPropertyResponse, error) {
+			p := property
+			return &papi.GetPropertyResponse{Property: &p}, nil
+		})
+	ExpectGetPropertyVersionHostnames(client, "prp_0", "grp_0", "ctr_0", 2, &hostnames)
+	ExpectGetRuleTree(client, "prp_0", "grp_0", "ctr_0", 2, &rules, &ruleFormat)
+	ExpectGetPropertyVersion(client, "prp_0", "grp_0", "ctr_0", 2, papi.VersionStatusInactive, papi.VersionStatusInactive).Twice()
+
+	// The notes are set on the editable latest version, and removed from it when they are removed from the configuration
+	notes := &mockversionnotes{}
+	notes.Test(T{t})
+	ExpectUpdateVersionNotes(notes, "prp_0", 2, "release notes").Once()
+	ExpectUpdateVersionNotes(notes, "prp_0", 2, "").Once()
+
+	config := func(Notes string) *terraform.ResourceConfig {
+		raw := map[string]interface{}{
+			"name":        "test property",
+			"contract_id": "ctr_0",
+			"group_id":    "grp_0",
+			"product_id":  "prd_0",
+			"hostnames":   map[string]interface{}{"from.test.domain": "to.test.domain"},
+		}
+		if Notes != "" {
+			raw["version_notes"] = Notes
+		}
+		return terraform.NewResourceConfigRaw(raw)
+	}
+
+	useClient(client, func() {
+		useVersionNotesClient(notes, func() {
+			d := res.TestResourceData()
+			d.SetId("prp_0,ctr_0,grp_0")
+			imported, err := res.Importer.StateContext(ctx, d, meta)
+			require.NoError(t, err)
+			state, diags := res.RefreshWithoutUpgrade(ctx, imported[0].State(), meta)
+			require.False(t, diags.HasError(), "%v", diags)
+
+			for _, Notes := range []string{"release notes", ""} {
+				diff, err := res.SimpleDiff(ctx, state, config(Notes), meta)
+				require.NoError(t, err)
+				require.NotNil(t, diff)
+				state, diags = res.Apply(ctx, state, diff, meta)
+				require.False(t, diags.HasError(), "%v", diags)
+				assert.Equal(t, "2", state.Attributes["latest_version"])
+				assert.Equal(t, Notes, state.Attributes["version_notes"])
+			}
+		})
+	})
+
+	client.AssertExpectations(t)
+	notes.AssertExpectations(t)
+}
+
+func TestResPropertyRulesSchema(t *testing.T) {
+	ctx := context.Background()
+	meta := testMeta(t)
+	res := Provider().ResourcesMap["akamai_property"]
+
+	config := func(ProductID, RulesFile string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":        "test property",
+			"contract_id": "ctr_0",
+			"group_id":    "grp_0",
+			"product_id":  ProductID,
+			"rule_format": "v2020-03-04",
+			"rules":       loadFixtureString(RulesFile),
+		})
+	}
+
+	// The schema may be cached by an earlier run, the client is not asserted
+	schemas := &mockschemas{}
+	schemas.On("GetRuleSchema", mock.Anything, "prd_Schema_Test", "v2020-03-04").
+		Return(json.RawMessage(loadFixtureBytes("testdata/TestRuleSchema/schema.json")), nil)
+	schemas.On("GetRuleSchema", mock.Anything, "prd_Schema_Missing", "v2020-03-04").
+		Return(nil, errRuleSchemaNotFound)
+
+	useSchemasClient(schemas, func() {
+		t.Run("invalid rules fail the plan", func(t *testing.T) {
+			_, err := res.SimpleDiff(ctx, nil, config("prd_Schema_Test", "testdata/TestRuleSchema/rules_invalid.json"), meta)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), ErrRulesNotValid.Error()+" v2020-03-04:\n  /rules/behaviors/0/options/hostname: required option is missing")
+			assert.Contains(t, err.Error(), `/rules/behaviors/2/name: unknown behavior "gzipResponse"`)
+		})
+
+		t.Run("valid rules", func(t *testing.T) {
+			diff, err := res.SimpleDiff(ctx, nil, config("prd_Schema_Test", "testdata/TestRuleSchema/rules_valid.json"), meta)
+			require.NoError(t, err)
+			assert.NotNil(t, diff)
+		})
+
+		t.Run("rules not validated without a schema", func(t *testing.T) {
+			diff, err := res.SimpleDiff(ctx, nil, config("prd_Schema_Missing", "testdata/TestRuleSchema/rules_invalid.json"), meta)
+			require.NoError(t, err)
+			assert.NotNil(t, diff)
+		})
+	})
+}
@@ -0,0 +1,334 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+// PAPI Property Version
+//
+// Every attribute forces a new version, a version is never modified once created so it can be
+// activated and audited as is. The ID is the property ID and the version number like prp_123:v4
+//
+// https://developer.akamai.com/api/core_features/property_manager/v1.html#versionsgroup
+func resourcePropertyVersion() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePropertyVersionCreate,
+		ReadContext:   resourcePropertyVersionRead,
+
+		// NB: PAPI does not delete property versions, the version is only removed from the state
+		DeleteContext: schema.NoopContext,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePropertyVersionImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"property_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: tools.IsNotBlank,
+				StateFunc:        addPrefixToState("prp_"),
+			},
+			"contract_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: tools.IsNotBlank,
+				StateFunc:        addPrefixToState("ctr_"),
+			},
+			"group_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: tools.IsNotBlank,
+				StateFunc:        addPrefixToState("grp_"),
+			},
+			"create_from_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The version the new version is based on, defaults to the latest version",
+				// PAPI does not return the base version, an imported version keeps the configured one
+				DiffSuppressFunc: func(_, old, _ string, d *schema.ResourceData) bool {
+					return old == "" && d.Id() != ""
+				},
+			},
+			"rules": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsJSON,
+				Description:  "The rule tree of the version as JSON, defaults to the rules of the base version",
+				DiffSuppressFunc: func(_, old, new string, _ *schema.ResourceData) bool {
+					return compareRulesJSON(old, new)
+				},
+				StateFunc: func(v interface{}) string {
+					return compactJSON([]byte(v.(string)))
+				},
+			},
+			"rule_format": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The rule format of the version, defaults to the rule format of the base version",
+			},
+			"hostnames": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Mapping of edge hostname CNAMEs to other CNAMEs, defaults to the hostnames of the base version",
+			},
+			"version_notes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The notes of the version",
+			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version number, usable as the version of akamai_property_activation",
+			},
+			"etag": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"staging_status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"production_status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourcePropertyVersionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("PAPI", "resourcePropertyVersionCreate")
+	client := inst.Client(meta)
+	ctx = log.NewContext(ctx, logger)
+
+	Property := papi.Property{
+		PropertyID: tools.AddPrefix(d.Get("property_id").(string), "prp_"),
+		ContractID: tools.AddPrefix(d.Get("contract_id").(string), "ctr_"),
+		GroupID:    tools.AddPrefix(d.Get("group_id").(string), "grp_"),
+	}
+
+	FromVersion := d.Get("create_from_version").(int)
+	if FromVersion == 0 {
+		res, err := client.GetLatestVersion(ctx, papi.GetLatestVersionRequest{
+			PropertyID: Property.PropertyID,
+			ContractID: Property.ContractID,
+			GroupID:    Property.GroupID,
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		FromVersion = res.Version.PropertyVersion
+	}
+
+	Version, err := createPropertyVersion(ctx, client, Property, FromVersion)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("%w: %s", ErrVersionCreate, err))
+	}
+	Property.LatestVersion = Version
+
+	// Save minimum state BEFORE moving on
+	d.SetId(propertyVersionID(Property.PropertyID, Version))
+	if err := d.Set("create_from_version", FromVersion); err != nil {
+		return diag.Errorf("%v: %s", tools.ErrValueSet, err.Error())
+	}
+
+	if v, ok := d.GetOk("hostnames"); ok {
+		if err := updatePropertyHostnames(ctx, client, Property, mapToHostnames(v.(map[string]interface{}))); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+	}
+
+	RulesJSON := d.Get("rules").(string)
+	RuleFormat := d.Get("rule_format").(string)
+	if RulesJSON != "" || RuleFormat != "" {
+		var Rules papi.RulesUpdate
+		if RulesJSON != "" {
+			if err := json.Unmarshal([]byte(RulesJSON), &Rules); err != nil {
+				d.Partial(true)
+				return diag.Errorf("rules are not valid JSON: %s", err)
+			}
+		} else {
+			// only the rule format changes, the rules of the base version are saved in the new format
+			if Rules, _, _, _, err = fetchPropertyRules(ctx, client, Property); err != nil {
+				d.Partial(true)
+				return diag.FromErr(err)
+			}
+		}
+
+		ctx := ctx
+		if RuleFormat != "" {
+			h := http.Header{"Content-Type": []string{fmt.Sprintf("application/vnd.akamai.papirules.%s+json", RuleFormat)}}
+			ctx = session.ContextWithOptions(ctx, session.WithContextHeaders(h))
+		}
+		if err := updatePropertyRules(ctx, client, Property, Rules); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+	}
+
+	if Note := d.Get("version_notes").(string); Note != "" {
+		if err := updatePropertyVersionNote(ctx, inst.VersionNotesClient(meta), Property, Note); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourcePropertyVersionRead(ctx, d, m)
+}
+
+func resourcePropertyVersionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("PAPI", "resourcePropertyVersionRead")
+	client := inst.Client(meta)
+	ctx = log.NewContext(ctx, logger)
+
+	PropertyID, Version, err := parsePropertyVersionID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	Property := papi.Property{
+		PropertyID:    PropertyID,
+		ContractID:    tools.AddPrefix(d.Get("contract_id").(string), "ctr_"),
+		GroupID:       tools.AddPrefix(d.Get("group_id").(string), "grp_"),
+		LatestVersion: Version,
+	}
+
+	res, err := client.GetPropertyVersion(ctx, papi.GetPropertyVersionRequest{
+		PropertyID:      Property.PropertyID,
+		PropertyVersion: Version,
+		ContractID:      Property.ContractID,
+		GroupID:         Property.GroupID,
+	})
+	if err != nil {
+		var e *papi.Error
+		if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+			logger.Warnf("%s: %s, removing it from the state", ErrVersionNotFound, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	Hostnames, err := fetchPropertyHostnames(ctx, client, Property)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	Rules, RuleFormat, _, _, err := fetchPropertyRules(ctx, client, Property)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	RulesJSON, err := json.Marshal(Rules)
+	if err != nil {
+		return diag.Errorf("received rules that could not be rendered to JSON: %s", err)
+	}
+
+	attrs := map[string]interface{}{
+		"property_id":       Property.PropertyID,
+		"version":           Version,
+		"etag":              res.Version.Etag,
+		"staging_status":    string(res.Version.StagingStatus),
+		"production_status": string(res.Version.ProductionStatus),
+		"version_notes":     res.Version.Note,
+		"hostnames":         hostnamesToMap(Hostnames),
+		"rules":             string(RulesJSON),
+		"rule_format":       RuleFormat,
+	}
+	if err := rdSetAttrs(ctx, d, attrs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourcePropertyVersionImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	meta := akamai.Meta(m)
+	client := inst.Client(meta)
+	ctx = log.NewContext(ctx, meta.Log("PAPI", "resourcePropertyVersionImport"))
+
+	// The import ID is the version ID like prp_123:v4, optionally followed by the contract and group like
+	// prp_123:v4,ctr_1,grp_2. The contract and group are looked up when they are not given.
+	parts := strings.Split(d.Id(), ",")
+	if len(parts) != 1 && len(parts) != 3 {
+		return nil, fmt.Errorf("%w: import ID %q, expected prp_<id>:v<version>[,ctr_<id>,grp_<id>]", tools.ErrInvalidType, d.Id())
+	}
+	PropertyID, Version, err := parsePropertyVersionID(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var ContractID, GroupID string
+	if len(parts) == 3 {
+		ContractID = tools.AddPrefix(parts[1], "ctr_")
+		GroupID = tools.AddPrefix(parts[2], "grp_")
+	} else {
+		Property, err := fetchProperty(ctx, client, PropertyID, "", "")
+		if err != nil {
+			return nil, err
+		}
+		ContractID, GroupID = Property.ContractID, Property.GroupID
+	}
+
+	if err := rdSetAttrs(ctx, d, map[string]interface{}{
+		"property_id": PropertyID,
+		"contract_id": ContractID,
+		"group_id":    GroupID,
+	}); err != nil {
+		return nil, err
+	}
+
+	d.SetId(propertyVersionID(PropertyID, Version))
+	return []*schema.ResourceData{d}, nil
+}
+
+func propertyVersionID(PropertyID string, Version int) string {
+	return fmt.Sprintf("%s:v%d", PropertyID, Version)
+}
+
+// parsePropertyVersionID splits an ID like prp_123:v4 into the property ID and the version number
+func parsePropertyVersionID(id string) (string, int, error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 2 || parts[0] == "" {
+		return "", 0, fmt.Errorf("%w: property version ID %q, expected prp_<id>:v<version>", tools.ErrInvalidType, id)
+	}
+	Version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil || Version < 1 {
+		return "", 0, fmt.Errorf("%w: property version ID %q, expected prp_<id>:v<version>", tools.ErrInvalidType, id)
+	}
+
+	return tools.AddPrefix(parts[0], "prp_"), Version, nil
+}
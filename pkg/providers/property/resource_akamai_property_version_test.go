@@ -0,0 +1,177 @@
+package property
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResPropertyVersion(t *testing.T) {
+	t.Run("version created from a given version", func(t *testing.T) {
+		client := &mockpapi{}
+		Hostnames := []papi.Hostname{{CnameType: "EDGE_HOSTNAME", CnameFrom: "www.example.com", CnameTo: "www.example.com.edgesuite.net"}}
+		Rules := papi.RulesUpdate{Rules: papi.Rules{Name: "default"}}
+		RuleFormat := "v2020-03-04"
+
+		ExpectCreatePropertyVersion(client, "prp_1", "grp_1", "ctr_1", 1, 2).Once()
+		ExpectUpdatePropertyVersionHostnames(client, "prp_1", "grp_1", "ctr_1", 2, Hostnames).Once()
+		ExpectGetPropertyVersion(client, "prp_1", "grp_1", "ctr_1", 2, papi.VersionStatusInactive, papi.VersionStatusInactive)
+		ExpectGetPropertyVersionHostnames(client, "prp_1", "grp_1", "ctr_1", 2, &Hostnames)
+		ExpectGetRuleTree(client, "prp_1", "grp_1", "ctr_1", 2, &Rules, &RuleFormat)
+		ExpectGetProperty(client, "prp_1", "", "", &papi.Property{PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_1", LatestVersion: 2}).Once()
+
+		useClient(client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{
+					{
+						Config: loadFixtureString("testdata/TestResPropertyVersion/from_version.tf"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("akamai_property_version.test", "id", "prp_1:v2"),
+							resource.TestCheckResourceAttr("akamai_property_version.test", "version", "2"),
+							resource.TestCheckResourceAttr("akamai_property_version.test", "create_from_version", "1"),
+							resource.TestCheckResourceAttr("akamai_property_version.test", "rule_format", RuleFormat),
+							resource.TestCheckResourceAttr("akamai_property_version.test", "staging_status", "INACTIVE"),
+						),
+					},
+					{
+						ImportState:             true,
+						ImportStateVerify:       true,
+						ImportStateId:           "prp_1:v2",
+						ImportStateVerifyIgnore: []string{"create_from_version"},
+						ResourceName:            "akamai_property_version.test",
+						Config:                  loadFixtureString("testdata/TestResPropertyVersion/from_version.tf"),
+					},
+				},
+			})
+		})
+
+		client.AssertExpectations(t)
+	})
+}
+
+// The imported version is planned against the configuration through the plugin SDK, an import step of
+// resource.UnitTest doesn't plan
+func TestResPropertyVersionImport(t *testing.T) {
+	ctx := context.Background()
+	res := testProvider.ResourcesMap["akamai_property_version"]
+	meta := testMeta(t)
+
+	client := &mockpapi{}
+	client.Test(T{t})
+	Hostnames := []papi.Hostname{{CnameType: "EDGE_HOSTNAME", CnameFrom: "www.example.com", CnameTo: "www.example.com.edgesuite.net"}}
+	Rules := papi.RulesUpdate{Rules: papi.Rules{Name: "default"}}
+	RuleFormat := "v2020-03-04"
+	ExpectGetProperty(client, "prp_1", "", "", &papi.Property{PropertyID: "prp_1", ContractID: "ctr_1", GroupID: "grp_1", LatestVersion: 2}).Once()
+	ExpectGetPropertyVersion(client, "prp_1", "grp_1", "ctr_1", 2, papi.VersionStatusInactive, papi.VersionStatusInactive).Once()
+	ExpectGetPropertyVersionHostnames(client, "prp_1", "grp_1", "ctr_1", 2, &Hostnames).Once()
+	ExpectGetRuleTree(client, "prp_1", "grp_1", "ctr_1", 2, &Rules, &RuleFormat).Once()
+
+	useClient(client, func() {
+		d := res.TestResourceData()
+		d.SetId("prp_1:v2")
+		imported, err := res.Importer.StateContext(ctx, d, meta)
+		require.NoError(t, err)
+		require.Len(t, imported, 1)
+		assert.Equal(t, "prp_1:v2", imported[0].Id())
+
+		state, diags := res.RefreshWithoutUpgrade(ctx, imported[0].State(), meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, "ctr_1", state.Attributes["contract_id"])
+		assert.Equal(t, "grp_1", state.Attributes["group_id"])
+		assert.Equal(t, "www.example.com.edgesuite.net", state.Attributes["hostnames.www.example.com"])
+
+		// The base version is not known after the import, the configured one does not replace the version
+		diff, err := res.SimpleDiff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"property_id":         "prp_1",
+			"contract_id":         "ctr_1",
+			"group_id":            "grp_1",
+			"create_from_version": 1,
+			"hostnames":           map[string]interface{}{"www.example.com": "www.example.com.edgesuite.net"},
+		}), meta)
+		require.NoError(t, err)
+		assert.False(t, diff != nil && diff.RequiresNew(), "unexpected replacement: %v", diff)
+	})
+
+	client.AssertExpectations(t)
+}
+
+func TestResPropertyVersionNotes(t *testing.T) {
+	ctx := context.Background()
+	res := testProvider.ResourcesMap["akamai_property_version"]
+	meta := testMeta(t)
+
+	client := &mockpapi{}
+	client.Test(T{t})
+	Hostnames := []papi.Hostname{{CnameType: "EDGE_HOSTNAME", CnameFrom: "www.example.com", CnameTo: "www.example.com.edgesuite.net"}}
+	Rules := papi.RulesUpdate{Rules: papi.Rules{Name: "default"}}
+	RuleFormat := "v2020-03-04"
+	ExpectCreatePropertyVersion(client, "prp_1", "grp_1", "ctr_1", 1, 2).Once()
+	ExpectUpdatePropertyVersionHostnames(client, "prp_1", "grp_1", "ctr_1", 2, Hostnames).Once()
+	Version := ExpectGetPropertyVersion(client, "prp_1", "grp_1", "ctr_1", 2, papi.VersionStatusInactive, papi.VersionStatusInactive)
+	ExpectGetPropertyVersionHostnames(client, "prp_1", "grp_1", "ctr_1", 2, &Hostnames)
+	ExpectGetRuleTree(client, "prp_1", "grp_1", "ctr_1", 2, &Rules, &RuleFormat)
+
+	// The read version holds the notes once they are set
+	notes := &mockversionnotes{}
+	notes.Test(T{t})
+	ExpectUpdateVersionNotes(notes, "prp_1", 2, "release notes").Once().Run(func(mock.Arguments) {
+		Version.ReturnArguments.Get(0).(*papi.GetPropertyVersionsResponse).Version.Note = "release notes"
+	})
+
+	useClient(client, func() {
+		useVersionNotesClient(notes, func() {
+			diff, err := res.SimpleDiff(ctx, nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"property_id":         "prp_1",
+				"contract_id":         "ctr_1",
+				"group_id":            "grp_1",
+				"create_from_version": 1,
+				"hostnames":           map[string]interface{}{"www.example.com": "www.example.com.edgesuite.net"},
+				"version_notes":       "release notes",
+			}), meta)
+			require.NoError(t, err)
+			state, diags := res.Apply(ctx, nil, diff, meta)
+			require.False(t, diags.HasError(), "%v", diags)
+			assert.Equal(t, "prp_1:v2", state.ID)
+			assert.Equal(t, "release notes", state.Attributes["version_notes"])
+		})
+	})
+
+	client.AssertExpectations(t)
+	notes.AssertExpectations(t)
+}
+
+func TestParsePropertyVersionID(t *testing.T) {
+	tests := map[string]struct {
+		id         string
+		propertyID string
+		version    int
+		withError  bool
+	}{
+		"with prefix":      {id: "prp_123:v4", propertyID: "prp_123", version: 4},
+		"without prefix":   {id: "123:4", propertyID: "prp_123", version: 4},
+		"missing version":  {id: "prp_123", withError: true},
+		"invalid version":  {id: "prp_123:latest", withError: true},
+		"zero version":     {id: "prp_123:v0", withError: true},
+		"missing property": {id: ":v1", withError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			propertyID, version, err := parsePropertyVersionID(test.id)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.propertyID, propertyID)
+			assert.Equal(t, test.version, version)
+		})
+	}
+}
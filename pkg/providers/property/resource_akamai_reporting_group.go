@@ -0,0 +1,216 @@
+package property
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+// CPRG Reporting Group
+//
+// A reporting group aggregates the traffic reports of CP codes of a contract. The group_id is the access group, only
+// the users of that group see the reporting group. The ID is the reporting group number.
+//
+// https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#reportinggroup
+func resourceReportingGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceReportingGroupCreate,
+		ReadContext:   resourceReportingGroupRead,
+		UpdateContext: resourceReportingGroupUpdate,
+		DeleteContext: resourceReportingGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: akamaiReportingGroupSchema,
+	}
+}
+
+var akamaiReportingGroupSchema = map[string]*schema.Schema{
+	"name": {
+		Type:             schema.TypeString,
+		Required:         true,
+		ValidateDiagFunc: tools.IsNotBlank,
+	},
+	"contract_id": {
+		Type:             schema.TypeString,
+		Required:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: tools.IsNotBlank,
+		StateFunc:        addPrefixToState("ctr_"),
+	},
+	"group_id": {
+		Type:             schema.TypeString,
+		Required:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: tools.IsNotBlank,
+		StateFunc:        addPrefixToState("grp_"),
+		Description:      "The access group of the reporting group",
+	},
+	"cp_codes": {
+		Type:     schema.TypeSet,
+		Required: true,
+		MinItems: 1,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringMatch(regexp.MustCompile(`^cpc_\d+$`), "must be a CP code ID like cpc_123"),
+		},
+		Description: "The IDs of the CP codes of the reporting group, like the id of akamai_cp_code",
+	},
+}
+
+func resourceReportingGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("CPRG", "resourceReportingGroupCreate")
+	client := inst.CPRGClient(meta)
+	ctx = log.NewContext(ctx, logger)
+
+	group, err := expandReportingGroup(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	logger.Debugf("Creating reporting group %q", group.ReportingGroupName)
+	created, err := client.CreateReportingGroup(ctx, *group)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.Itoa(created.ReportingGroupID))
+
+	return resourceReportingGroupRead(ctx, d, m)
+}
+
+func resourceReportingGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("CPRG", "resourceReportingGroupRead")
+	client := inst.CPRGClient(meta)
+	ctx = log.NewContext(ctx, logger)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("%w: %q", ErrReportingGroupID, d.Id()))
+	}
+
+	group, err := client.GetReportingGroup(ctx, id)
+	if err != nil {
+		var e *apiError
+		if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+			logger.Warnf("reporting group %d not found, removing it from the state", id)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if err := rdSetAttrs(ctx, d, flattenReportingGroup(group)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceReportingGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("CPRG", "resourceReportingGroupUpdate")
+	client := inst.CPRGClient(meta)
+	ctx = log.NewContext(ctx, logger)
+
+	if !d.HasChanges("name", "cp_codes") {
+		return resourceReportingGroupRead(ctx, d, m)
+	}
+
+	group, err := expandReportingGroup(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if group.ReportingGroupID, err = strconv.Atoi(d.Id()); err != nil {
+		return diag.FromErr(fmt.Errorf("%w: %q", ErrReportingGroupID, d.Id()))
+	}
+
+	logger.Debugf("Updating reporting group %d", group.ReportingGroupID)
+	if _, err := client.UpdateReportingGroup(ctx, *group); err != nil {
+		d.Partial(true)
+		return diag.FromErr(err)
+	}
+
+	return resourceReportingGroupRead(ctx, d, m)
+}
+
+func resourceReportingGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := akamai.Meta(m)
+	logger := meta.Log("CPRG", "resourceReportingGroupDelete")
+	client := inst.CPRGClient(meta)
+	ctx = log.NewContext(ctx, logger)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("%w: %q", ErrReportingGroupID, d.Id()))
+	}
+
+	if err := client.DeleteReportingGroup(ctx, id); err != nil {
+		var e *apiError
+		if !errors.As(err, &e) || e.StatusCode != http.StatusNotFound {
+			return diag.FromErr(err)
+		}
+		logger.Warnf("reporting group %d is already deleted", id)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// expandReportingGroup returns the reporting group of the resource, CPRG takes the IDs without their prefixes
+func expandReportingGroup(d *schema.ResourceData) (*cprgReportingGroup, error) {
+	contractID := strings.TrimPrefix(d.Get("contract_id").(string), "ctr_")
+	groupID, err := tools.GetIntID(d.Get("group_id").(string), "grp_")
+	if err != nil {
+		return nil, fmt.Errorf("%w: group %q", ErrReportingGroupID, d.Get("group_id"))
+	}
+
+	contract := cprgReportingGroupContract{ContractID: contractID}
+	for _, cpCode := range d.Get("cp_codes").(*schema.Set).List() {
+		cpCodeID, err := tools.GetIntID(cpCode.(string), "cpc_")
+		if err != nil {
+			return nil, fmt.Errorf("%w: CP code %q", ErrReportingGroupID, cpCode)
+		}
+		contract.CPCodes = append(contract.CPCodes, cprgReportingGroupCPCode{CPCodeID: cpCodeID})
+	}
+	sort.Slice(contract.CPCodes, func(i, j int) bool {
+		return contract.CPCodes[i].CPCodeID < contract.CPCodes[j].CPCodeID
+	})
+
+	return &cprgReportingGroup{
+		ReportingGroupName: d.Get("name").(string),
+		Contracts:          []cprgReportingGroupContract{contract},
+		AccessGroup:        cprgAccessGroup{GroupID: groupID, ContractID: contractID},
+	}, nil
+}
+
+// flattenReportingGroup returns the resource attributes of the reporting group
+func flattenReportingGroup(group *cprgReportingGroup) map[string]interface{} {
+	var cpCodes []interface{}
+	for _, contract := range group.Contracts {
+		for _, cpCode := range contract.CPCodes {
+			cpCodes = append(cpCodes, fmt.Sprintf("cpc_%d", cpCode.CPCodeID))
+		}
+	}
+
+	return map[string]interface{}{
+		"name":        group.ReportingGroupName,
+		"contract_id": tools.AddPrefix(group.AccessGroup.ContractID, "ctr_"),
+		"group_id":    fmt.Sprintf("grp_%d", group.AccessGroup.GroupID),
+		"cp_codes":    cpCodes,
+	}
+}
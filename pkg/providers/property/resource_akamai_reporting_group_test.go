@@ -0,0 +1,64 @@
+package property
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResReportingGroup(t *testing.T) {
+	t.Run("lifecycle", func(t *testing.T) {
+		client := &mockcprg{}
+		defer client.AssertExpectations(t)
+
+		group := func(name string, cpCodes ...int) cprgReportingGroup {
+			contract := cprgReportingGroupContract{ContractID: "1"}
+			for _, id := range cpCodes {
+				contract.CPCodes = append(contract.CPCodes, cprgReportingGroupCPCode{CPCodeID: id})
+			}
+			return cprgReportingGroup{
+				ReportingGroupName: name,
+				Contracts:          []cprgReportingGroupContract{contract},
+				AccessGroup:        cprgAccessGroup{GroupID: 2, ContractID: "1"},
+			}
+		}
+
+		// Contains the reporting group known to mock CPRG
+		current := group("reports", 1)
+		current.ReportingGroupID = 10
+
+		client.On("CreateReportingGroup", AnyCTX, group("reports", 1)).Return(&current, nil).Once()
+		client.On("GetReportingGroup", AnyCTX, 10).Return(&current, nil)
+		updated := group("all reports", 1, 2)
+		updated.ReportingGroupID = 10
+		client.On("UpdateReportingGroup", AnyCTX, updated).Run(func(mock.Arguments) {
+			current = updated
+		}).Return(&updated, nil).Once()
+		client.On("DeleteReportingGroup", AnyCTX, 10).Return(nil).Once()
+
+		useCPRGClient(client, func() {
+			resource.UnitTest(t, resource.TestCase{
+				Providers: testAccProviders,
+				Steps: []resource.TestStep{
+					{
+						Config: loadFixtureString("testdata/TestResReportingGroup/lifecycle_step0.tf"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("akamai_reporting_group.test", "id", "10"),
+							resource.TestCheckResourceAttr("akamai_reporting_group.test", "name", "reports"),
+							resource.TestCheckResourceAttr("akamai_reporting_group.test", "cp_codes.#", "1"),
+						),
+					},
+					{
+						Config: loadFixtureString("testdata/TestResReportingGroup/lifecycle_step1.tf"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("akamai_reporting_group.test", "id", "10"),
+							resource.TestCheckResourceAttr("akamai_reporting_group.test", "name", "all reports"),
+							resource.TestCheckResourceAttr("akamai_reporting_group.test", "cp_codes.#", "2"),
+						),
+					},
+				},
+			})
+		})
+	})
+}
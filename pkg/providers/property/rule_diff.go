@@ -0,0 +1,237 @@
+package property
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+)
+
+// ruleChanges returns the changes between two rule trees given as JSON, one line per change
+// Rules are matched by name under their parent, behaviors and criteria by name within their rule
+func ruleChanges(old, new string) ([]string, error) {
+	newRules, err := decodeRuleTree(new)
+	if err != nil {
+		return nil, fmt.Errorf("new rules: %w", err)
+	}
+	if old == "" {
+		return []string{fmt.Sprintf("+ rule %q", newRules.Name)}, nil
+	}
+	oldRules, err := decodeRuleTree(old)
+	if err != nil {
+		return nil, fmt.Errorf("old rules: %w", err)
+	}
+
+	var changes []string
+	diffRules(newRules.Name, &oldRules, &newRules, &changes)
+	return changes, nil
+}
+
+func decodeRuleTree(data string) (papi.Rules, error) {
+	var rules papi.RulesUpdate
+	dec := json.NewDecoder(bytes.NewReader([]byte(data)))
+	dec.UseNumber()
+	if err := dec.Decode(&rules); err != nil {
+		return papi.Rules{}, err
+	}
+	return rules.Rules, nil
+}
+
+func diffRules(path string, old, new *papi.Rules, changes *[]string) {
+	change := func(format string, args ...interface{}) {
+		*changes = append(*changes, fmt.Sprintf("~ rule %q: ", path)+fmt.Sprintf(format, args...))
+	}
+
+	if old.Name != new.Name {
+		change("name %q -> %q", old.Name, new.Name)
+	}
+	if old.Comment != new.Comment {
+		change("comment %q -> %q", old.Comment, new.Comment)
+	}
+	if criteriaMustSatisfy(old) != criteriaMustSatisfy(new) {
+		change("criteria_must_satisfy %q -> %q", criteriaMustSatisfy(old), criteriaMustSatisfy(new))
+	}
+	if old.Options.IsSecure != new.Options.IsSecure {
+		change("is_secure %t -> %t", old.Options.IsSecure, new.Options.IsSecure)
+	}
+	if old.AdvancedOverride != new.AdvancedOverride {
+		change("advanced override changed")
+	}
+	if !reflect.DeepEqual(old.CustomOverride, new.CustomOverride) {
+		change("custom override changed")
+	}
+
+	diffBehaviors(path, "criterion", old.Criteria, new.Criteria, changes)
+	diffBehaviors(path, "behavior", old.Behaviors, new.Behaviors, changes)
+	diffVariables(path, old.Variables, new.Variables, changes)
+
+	oldChildren := make(map[string]*papi.Rules, len(old.Children))
+	oldOrder := ruleKeys(old.Children)
+	for i, key := range oldOrder {
+		oldChildren[key] = &old.Children[i]
+	}
+	newOrder := ruleKeys(new.Children)
+	newChildren := make(map[string]bool, len(newOrder))
+	for _, key := range newOrder {
+		newChildren[key] = true
+	}
+
+	var oldKept, newKept []string
+	for _, key := range oldOrder {
+		if !newChildren[key] {
+			*changes = append(*changes, fmt.Sprintf("- rule %q", path+"/"+key))
+			continue
+		}
+		oldKept = append(oldKept, key)
+	}
+	for i, key := range newOrder {
+		oldChild, ok := oldChildren[key]
+		if !ok {
+			*changes = append(*changes, fmt.Sprintf("+ rule %q", path+"/"+key))
+			continue
+		}
+		newKept = append(newKept, key)
+		diffRules(path+"/"+key, oldChild, &new.Children[i], changes)
+	}
+	if !reflect.DeepEqual(oldKept, newKept) {
+		change("child rules reordered %s -> %s", compactValue(oldKept), compactValue(newKept))
+	}
+}
+
+// criteriaMustSatisfy returns the criteria match of the rule, PAPI omits the default "all"
+func criteriaMustSatisfy(rule *papi.Rules) papi.RuleCriteriaMustSatisfy {
+	if rule.CriteriaMustSatisfy == "" {
+		return papi.RuleCriteriaMustSatisfyAll
+	}
+	return rule.CriteriaMustSatisfy
+}
+
+// ruleKeys returns the names of the rules, a repeated name gets the number of its occurrence like "Images #2"
+func ruleKeys(rules []papi.Rules) []string {
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, r.Name)
+	}
+	return occurrenceKeys(names)
+}
+
+func occurrenceKeys(names []string) []string {
+	seen := make(map[string]int, len(names))
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s #%d", name, seen[name])
+		}
+		keys = append(keys, name)
+	}
+	return keys
+}
+
+func diffBehaviors(path, kind string, old, new []papi.RuleBehavior, changes *[]string) {
+	names := func(behaviors []papi.RuleBehavior) []string {
+		list := make([]string, 0, len(behaviors))
+		for _, b := range behaviors {
+			list = append(list, b.Name)
+		}
+		return occurrenceKeys(list)
+	}
+
+	oldKeys := names(old)
+	oldBehaviors := make(map[string]papi.RuleBehavior, len(old))
+	for i, key := range oldKeys {
+		oldBehaviors[key] = old[i]
+	}
+	newKeys := names(new)
+	newBehaviors := make(map[string]papi.RuleBehavior, len(new))
+	for i, key := range newKeys {
+		newBehaviors[key] = new[i]
+	}
+
+	for _, key := range oldKeys {
+		if _, ok := newBehaviors[key]; !ok {
+			*changes = append(*changes, fmt.Sprintf("- rule %q: %s %q %s", path, kind, key, compactValue(oldBehaviors[key].Options)))
+		}
+	}
+	for _, key := range newKeys {
+		oldBehavior, ok := oldBehaviors[key]
+		if !ok {
+			*changes = append(*changes, fmt.Sprintf("+ rule %q: %s %q %s", path, kind, key, compactValue(newBehaviors[key].Options)))
+			continue
+		}
+
+		oldOptions, newOptions := oldBehavior.Options, newBehaviors[key].Options
+		for _, name := range sortedOptionNames(oldOptions, newOptions) {
+			oldValue, inOld := oldOptions[name]
+			newValue, inNew := newOptions[name]
+			prefix := fmt.Sprintf("rule %q: %s %q option %q", path, kind, key, name)
+			switch {
+			case !inNew:
+				*changes = append(*changes, fmt.Sprintf("- %s: %s", prefix, compactValue(oldValue)))
+			case !inOld:
+				*changes = append(*changes, fmt.Sprintf("+ %s: %s", prefix, compactValue(newValue)))
+			case compactValue(oldValue) != compactValue(newValue):
+				*changes = append(*changes, fmt.Sprintf("~ %s: %s -> %s", prefix, compactValue(oldValue), compactValue(newValue)))
+			}
+		}
+	}
+}
+
+func sortedOptionNames(old, new papi.RuleOptionsMap) []string {
+	all := make(map[string]interface{}, len(old)+len(new))
+	for name := range old {
+		all[name] = nil
+	}
+	for name := range new {
+		all[name] = nil
+	}
+	return sortedKeys(all)
+}
+
+func diffVariables(path string, old, new []papi.RuleVariable, changes *[]string) {
+	oldVariables := make(map[string]papi.RuleVariable, len(old))
+	for _, v := range old {
+		oldVariables[v.Name] = v
+	}
+	newVariables := make(map[string]bool, len(new))
+	for _, v := range new {
+		newVariables[v.Name] = true
+	}
+
+	for _, v := range old {
+		if !newVariables[v.Name] {
+			*changes = append(*changes, fmt.Sprintf("- rule %q: variable %q", path, v.Name))
+		}
+	}
+	for _, v := range new {
+		oldVariable, ok := oldVariables[v.Name]
+		if !ok {
+			*changes = append(*changes, fmt.Sprintf("+ rule %q: variable %q", path, v.Name))
+			continue
+		}
+
+		var fields []string
+		switch {
+		case oldVariable.Value == v.Value:
+		case oldVariable.Sensitive || v.Sensitive:
+			fields = append(fields, "value changed")
+		default:
+			fields = append(fields, fmt.Sprintf("value %q -> %q", oldVariable.Value, v.Value))
+		}
+		if oldVariable.Description != v.Description {
+			fields = append(fields, fmt.Sprintf("description %q -> %q", oldVariable.Description, v.Description))
+		}
+		if oldVariable.Hidden != v.Hidden {
+			fields = append(fields, fmt.Sprintf("hidden %t -> %t", oldVariable.Hidden, v.Hidden))
+		}
+		if oldVariable.Sensitive != v.Sensitive {
+			fields = append(fields, fmt.Sprintf("sensitive %t -> %t", oldVariable.Sensitive, v.Sensitive))
+		}
+		if len(fields) > 0 {
+			*changes = append(*changes, fmt.Sprintf("~ rule %q: variable %q %s", path, v.Name, strings.Join(fields, ", ")))
+		}
+	}
+}
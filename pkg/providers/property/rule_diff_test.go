@@ -0,0 +1,81 @@
+package property
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleChanges(t *testing.T) {
+	base := `{"rules":{"name":"default","behaviors":[
+		{"name":"origin","options":{"hostname":"origin.example.com","httpPort":80}},
+		{"name":"cpCode","options":{"value":{"id":1}}}],
+		"variables":[{"name":"PMUSER_KEY","value":"a","sensitive":true,"hidden":true}],
+		"children":[
+			{"name":"Static","criteria":[{"name":"path","options":{"values":["/static/*"]}}],"behaviors":[{"name":"caching","options":{"ttl":"1d"}}]},
+			{"name":"Images","behaviors":[{"name":"imageManager","options":{"enabled":true}}]}]}}`
+
+	tests := map[string]struct {
+		old, new string
+		expected []string
+	}{
+		"same rules in another order": {
+			old: base,
+			new: `{"rules":{"name":"default","behaviors":[
+				{"name":"cpCode","options":{"value":{"id":1}}},
+				{"name":"origin","options":{"httpPort":80,"hostname":"origin.example.com"}}],
+				"criteriaMustSatisfy":"all",
+				"variables":[{"name":"PMUSER_KEY","value":"a","sensitive":true,"hidden":true}],
+				"children":[
+					{"name":"Static","criteria":[{"name":"path","options":{"values":["/static/*"]}}],"behaviors":[{"name":"caching","options":{"ttl":"1d"}}]},
+					{"name":"Images","behaviors":[{"name":"imageManager","options":{"enabled":true}}]}]}}`,
+		},
+		"changed options, behaviors and rules": {
+			old: base,
+			new: `{"rules":{"name":"default","behaviors":[
+				{"name":"origin","options":{"hostname":"origin.example.com","httpPort":8080,"compress":true}},
+				{"name":"gzipResponse","options":{"behavior":"ALWAYS"}}],
+				"variables":[{"name":"PMUSER_KEY","value":"b","sensitive":true,"hidden":true}],
+				"children":[
+					{"name":"Static","criteriaMustSatisfy":"any","criteria":[{"name":"path","options":{"values":["/static/*","/assets/*"]}}],"behaviors":[{"name":"caching","options":{"ttl":"1d"}}]},
+					{"name":"Fonts","behaviors":[]}]}}`,
+			expected: []string{
+				`- rule "default": behavior "cpCode" {"value":{"id":1}}`,
+				`+ rule "default": behavior "origin" option "compress": true`,
+				`~ rule "default": behavior "origin" option "httpPort": 80 -> 8080`,
+				`+ rule "default": behavior "gzipResponse" {"behavior":"ALWAYS"}`,
+				`~ rule "default": variable "PMUSER_KEY" value changed`,
+				`- rule "default/Images"`,
+				`~ rule "default/Static": criteria_must_satisfy "all" -> "any"`,
+				`~ rule "default/Static": criterion "path" option "values": ["/static/*"] -> ["/static/*","/assets/*"]`,
+				`+ rule "default/Fonts"`,
+			},
+		},
+		"reordered child rules": {
+			old: `{"rules":{"name":"default","children":[{"name":"A"},{"name":"B"},{"name":"B"}]}}`,
+			new: `{"rules":{"name":"default","children":[{"name":"B"},{"name":"A"},{"name":"B","comment":"second"}]}}`,
+			expected: []string{
+				`~ rule "default/B #2": comment "" -> "second"`,
+				`~ rule "default": child rules reordered ["A","B","B #2"] -> ["B","A","B #2"]`,
+			},
+		},
+		"new rule tree": {
+			new:      `{"rules":{"name":"default"}}`,
+			expected: []string{`+ rule "default"`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			changes, err := ruleChanges(test.old, test.new)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, changes)
+		})
+	}
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := ruleChanges(base, `{"rules":`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,446 @@
+package property
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+)
+
+type (
+	// ruleSchema validates rule trees against a PAPI rule format JSON schema
+	// It supports the subset of JSON schema used by the rule format schemas, behaviors and criteria are
+	// looked up in the definitions catalog and their options validated against the catalog entry
+	ruleSchema struct {
+		root      map[string]interface{}
+		behaviors map[string]interface{}
+		criteria  map[string]interface{}
+	}
+
+	// ruleSchemaError is a rule tree error located with a JSON pointer
+	ruleSchemaError struct {
+		Pointer string
+		Message string
+	}
+)
+
+var (
+	// ErrRuleSchema is returned when the rule format schema cannot be loaded
+	ErrRuleSchema = errors.New("rule format schema")
+
+	// ErrRulesNotValid is returned when the rules do not match the rule format schema
+	ErrRulesNotValid = errors.New("rules do not match the rule format schema")
+
+	// errRuleSchemaNotFound is returned when PAPI has no schema for the product and rule format
+	errRuleSchemaNotFound = errors.New("rule format schema not found")
+)
+
+// RuleSchemaCacheTTL is how long the schema of a frozen rule format stays cached, it does not change once published
+var RuleSchemaCacheTTL = 24 * time.Hour
+
+// maxRuleSchemaRefs bounds the $ref resolutions of a single value, as schemas may be recursive
+const maxRuleSchemaRefs = 32
+
+func (e ruleSchemaError) String() string {
+	return fmt.Sprintf("%s: %s", e.Pointer, e.Message)
+}
+
+// parseRuleSchema loads a rule format schema
+func parseRuleSchema(data []byte) (*ruleSchema, error) {
+	root, ok := decodeJSONNumbers(data).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: not a JSON object", ErrRuleSchema)
+	}
+
+	definitions, _ := root["definitions"].(map[string]interface{})
+	catalog, _ := definitions["catalog"].(map[string]interface{})
+	behaviors, _ := catalog["behaviors"].(map[string]interface{})
+	criteria, _ := catalog["criteria"].(map[string]interface{})
+	if behaviors == nil || criteria == nil {
+		return nil, fmt.Errorf("%w: definitions.catalog has no behaviors or criteria", ErrRuleSchema)
+	}
+
+	return &ruleSchema{
+		root:      root,
+		behaviors: behaviors,
+		criteria:  criteria,
+	}, nil
+}
+
+// Validate returns the errors of the rule tree, given as the JSON of the rules attribute
+func (s *ruleSchema) Validate(rulesJSON []byte) ([]ruleSchemaError, error) {
+	doc, ok := decodeJSONNumbers(rulesJSON).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("rules are not a JSON object")
+	}
+
+	var errs []ruleSchemaError
+	rules, ok := doc["rules"]
+	if !ok {
+		return append(errs, ruleSchemaError{"/rules", "the rules object is missing"}), nil
+	}
+	s.validateRule(rules, "/rules", &errs)
+
+	return errs, nil
+}
+
+func (s *ruleSchema) validateRule(v interface{}, ptr string, errs *[]ruleSchemaError) {
+	rule, ok := v.(map[string]interface{})
+	if !ok {
+		*errs = append(*errs, ruleSchemaError{ptr, fmt.Sprintf("expected a rule object, got %s", jsonType(v))})
+		return
+	}
+	if name, _ := rule["name"].(string); name == "" {
+		*errs = append(*errs, ruleSchemaError{ptr + "/name", "the rule name is required"})
+	}
+
+	s.validateBehaviors(rule["behaviors"], ptr+"/behaviors", s.behaviors, "behavior", errs)
+	s.validateBehaviors(rule["criteria"], ptr+"/criteria", s.criteria, "criterion", errs)
+
+	if children, ok := rule["children"]; ok && children != nil {
+		list, ok := children.([]interface{})
+		if !ok {
+			*errs = append(*errs, ruleSchemaError{ptr + "/children", fmt.Sprintf("expected array, got %s", jsonType(children))})
+			return
+		}
+		for i, child := range list {
+			s.validateRule(child, fmt.Sprintf("%s/children/%d", ptr, i), errs)
+		}
+	}
+}
+
+func (s *ruleSchema) validateBehaviors(v interface{}, ptr string, catalog map[string]interface{}, kind string, errs *[]ruleSchemaError) {
+	if v == nil {
+		return
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		*errs = append(*errs, ruleSchemaError{ptr, fmt.Sprintf("expected array, got %s", jsonType(v))})
+		return
+	}
+
+	for i, item := range list {
+		itemPtr := fmt.Sprintf("%s/%d", ptr, i)
+		behavior, ok := item.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, ruleSchemaError{itemPtr, fmt.Sprintf("expected a %s object, got %s", kind, jsonType(item))})
+			continue
+		}
+		name, _ := behavior["name"].(string)
+		def, ok := catalog[name].(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, ruleSchemaError{itemPtr + "/name", fmt.Sprintf("unknown %s %q", kind, name)})
+			continue
+		}
+
+		properties, _ := def["properties"].(map[string]interface{})
+		optionsSchema, ok := properties["options"]
+		if !ok {
+			continue
+		}
+		options := behavior["options"]
+		if options == nil {
+			options = map[string]interface{}{}
+		}
+		s.validateValue(optionsSchema, options, itemPtr+"/options", 0, errs)
+	}
+}
+
+// validateValue validates a value against a schema, appending the errors found to errs
+func (s *ruleSchema) validateValue(sch interface{}, v interface{}, ptr string, refs int, errs *[]ruleSchemaError) {
+	m, ok := sch.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if ref, ok := m["$ref"].(string); ok {
+		if refs >= maxRuleSchemaRefs {
+			return
+		}
+		if target := s.resolve(ref); target != nil {
+			s.validateValue(target, v, ptr, refs+1, errs)
+		}
+		return
+	}
+
+	for _, sub := range schemaList(m["allOf"]) {
+		s.validateValue(sub, v, ptr, refs, errs)
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		alternatives := schemaList(m[key])
+		if len(alternatives) == 0 {
+			continue
+		}
+		matched := false
+		for _, sub := range alternatives {
+			var subErrs []ruleSchemaError
+			s.validateValue(sub, v, ptr, refs, &subErrs)
+			if len(subErrs) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			*errs = append(*errs, ruleSchemaError{ptr, "the value does not match any of the allowed forms"})
+			return
+		}
+	}
+
+	// PAPI resolves variable expressions like {{user.PMUSER_ORIGIN}} at the edge, their type is not known
+	if str, ok := v.(string); ok && strings.Contains(str, "{{") {
+		return
+	}
+
+	if types := schemaTypes(m["type"]); len(types) > 0 && !matchesType(v, types) {
+		*errs = append(*errs, ruleSchemaError{ptr, fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), jsonType(v))})
+		return
+	}
+
+	if enum, ok := m["enum"].([]interface{}); ok && !inEnum(v, enum) {
+		*errs = append(*errs, ruleSchemaError{ptr, fmt.Sprintf("%s is not one of %s", compactValue(v), compactValue(enum))})
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		s.validateObject(m, value, ptr, refs, errs)
+	case []interface{}:
+		if items, ok := m["items"].(map[string]interface{}); ok {
+			for i, item := range value {
+				s.validateValue(items, item, fmt.Sprintf("%s/%d", ptr, i), refs, errs)
+			}
+		}
+	case string:
+		if min, ok := schemaNumber(m["minLength"]); ok && float64(len(value)) < min {
+			*errs = append(*errs, ruleSchemaError{ptr, fmt.Sprintf("must be at least %v characters long", min)})
+		}
+		if max, ok := schemaNumber(m["maxLength"]); ok && float64(len(value)) > max {
+			*errs = append(*errs, ruleSchemaError{ptr, fmt.Sprintf("must be at most %v characters long", max)})
+		}
+	case json.Number:
+		n, _ := value.Float64()
+		if min, ok := schemaNumber(m["minimum"]); ok && n < min {
+			*errs = append(*errs, ruleSchemaError{ptr, fmt.Sprintf("must be at least %v", min)})
+		}
+		if max, ok := schemaNumber(m["maximum"]); ok && n > max {
+			*errs = append(*errs, ruleSchemaError{ptr, fmt.Sprintf("must be at most %v", max)})
+		}
+	}
+}
+
+func (s *ruleSchema) validateObject(m map[string]interface{}, obj map[string]interface{}, ptr string, refs int, errs *[]ruleSchemaError) {
+	for _, r := range schemaList(m["required"]) {
+		name, _ := r.(string)
+		if _, ok := obj[name]; name != "" && !ok {
+			*errs = append(*errs, ruleSchemaError{ptr + "/" + escapePointer(name), "required option is missing"})
+		}
+	}
+
+	properties, _ := m["properties"].(map[string]interface{})
+	for _, name := range sortedKeys(obj) {
+		namePtr := ptr + "/" + escapePointer(name)
+		if propSchema, ok := properties[name]; ok {
+			s.validateValue(propSchema, obj[name], namePtr, refs, errs)
+			continue
+		}
+		switch additional := m["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*errs = append(*errs, ruleSchemaError{namePtr, "unknown option"})
+			}
+		case map[string]interface{}:
+			s.validateValue(additional, obj[name], namePtr, refs, errs)
+		}
+	}
+}
+
+// resolve returns the schema a local $ref like #/definitions/type_rule points to
+func (s *ruleSchema) resolve(ref string) interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+
+	var current interface{} = s.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if current, ok = m[token]; !ok {
+			return nil
+		}
+	}
+
+	return current
+}
+
+// loadRuleSchema returns the rule format schema of the property
+// The schema is read from file when it is set, otherwise it is fetched from PAPI once and cached. The schema of the
+// latest rule format follows the provider cache ttl as it changes with the next rule format
+func loadRuleSchema(ctx context.Context, meta akamai.OperationMeta, file, productID, ruleFormat string) (*ruleSchema, error) {
+	var data []byte
+	if file != "" {
+		var err error
+		if data, err = ioutil.ReadFile(file); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrRuleSchema, err)
+		}
+		return parseRuleSchema(data)
+	}
+
+	key := fmt.Sprintf("rule_schema:%s:%s", productID, ruleFormat)
+	var cached json.RawMessage
+	if err := meta.CacheGet(inst, key, &cached); err != nil {
+		if !akamai.IsNotFoundError(err) && !errors.Is(err, akamai.ErrCacheDisabled) {
+			return nil, err
+		}
+
+		if cached, err = inst.SchemasClient(meta).GetRuleSchema(ctx, productID, ruleFormat); err != nil {
+			return nil, err
+		}
+
+		if ruleFormat == "latest" {
+			err = meta.CacheSet(inst, key, cached)
+		} else {
+			err = meta.CacheSetWithTTL(inst, key, cached, RuleSchemaCacheTTL)
+		}
+		if err != nil && !errors.Is(err, akamai.ErrCacheDisabled) {
+			return nil, err
+		}
+	}
+
+	return parseRuleSchema(cached)
+}
+
+func decodeJSONNumbers(data []byte) interface{} {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil
+	}
+
+	return v
+}
+
+func schemaList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+func schemaTypes(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+
+	return nil
+}
+
+func schemaNumber(v interface{}) (float64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+
+	return f, err == nil
+}
+
+func matchesType(v interface{}, types []string) bool {
+	actual := jsonType(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonType returns the JSON schema type name of a decoded value
+func jsonType(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := strconv.ParseInt(value.String(), 10, 64); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+func inEnum(v interface{}, enum []interface{}) bool {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(normalizeNumbers(v), normalizeNumbers(allowed)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeNumbers converts numbers so 1 and 1.0 compare equal
+func normalizeNumbers(v interface{}) interface{} {
+	if n, ok := v.(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+	}
+
+	return v
+}
+
+func compactValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(data)
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,106 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+)
+
+// ttlMeta records the ttl of the cached values of the wrapped meta
+type ttlMeta struct {
+	akamai.OperationMeta
+	ttls map[string]time.Duration
+}
+
+func (m *ttlMeta) CacheSet(prov akamai.Subprovider, key string, val interface{}) error {
+	m.ttls[key] = 0
+	return m.OperationMeta.CacheSet(prov, key, val)
+}
+
+func (m *ttlMeta) CacheSetWithTTL(prov akamai.Subprovider, key string, val interface{}, ttl time.Duration) error {
+	m.ttls[key] = ttl
+	return m.OperationMeta.CacheSetWithTTL(prov, key, val, ttl)
+}
+
+func TestRuleSchema(t *testing.T) {
+	s, err := loadRuleSchema(context.Background(), nil, "testdata/TestRuleSchema/schema.json", "", "")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		rules    string
+		expected []ruleSchemaError
+	}{
+		"valid rules": {
+			rules: loadFixtureString("testdata/TestRuleSchema/rules_valid.json"),
+		},
+		"invalid rules": {
+			rules: loadFixtureString("testdata/TestRuleSchema/rules_invalid.json"),
+			expected: []ruleSchemaError{
+				{"/rules/behaviors/0/options/hostname", "required option is missing"},
+				{"/rules/behaviors/0/options/cacheKey", "unknown option"},
+				{"/rules/behaviors/0/options/httpPort", "expected integer, got string"},
+				{"/rules/behaviors/0/options/originType", `"ORIGIN" is not one of ["CUSTOMER","NET_STORAGE"]`},
+				{"/rules/behaviors/1/options/value/id", "must be at least 1"},
+				{"/rules/behaviors/2/name", `unknown behavior "gzipResponse"`},
+				{"/rules/children/0/behaviors/0/options/ttl", "the value does not match any of the allowed forms"},
+				{"/rules/children/0/criteria/0/options/values", "required option is missing"},
+			},
+		},
+		"missing rules object": {
+			rules:    `{"name":"default"}`,
+			expected: []ruleSchemaError{{"/rules", "the rules object is missing"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs, err := s.Validate([]byte(test.rules))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, errs)
+		})
+	}
+}
+
+func TestLoadRuleSchemaCache(t *testing.T) {
+	meta := &ttlMeta{OperationMeta: akamai.Meta(testMeta(t)), ttls: map[string]time.Duration{}}
+	schemas := &mockschemas{}
+	schemas.On("GetRuleSchema", mock.Anything, "prd_Cache_Test", mock.Anything).
+		Return(json.RawMessage(loadFixtureString("testdata/TestRuleSchema/schema.json")), nil)
+
+	useSchemasClient(schemas, func() {
+		for _, ruleFormat := range []string{"v2020-03-04", "latest", "v2020-03-04"} {
+			_, err := loadRuleSchema(context.Background(), meta, "", "prd_Cache_Test", ruleFormat)
+			require.NoError(t, err)
+		}
+	})
+
+	// A frozen rule format is cached for longer than the provider cache ttl, the latest one is not
+	assert.Equal(t, map[string]time.Duration{
+		"rule_schema:prd_Cache_Test:v2020-03-04": RuleSchemaCacheTTL,
+		"rule_schema:prd_Cache_Test:latest":      0,
+	}, meta.ttls)
+	schemas.AssertNumberOfCalls(t, "GetRuleSchema", 2)
+}
+
+func TestParseRuleSchema(t *testing.T) {
+	tests := map[string]string{
+		"not an object":    `[]`,
+		"missing catalog":  `{"definitions":{}}`,
+		"missing criteria": `{"definitions":{"catalog":{"behaviors":{}}}}`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseRuleSchema([]byte(data))
+			assert.True(t, errors.Is(err, ErrRuleSchema), "want %v, got %v", ErrRuleSchema, err)
+		})
+	}
+}
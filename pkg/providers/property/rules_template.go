@@ -0,0 +1,556 @@
+package property
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/apex/log"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+// Rules templates use the snippet format of the Property Manager CLI: a JSON string like "#include:file.json" is
+// replaced by the snippet in that file and a JSON string like "${env.name}" by the value of the variable. On top of
+// that format:
+//
+//   * include paths may be glob patterns, all matching snippets are included in lexical order
+//   * paths starting with "./" or "../" are resolved from the including snippet, other paths from the directory of
+//     the main template like the CLI does
+//   * "#includeIf:<expression>:file.json" includes the snippet only when the expression is truthy, "!" negates it
+//   * "${...}" accepts the functions default(value, fallback), join(list, separator) and lookup(map, key[, fallback])
+//     with 'single quoted' string literals
+//
+// Snippets are converted line by line to text/template, so template errors keep the file and line of the snippet.
+
+const (
+	leftDelim  = "@+#"
+	rightDelim = "#+@"
+
+	// omittedInclude stands for an include that renders nothing, it is removed from the result with its comma
+	omittedInclude = `"#omitted"`
+)
+
+var (
+	includeRegexp = regexp.MustCompile(`"#include(If)?:([^"]+)"`)
+	varRegexp     = regexp.MustCompile(`"\$\{((?:[^"'}]|'[^']*')+)\}"`)
+
+	identRegexp  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	numberRegexp = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+	// templateErrorRegexp matches the location text/template puts in front of parse and execution errors
+	templateErrorRegexp = regexp.MustCompile(`^template: ([^:]+):([0-9]+)(?::[0-9]+)?: ((?s).*)$`)
+
+	sourceMarkerRegexp   = regexp.MustCompile(`\x00[^\x00]*\x00`)
+	omittedIncludeRegexp = regexp.MustCompile(`"#omitted"(?:\s|\x00[^\x00]*\x00)*,|,(?:\s|\x00[^\x00]*\x00)*"#omitted"|"#omitted"`)
+)
+
+// expressionFunctions are the functions usable in "${...}" expressions
+var expressionFunctions = map[string]bool{
+	"default": true,
+	"join":    true,
+	"lookup":  true,
+}
+
+var rulesTemplateFuncs = template.FuncMap{
+	"default": templateDefault,
+	"join":    templateJoin,
+	"lookup":  templateLookup,
+	"truthy":  templateTruthy,
+}
+
+// rulesTemplate is a main template with the snippets it includes, snippets are named by their slash separated path
+// relative to the directory of the main template
+type rulesTemplate struct {
+	root string
+	main string
+	tmpl *template.Template
+}
+
+func newRulesTemplate(file string) (*rulesTemplate, error) {
+	t := &rulesTemplate{
+		root: filepath.Dir(file),
+		main: filepath.Base(file),
+	}
+	t.tmpl = template.New(t.main).Delims(leftDelim, rightDelim).Option("missingkey=error").Funcs(rulesTemplateFuncs)
+	if err := t.load(t.main); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// load parses a snippet and the snippets it includes, an include of a missing file fails when the template is executed
+func (t *rulesTemplate) load(name string) error {
+	if t.tmpl.Lookup(name) != nil {
+		return nil
+	}
+	text, includes, err := convertToTemplate(t.root, name)
+	if err != nil {
+		return err
+	}
+	if _, err := t.tmpl.New(name).Delims(leftDelim, rightDelim).Option("missingkey=error").Parse(markSourceLines(name, text)); err != nil {
+		return t.sourceError(err)
+	}
+	for _, include := range includes {
+		if _, err := os.Stat(t.path(include)); err != nil {
+			continue
+		}
+		if err := t.load(include); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execute renders the template with the given variables to indented JSON
+func (t *rulesTemplate) execute(ctx context.Context, vars map[string]interface{}) (string, error) {
+	logger := log.FromContext(ctx)
+
+	wr := bytes.Buffer{}
+	if err := t.tmpl.ExecuteTemplate(&wr, t.main, vars); err != nil {
+		return "", t.sourceError(err)
+	}
+	rendered := omittedIncludeRegexp.ReplaceAllFunc(wr.Bytes(), func(match []byte) []byte {
+		return bytes.Join(sourceMarkerRegexp.FindAll(match, -1), nil)
+	})
+	result, sources := stripSourceLines(rendered)
+
+	formatted := bytes.Buffer{}
+	if err := json.Indent(&formatted, result, "", "  "); err != nil {
+		logger.Debugf("Creating rule tree resulted in invalid JSON: %s\nError: %s", result, err)
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset := int(syntaxErr.Offset) - 1
+			if offset < 0 {
+				offset = 0
+			}
+			if source, ok := sourceAt(sources, offset); ok {
+				return "", fmt.Errorf("invalid JSON result: %s:%d: %w", t.path(source.name), source.line, err)
+			}
+		}
+		return "", fmt.Errorf("invalid JSON result: %w", err)
+	}
+	return formatted.String(), nil
+}
+
+func (t *rulesTemplate) path(name string) string {
+	return filepath.Join(t.root, filepath.FromSlash(name))
+}
+
+// sourceError replaces the template name and line of a text/template error by the snippet file and line
+func (t *rulesTemplate) sourceError(err error) error {
+	m := templateErrorRegexp.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	return fmt.Errorf("%s:%s: %s", t.path(m[1]), m[2], m[3])
+}
+
+// convertToTemplate converts the snippet root/name to a text/template and returns the names of the snippets it
+// includes, every line of the snippet stays on the same line of the template
+func convertToTemplate(root, name string) (string, []string, error) {
+	path := filepath.Join(root, filepath.FromSlash(name))
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrReadFile, err)
+	}
+	defer f.Close()
+
+	var includes []string
+	builder := strings.Builder{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		var lineErr error
+		line := includeRegexp.ReplaceAllFunc(scanner.Bytes(), func(statement []byte) []byte {
+			action, names, err := convertInclude(root, name, includeRegexp.FindSubmatch(statement))
+			if err != nil && lineErr == nil {
+				lineErr = err
+			}
+			includes = append(includes, names...)
+			return []byte(action)
+		})
+		line = varRegexp.ReplaceAllFunc(line, func(statement []byte) []byte {
+			expr := string(varRegexp.FindSubmatch(statement)[1])
+			pipeline, err := convertExpression(expr)
+			if err != nil && lineErr == nil {
+				lineErr = fmt.Errorf("%w %q: %s", ErrTemplateExpression, expr, err)
+			}
+			return []byte(leftDelim + pipeline + rightDelim)
+		})
+		if lineErr != nil {
+			return "", nil, fmt.Errorf("%s:%d: %w", path, n, lineErr)
+		}
+		builder.Write(line)
+		builder.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrReadFile, err)
+	}
+	return builder.String(), includes, nil
+}
+
+// convertInclude converts a matched include statement to template actions
+func convertInclude(root, from string, statement [][]byte) (string, []string, error) {
+	conditional, target := len(statement[1]) > 0, string(statement[2])
+
+	var condition string
+	negate := false
+	if conditional {
+		i := strings.LastIndex(target, ":")
+		if i < 1 {
+			return "", nil, fmt.Errorf("%w %q: expected #includeIf:<expression>:<file>", ErrTemplateInclude, target)
+		}
+		expr := target[:i]
+		target = target[i+1:]
+		if strings.HasPrefix(expr, "!") {
+			negate = true
+			expr = expr[1:]
+		}
+		pipeline, err := convertExpression(expr)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w %q: %s", ErrTemplateExpression, expr, err)
+		}
+		condition = pipeline
+	}
+
+	names, err := includeNames(root, from, target)
+	if err != nil {
+		return "", nil, err
+	}
+	actions := make([]string, 0, len(names))
+	for _, name := range names {
+		actions = append(actions, fmt.Sprintf(`%stemplate "%s" .%s`, leftDelim, name, rightDelim))
+	}
+	included := strings.Join(actions, ",")
+	if len(names) == 0 {
+		included = omittedInclude
+	}
+	if !conditional {
+		return included, names, nil
+	}
+
+	then, otherwise := included, omittedInclude
+	if negate {
+		then, otherwise = otherwise, then
+	}
+	return fmt.Sprintf("%sif truthy %s%s%s%selse%s%s%send%s",
+		leftDelim, condition, rightDelim, then, leftDelim, rightDelim, otherwise, leftDelim, rightDelim), names, nil
+}
+
+// includeNames resolves an include path to the names of the snippets it refers to, a glob pattern can match none
+func includeNames(root, from, target string) ([]string, error) {
+	name := filepath.FromSlash(target)
+	if strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") {
+		name = filepath.Join(filepath.Dir(filepath.FromSlash(from)), name)
+	}
+	name = filepath.Clean(name)
+	if !strings.ContainsAny(target, "*?[") {
+		return []string{filepath.ToSlash(name)}, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(root, name))
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %s", ErrTemplateInclude, target, err)
+	}
+	var names []string
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(root, match)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %s", ErrTemplateInclude, target, err)
+		}
+		if rel = filepath.ToSlash(rel); rel != from {
+			names = append(names, rel)
+		}
+	}
+	return names, nil
+}
+
+// convertExpression converts the content of a "${...}" statement to a template pipeline
+func convertExpression(expr string) (string, error) {
+	p := expressionParser{expr: expr}
+	pipeline, err := p.term(false)
+	if err != nil {
+		return "", err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.expr) {
+		return "", fmt.Errorf("unexpected %q at position %d", p.expr[p.pos], p.pos+1)
+	}
+	return pipeline, nil
+}
+
+type expressionParser struct {
+	expr string
+	pos  int
+}
+
+func (p *expressionParser) skipSpaces() {
+	for p.pos < len(p.expr) && p.expr[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *expressionParser) next() byte {
+	if p.pos < len(p.expr) {
+		return p.expr[p.pos]
+	}
+	return 0
+}
+
+// term parses a variable reference, a literal or a function call, an optional variable is nil when it is not defined
+func (p *expressionParser) term(optional bool) (string, error) {
+	p.skipSpaces()
+	if p.pos == len(p.expr) {
+		return "", errors.New("unexpected end of expression")
+	}
+
+	if p.next() == '\'' {
+		end := strings.IndexByte(p.expr[p.pos+1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string at position %d", p.pos+1)
+		}
+		literal, err := json.Marshal(p.expr[p.pos+1 : p.pos+1+end])
+		if err != nil {
+			return "", err
+		}
+		p.pos += end + 2
+		// like string variables, string literals are JSON strings
+		return strconv.Quote(string(literal)), nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.expr) && strings.IndexByte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_.-+", p.expr[p.pos]) >= 0 {
+		p.pos++
+	}
+	word := p.expr[start:p.pos]
+	switch {
+	case word == "":
+		return "", fmt.Errorf("unexpected %q at position %d", p.expr[p.pos], p.pos+1)
+	case strings.HasPrefix(word, "env."):
+		name := strings.TrimPrefix(word, "env.")
+		if !identRegexp.MatchString(name) {
+			return "", fmt.Errorf("invalid variable name %q", name)
+		}
+		if optional {
+			return fmt.Sprintf("(index . %q)", name), nil
+		}
+		return "." + name, nil
+	case word == "true" || word == "false":
+		return word, nil
+	case word == "null":
+		return `"null"`, nil
+	case numberRegexp.MatchString(word):
+		return word, nil
+	}
+
+	p.skipSpaces()
+	if p.next() != '(' {
+		return "", fmt.Errorf("unknown term %q, expected env.<name>, a literal or a function call", word)
+	}
+	if !expressionFunctions[word] {
+		return "", fmt.Errorf("unknown function %q", word)
+	}
+	p.pos++
+	args := []string{word}
+	for {
+		arg, err := p.term(word == "default" && len(args) == 1)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, arg)
+		p.skipSpaces()
+		switch p.next() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return "(" + strings.Join(args, " ") + ")", nil
+		default:
+			return "", fmt.Errorf("expected ',' or ')' at position %d", p.pos+1)
+		}
+	}
+}
+
+// decodeTemplateValue returns the value of a variable, strings, lists, maps and JSON blocks are held as JSON text
+func decodeTemplateValue(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(s), &value); err != nil {
+		// string variables are quoted but not escaped
+		return strings.TrimSuffix(strings.TrimPrefix(s, `"`), `"`)
+	}
+	return value
+}
+
+func encodeTemplateValue(v interface{}) (string, error) {
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// templateDefault returns the fallback when the value is undefined, null or an empty string
+func templateDefault(value, fallback interface{}) interface{} {
+	switch v := decodeTemplateValue(value).(type) {
+	case nil:
+		return fallback
+	case string:
+		if v == "" {
+			return fallback
+		}
+	}
+	return value
+}
+
+// templateJoin joins the items of a list to a string
+func templateJoin(list, separator interface{}) (string, error) {
+	items, ok := decodeTemplateValue(list).([]interface{})
+	if !ok {
+		return "", fmt.Errorf("%w: join expects a list, got %v", tools.ErrInvalidType, list)
+	}
+	sep, ok := decodeTemplateValue(separator).(string)
+	if !ok {
+		return "", fmt.Errorf("%w: join expects a string separator, got %v", tools.ErrInvalidType, separator)
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			parts = append(parts, s)
+			continue
+		}
+		s, err := encodeTemplateValue(item)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, s)
+	}
+	return encodeTemplateValue(strings.Join(parts, sep))
+}
+
+// templateLookup returns the value of a key of a map, or the fallback when the map has no such key
+func templateLookup(values, key interface{}, fallback ...interface{}) (interface{}, error) {
+	m, ok := decodeTemplateValue(values).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: lookup expects a map, got %v", tools.ErrInvalidType, values)
+	}
+	k, ok := decodeTemplateValue(key).(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: lookup expects a string key, got %v", tools.ErrInvalidType, key)
+	}
+	if v, ok := m[k]; ok {
+		return encodeTemplateValue(v)
+	}
+	if len(fallback) > 0 {
+		return fallback[0], nil
+	}
+	return nil, fmt.Errorf("%w: key %q in map", tools.ErrNotFound, k)
+}
+
+// templateTruthy tells whether a value enables a conditional include: false, 0, null, "" and empty lists and maps do not
+func templateTruthy(value interface{}) bool {
+	switch v := decodeTemplateValue(value).(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case int:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// Executed templates carry markers of the snippet line that produced the output so that invalid JSON can be reported
+// at its snippet line. A marker is enclosed in NUL bytes, which JSON text cannot contain unescaped.
+const (
+	markerEnter = 'E' // start of a snippet, followed by the snippet name
+	markerLine  = 'L' // start of a line, followed by the line number
+	markerLeave = 'X' // end of a snippet
+)
+
+type sourceLine struct {
+	offset int
+	name   string
+	line   int
+}
+
+func markSourceLines(name, text string) string {
+	builder := strings.Builder{}
+	builder.WriteString("\x00" + string(markerEnter) + name + "\x00")
+	for i, line := range strings.SplitAfter(text, "\n") {
+		if line != "" {
+			builder.WriteString(fmt.Sprintf("\x00%c%d\x00%s", markerLine, i+1, line))
+		}
+	}
+	builder.WriteString("\x00" + string(markerLeave) + "\x00")
+	return builder.String()
+}
+
+// stripSourceLines removes the markers from the output and returns the offsets at which the source line changes
+func stripSourceLines(rendered []byte) ([]byte, []sourceLine) {
+	var (
+		result  []byte
+		sources []sourceLine
+		stack   []sourceLine
+		current sourceLine
+		last    int
+	)
+	for _, loc := range sourceMarkerRegexp.FindAllIndex(rendered, -1) {
+		result = append(result, rendered[last:loc[0]]...)
+		last = loc[1]
+		marker := rendered[loc[0]+1 : loc[1]-1]
+		switch {
+		case len(marker) == 0:
+		case marker[0] == markerEnter:
+			stack = append(stack, current)
+			current = sourceLine{name: string(marker[1:])}
+		case marker[0] == markerLine:
+			current.line, _ = strconv.Atoi(string(marker[1:]))
+		case marker[0] == markerLeave && len(stack) > 0:
+			current = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+		}
+		current.offset = len(result)
+		sources = append(sources, current)
+	}
+	return append(result, rendered[last:]...), sources
+}
+
+// sourceAt returns the snippet line that produced the output at the given offset
+func sourceAt(sources []sourceLine, offset int) (sourceLine, bool) {
+	var found sourceLine
+	ok := false
+	for _, source := range sources {
+		if source.offset > offset {
+			break
+		}
+		found, ok = source, source.name != ""
+	}
+	return found, ok
+}
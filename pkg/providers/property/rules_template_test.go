@@ -0,0 +1,148 @@
+package property
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+func TestRulesTemplateExecute(t *testing.T) {
+	templates := "testdata/TestRulesTemplate"
+	vars := map[string]interface{}{
+		"hostnames": `["www.example.com","static.example.com"]`,
+		"origins":   `{"eu":"origin-eu.example.com","us":"origin-us.example.com"}`,
+		"ports":     `{"us":8080}`,
+		"cpCode":    12345.0,
+	}
+	withVars := func(extra map[string]interface{}) map[string]interface{} {
+		merged := make(map[string]interface{}, len(vars)+len(extra))
+		for k, v := range vars {
+			merged[k] = v
+		}
+		for k, v := range extra {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	tests := map[string]struct {
+		file      string
+		vars      map[string]interface{}
+		expected  string
+		withError string
+	}{
+		"glob includes and functions, conditional include enabled": {
+			file:     "main.json",
+			vars:     withVars(map[string]interface{}{"name": `"main"`, "region": `"us"`, "waf": `"waf_1"`}),
+			expected: "rules_waf.json",
+		},
+		"conditional include disabled and defaults": {
+			file:     "main.json",
+			vars:     withVars(map[string]interface{}{"region": `"eu"`, "waf": false}),
+			expected: "rules_no_waf.json",
+		},
+		"undefined variable is reported at the snippet line": {
+			file:      "errors/undefined_variable.json",
+			vars:      vars,
+			withError: `testdata/TestRulesTemplate/errors/undefined.json:7: executing "undefined.json" at <.caching>: map has no entry for key "caching"`,
+		},
+		"invalid JSON is reported at the snippet line": {
+			file:      "errors/invalid_json.json",
+			vars:      vars,
+			withError: "invalid JSON result: testdata/TestRulesTemplate/errors/invalid_snippet.json:7: invalid character '\"' after object key:value pair",
+		},
+		"function error is reported at the snippet line": {
+			file:      "errors/missing_key.json",
+			vars:      vars,
+			withError: `testdata/TestRulesTemplate/errors/missing_key.json:5: executing "missing_key.json" at <lookup .origins "\"nowhere\"">: error calling lookup: value not found: key "nowhere" in map`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := newRulesTemplate(templates + "/" + test.file)
+			require.NoError(t, err)
+			res, err := tmpl.execute(context.Background(), test.vars)
+			if test.withError != "" {
+				require.Error(t, err)
+				assert.Equal(t, test.withError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, loadFixtureString(templates+"/"+test.expected), res)
+		})
+	}
+}
+
+func TestRulesTemplateFunctions(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		assert.Equal(t, `"fallback"`, templateDefault(nil, `"fallback"`))
+		assert.Equal(t, `"fallback"`, templateDefault(`""`, `"fallback"`))
+		assert.Equal(t, `"fallback"`, templateDefault("null", `"fallback"`))
+		assert.Equal(t, `"value"`, templateDefault(`"value"`, `"fallback"`))
+		assert.Equal(t, false, templateDefault(false, true))
+	})
+	t.Run("join", func(t *testing.T) {
+		res, err := templateJoin(`["a", 1, true, {"b":"c"}]`, `"-"`)
+		require.NoError(t, err)
+		assert.Equal(t, `"a-1-true-{\"b\":\"c\"}"`, res)
+		_, err = templateJoin(`"not a list"`, `","`)
+		assert.True(t, errors.Is(err, tools.ErrInvalidType))
+	})
+	t.Run("lookup", func(t *testing.T) {
+		res, err := templateLookup(`{"a":{"b":[1,2]}}`, `"a"`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"b":[1,2]}`, res)
+		res, err = templateLookup(`{"a":1}`, `"b"`, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, res)
+		_, err = templateLookup(`{"a":1}`, `"b"`)
+		assert.True(t, errors.Is(err, tools.ErrNotFound))
+		_, err = templateLookup(`[1]`, `"b"`)
+		assert.True(t, errors.Is(err, tools.ErrInvalidType))
+	})
+	t.Run("truthy", func(t *testing.T) {
+		for _, v := range []interface{}{nil, "null", false, 0, 0.0, `""`, `[]`, `{}`} {
+			assert.False(t, templateTruthy(v), "%#v", v)
+		}
+		for _, v := range []interface{}{true, 1, 2.5, `"x"`, `[1]`, `{"a":1}`} {
+			assert.True(t, templateTruthy(v), "%#v", v)
+		}
+	})
+}
+
+func TestConvertExpression(t *testing.T) {
+	tests := map[string]struct {
+		expr      string
+		expected  string
+		withError bool
+	}{
+		"variable":          {expr: "env.name", expected: ".name"},
+		"string literal":    {expr: "'a b'", expected: `"\"a b\""`},
+		"number literal":    {expr: "-1.5", expected: "-1.5"},
+		"null literal":      {expr: "null", expected: `"null"`},
+		"default":           {expr: "default(env.ttl, 300)", expected: `(default (index . "ttl") 300)`},
+		"nested calls":      {expr: "join( lookup(env.hosts, env.region) , ',')", expected: `(join (lookup .hosts .region) "\",\"")`},
+		"unknown function":  {expr: "upper(env.name)", withError: true},
+		"unknown term":      {expr: "name", withError: true},
+		"invalid name":      {expr: "env.my-var", withError: true},
+		"unterminated":      {expr: "join(env.a, ','", withError: true},
+		"unterminated text": {expr: "default(env.a, 'b)", withError: true},
+		"trailing input":    {expr: "env.a env.b", withError: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := convertExpression(test.expr)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+)
+
+// apiError is a problem response of an API called through the session, it matches the error of the API
+type apiError struct {
+	API        error  `json:"-"`
+	Method     string `json:"-"`
+	Path       string `json:"-"`
+	StatusCode int    `json:"-"`
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Detail     string `json:"detail"`
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("%s: %s %s: unexpected response status %d", e.API, e.Method, e.Path, e.StatusCode)
+	if e.Title != "" {
+		msg += ": " + e.Title
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// Unwrap makes the problem responses match the error of the API
+func (e *apiError) Unwrap() error {
+	return e.API
+}
+
+// execAPI calls an API the edgegrid library has no client for, the errors wrap api. A response status other than
+// expected is returned as an *apiError
+func execAPI(ctx context.Context, sess session.Session, api error, method, path, contentType string, in, out interface{}, expected int) error {
+	req, err := http.NewRequestWithContext(ctx, method, path, nil)
+	if err != nil {
+		return fmt.Errorf("%w: failed to create request: %s", api, err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	var resp *http.Response
+	if in != nil {
+		resp, err = sess.Exec(req, out, in)
+	} else {
+		resp, err = sess.Exec(req, out)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: request failed: %s", api, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		e := &apiError{API: api, Method: method, Path: path, StatusCode: resp.StatusCode}
+		if body, err := ioutil.ReadAll(resp.Body); err == nil {
+			_ = json.Unmarshal(body, e)
+		}
+		return e
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+data "akamai_property_rules_builder" "child" {
+  name = "default"
+}
+
+data "akamai_property_rules_builder" "test" {
+  name     = "default"
+  children = [data.akamai_property_rules_builder.child.json]
+}
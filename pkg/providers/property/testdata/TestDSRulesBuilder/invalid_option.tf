@@ -0,0 +1,16 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+data "akamai_property_rules_builder" "test" {
+  name             = "default"
+  rule_schema_file = "testdata/TestRuleSchema/schema.json"
+  behavior {
+    name = "origin"
+    options = {
+      originType = "ORIGIN"
+      hostname   = "origin.example.com"
+      cacheKey   = "ORIGIN_HOSTNAME"
+    }
+  }
+}
@@ -0,0 +1,47 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+data "akamai_property_rules_builder" "compression" {
+  name                  = "Compressible objects"
+  criteria_must_satisfy = "any"
+  criterion {
+    name = "contentType"
+    options = {
+      matchOperator      = "IS_ONE_OF"
+      matchWildcard      = true
+      matchCaseSensitive = false
+      values             = jsonencode(["text/html*", "text/css*"])
+    }
+  }
+  behavior {
+    name = "gzipResponse"
+    options = {
+      behavior = "ALWAYS"
+    }
+  }
+}
+
+data "akamai_property_rules_builder" "default" {
+  name      = "default"
+  is_secure = true
+  behavior {
+    name = "origin"
+    options = {
+      hostname   = "origin.example.com"
+      httpPort   = 80
+      originType = "CUSTOMER"
+    }
+  }
+  behavior {
+    name = "cpCode"
+    options = {
+      value = jsonencode({ id = 12345 })
+    }
+  }
+  variable {
+    name  = "PMUSER_ORIGIN"
+    value = "origin.example.com"
+  }
+  children = [data.akamai_property_rules_builder.compression.json]
+}
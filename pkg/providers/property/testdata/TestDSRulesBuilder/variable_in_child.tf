@@ -0,0 +1,11 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+data "akamai_property_rules_builder" "test" {
+  name = "Offload"
+  variable {
+    name  = "PMUSER_TEST"
+    value = "test"
+  }
+}
@@ -0,0 +1,37 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+data "akamai_property_rules_template" "test" {
+  template_file = "testdata/TestRulesTemplate/main.json"
+  variables {
+    name = "hostnames"
+    value = "[\"www.example.com\",\"static.example.com\"]"
+    type = "list"
+  }
+  variables {
+    name = "origins"
+    value = "{\"eu\":\"origin-eu.example.com\",\"us\":\"origin-us.example.com\"}"
+    type = "map"
+  }
+  variables {
+    name = "ports"
+    value = "{\"us\":8080}"
+    type = "map"
+  }
+  variables {
+    name = "region"
+    value = "eu"
+    type = "string"
+  }
+  variables {
+    name = "waf"
+    value = "false"
+    type = "bool"
+  }
+  variables {
+    name = "cpCode"
+    value = "12345"
+    type = "number"
+  }
+}
@@ -0,0 +1,9 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+data "akamai_edge_hostnames" "ehns" {
+  contract_id = "1"
+  group_id = "2"
+  domain_suffix = "edgekey.net"
+}
@@ -0,0 +1,6 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+data "akamai_property_search" "search" {
+}
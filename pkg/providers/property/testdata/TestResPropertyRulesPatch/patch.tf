@@ -0,0 +1,10 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+resource "akamai_property_rules_patch" "http_port" {
+  property_ids = ["prp_1", "prp_2"]
+  patch = jsonencode([
+    { op = "replace", path = "/rules/behaviors/0/options/httpPort", value = 8080 },
+  ])
+}
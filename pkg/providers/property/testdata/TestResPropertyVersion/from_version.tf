@@ -0,0 +1,13 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+resource "akamai_property_version" "test" {
+  property_id         = "prp_1"
+  contract_id         = "ctr_1"
+  group_id            = "grp_1"
+  create_from_version = 1
+  hostnames = {
+    "www.example.com" = "www.example.com.edgesuite.net"
+  }
+}
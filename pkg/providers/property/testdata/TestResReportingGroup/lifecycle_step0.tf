@@ -0,0 +1,10 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+resource "akamai_reporting_group" "test" {
+  name        = "reports"
+  contract_id = "ctr_1"
+  group_id    = "grp_2"
+  cp_codes    = ["cpc_1"]
+}
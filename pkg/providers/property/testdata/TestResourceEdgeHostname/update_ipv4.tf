@@ -0,0 +1,12 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+resource "akamai_edge_hostname" "edgehostname" {
+  contract = "2"
+  group = "2"
+  product = "2"
+  edge_hostname = "test.edgesuite.net"
+  ip_behavior = "IPV4"
+  delete_on_destroy = true
+}
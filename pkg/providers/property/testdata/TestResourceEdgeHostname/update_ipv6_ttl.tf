@@ -0,0 +1,14 @@
+provider "akamai" {
+  edgerc = "~/.edgerc"
+}
+
+resource "akamai_edge_hostname" "edgehostname" {
+  contract = "2"
+  group = "2"
+  product = "2"
+  edge_hostname = "test.edgesuite.net"
+  ip_behavior = "IPV6_COMPLIANCE"
+  ttl = 300
+  status_update_email = ["user@example.com"]
+  delete_on_destroy = true
+}
@@ -0,0 +1,740 @@
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/appsec"
+)
+
+const (
+	// AppSecConfigID is the security configuration available on the server, the API cannot create configurations
+	AppSecConfigID = 43253
+
+	// AppSecConfigName is the name of the security configuration available on the server
+	AppSecConfigName = "Test Security Configuration"
+)
+
+// appsecCollections lists the objects of a configuration version which get their ids allocated by the server
+var appsecCollections = []struct {
+	path    string
+	idField string
+	listKey string
+}{
+	{path: "security-policies", idField: "policyId", listKey: "policies"},
+	{path: "match-targets", idField: "targetId", listKey: "matchTargets"},
+	{path: "rate-policies", idField: "id", listKey: "ratePolicies"},
+	{path: "reputation-profiles", idField: "id", listKey: "reputationProfiles"},
+}
+
+// appsecPolicySettings lists the documents of a security policy, they are stored as sent by the client
+var appsecPolicySettings = []string{
+	"mode",
+	"protections",
+	"ip-geo-firewall",
+	"penalty-box",
+	"slow-post",
+	"eval",
+	"rules",
+	"rules/upgrade-details",
+	"rules/{id}",
+	"rules/{id}/condition-exception",
+	"attack-groups",
+	"attack-groups/{id}",
+	"attack-groups/{id}/condition-exception",
+	"eval-rules",
+	"eval-rules/{id}",
+	"eval-rules/{id}/condition-exception",
+	"rate-policies",
+	"rate-policies/{id}",
+	"reputation-profiles",
+	"reputation-profiles/{id}",
+	"custom-rules",
+	"custom-rules/{id}",
+}
+
+type (
+	appsecState struct {
+		configs     map[int]*appsecConfig
+		activations map[int]*appsecActivation
+	}
+
+	appsecConfig struct {
+		id                int
+		name              string
+		versions          map[int]*appsecVersion
+		lastVersion       int
+		stagingVersion    int
+		productionVersion int
+		customRules       map[string]map[string]interface{}
+	}
+
+	// appsecVersion keeps the documents of a configuration version as sent by the client
+	appsecVersion struct {
+		version     int
+		basedOn     int
+		createDate  string
+		staging     string
+		production  string
+		collections map[string]map[string]map[string]interface{}
+		documents   map[string]interface{}
+	}
+
+	appsecActivation struct {
+		appsec.GetActivationsResponse
+		configVersion int
+		pending       *pending
+	}
+)
+
+func newAppSecState() *appsecState {
+	cfg := &appsecConfig{
+		id:          AppSecConfigID,
+		name:        AppSecConfigName,
+		versions:    make(map[int]*appsecVersion),
+		customRules: make(map[string]map[string]interface{}),
+	}
+	cfg.versions[1] = newAppSecVersion(1, 0, "")
+	cfg.lastVersion = 1
+
+	return &appsecState{
+		configs:     map[int]*appsecConfig{cfg.id: cfg},
+		activations: make(map[int]*appsecActivation),
+	}
+}
+
+func newAppSecVersion(version, basedOn int, createDate string) *appsecVersion {
+	v := &appsecVersion{
+		version:     version,
+		basedOn:     basedOn,
+		createDate:  createDate,
+		staging:     "Inactive",
+		production:  "Inactive",
+		collections: make(map[string]map[string]map[string]interface{}),
+		documents:   make(map[string]interface{}),
+	}
+	for _, c := range appsecCollections {
+		v.collections[c.path] = make(map[string]map[string]interface{})
+	}
+
+	return v
+}
+
+func (s *Server) registerAppSec() {
+	rt := s.router
+	const version = "/appsec/v1/configs/{configId}/versions/{version}"
+	rt.handle(http.MethodGet, "/appsec/v1/configs", s.appsecGetConfigurations)
+	rt.handle(http.MethodGet, "/appsec/v1/configs/{configId}/versions", s.appsecGetVersions)
+	rt.handle(http.MethodPost, "/appsec/v1/configs/{configId}/versions", s.appsecCloneVersion)
+	rt.handle(http.MethodGet, "/appsec/v1/configs/{configId}/custom-rules", s.appsecGetCustomRules)
+	rt.handle(http.MethodPost, "/appsec/v1/configs/{configId}/custom-rules", s.appsecCreateCustomRule)
+	rt.handle(http.MethodGet, "/appsec/v1/configs/{configId}/custom-rules/{id}", s.appsecGetCustomRule)
+	rt.handle(http.MethodPut, "/appsec/v1/configs/{configId}/custom-rules/{id}", s.appsecUpdateCustomRule)
+	rt.handle(http.MethodDelete, "/appsec/v1/configs/{configId}/custom-rules/{id}", s.appsecRemoveCustomRule)
+	rt.handle(http.MethodGet, version, s.appsecGetVersion)
+	rt.handle(http.MethodGet, "/appsec/v1/export/configs/{configId}/versions/{version}", s.appsecExportVersion)
+	rt.handle(http.MethodGet, version+"/selectable-hostnames", s.appsecGetSelectableHostnames)
+	rt.handle(http.MethodGet, version+"/selected-hostnames", s.appsecGetDocument)
+	rt.handle(http.MethodPut, version+"/selected-hostnames", s.appsecPutDocument)
+	rt.handle(http.MethodGet, version+"/match-targets/sequence", s.appsecGetDocument)
+	rt.handle(http.MethodPut, version+"/match-targets/sequence", s.appsecPutDocument)
+	for _, c := range appsecCollections {
+		rt.handle(http.MethodGet, version+"/"+c.path, s.appsecListObjects)
+		rt.handle(http.MethodPost, version+"/"+c.path, s.appsecCreateObject)
+		rt.handle(http.MethodGet, version+"/"+c.path+"/{id}", s.appsecGetObject)
+		rt.handle(http.MethodPut, version+"/"+c.path+"/{id}", s.appsecUpdateObject)
+		rt.handle(http.MethodDelete, version+"/"+c.path+"/{id}", s.appsecRemoveObject)
+	}
+	for _, setting := range appsecPolicySettings {
+		path := version + "/security-policies/{policyId}/" + setting
+		rt.handle(http.MethodGet, path, s.appsecGetDocument)
+		rt.handle(http.MethodPut, path, s.appsecPutDocument)
+		rt.handle(http.MethodDelete, path, s.appsecRemoveDocument)
+	}
+	rt.handle(http.MethodPost, "/appsec/v1/activations", s.appsecCreateActivation)
+	rt.handle(http.MethodGet, "/appsec/v1/activations/{activationId}", s.appsecGetActivation)
+}
+
+func (s *Server) appsecConfig(w http.ResponseWriter, p params) *appsecConfig {
+	id, err := strconv.Atoi(p["configId"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid configuration id %s", p["configId"])
+		return nil
+	}
+	cfg, ok := s.appsec.configs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Configuration %d not found", id)
+		return nil
+	}
+
+	return cfg
+}
+
+// appsecVersion returns the configuration version of the path, the policy of the path has to exist when there is one
+func (s *Server) appsecVersion(w http.ResponseWriter, p params) (*appsecConfig, *appsecVersion) {
+	cfg := s.appsecConfig(w, p)
+	if cfg == nil {
+		return nil, nil
+	}
+	number, err := strconv.Atoi(p["version"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid version %s", p["version"])
+		return nil, nil
+	}
+	version, ok := cfg.versions[number]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Version %d of configuration %d not found", number, cfg.id)
+		return nil, nil
+	}
+	if policyID, ok := p["policyId"]; ok {
+		if _, ok := version.collections["security-policies"][policyID]; !ok {
+			writeError(w, http.StatusNotFound, "Security policy %s not found", policyID)
+			return nil, nil
+		}
+	}
+
+	return cfg, version
+}
+
+// editable writes an error and returns false when the version has been activated
+func (v *appsecVersion) editable(w http.ResponseWriter) bool {
+	if v.staging != "Inactive" || v.production != "Inactive" {
+		writeError(w, http.StatusBadRequest, "Version %d has been activated and cannot be modified", v.version)
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) appsecGetConfigurations(w http.ResponseWriter, _ *http.Request, _ params) {
+	var resp appsec.GetConfigurationsResponse
+	ids := make([]int, 0, len(s.appsec.configs))
+	for id := range s.appsec.configs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		cfg := s.appsec.configs[id]
+		resp.Configurations = append(resp.Configurations, struct {
+			Description         string   `json:"description,omitempty"`
+			FileType            string   `json:"fileType,omitempty"`
+			ID                  int      `json:"id,omitempty"`
+			LatestVersion       int      `json:"latestVersion,omitempty"`
+			Name                string   `json:"name,omitempty"`
+			StagingVersion      int      `json:"stagingVersion,omitempty"`
+			TargetProduct       string   `json:"targetProduct,omitempty"`
+			ProductionHostnames []string `json:"productionHostnames,omitempty"`
+			ProductionVersion   int      `json:"productionVersion,omitempty"`
+		}{
+			FileType:          "WAF",
+			ID:                cfg.id,
+			LatestVersion:     cfg.lastVersion,
+			Name:              cfg.name,
+			StagingVersion:    cfg.stagingVersion,
+			TargetProduct:     "KSD",
+			ProductionVersion: cfg.productionVersion,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (cfg *appsecConfig) versionDocument(v *appsecVersion) map[string]interface{} {
+	return map[string]interface{}{
+		"configId":     cfg.id,
+		"configName":   cfg.name,
+		"version":      v.version,
+		"versionNotes": "",
+		"createDate":   v.createDate,
+		"createdBy":    "apiserver",
+		"basedOn":      v.basedOn,
+		"production":   map[string]interface{}{"status": v.production},
+		"staging":      map[string]interface{}{"status": v.staging},
+	}
+}
+
+func (s *Server) appsecGetVersions(w http.ResponseWriter, _ *http.Request, p params) {
+	cfg := s.appsecConfig(w, p)
+	if cfg == nil {
+		return
+	}
+
+	list := make([]interface{}, 0, len(cfg.versions))
+	for number := cfg.lastVersion; number > 0; number-- {
+		if v, ok := cfg.versions[number]; ok {
+			list = append(list, cfg.versionDocument(v))
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"configId":           cfg.id,
+		"configName":         cfg.name,
+		"lastCreatedVersion": cfg.lastVersion,
+		"page":               1,
+		"pageSize":           len(list),
+		"totalSize":          len(list),
+		"versionList":        list,
+	})
+}
+
+func (s *Server) appsecGetVersion(w http.ResponseWriter, _ *http.Request, p params) {
+	cfg, v := s.appsecVersion(w, p)
+	if v == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cfg.versionDocument(v))
+}
+
+func (s *Server) appsecCloneVersion(w http.ResponseWriter, r *http.Request, p params) {
+	cfg := s.appsecConfig(w, p)
+	if cfg == nil {
+		return
+	}
+	var body appsec.CreateConfigurationCloneRequest
+	if !readJSON(w, r, &body) {
+		return
+	}
+	from, ok := cfg.versions[body.CreateFromVersion]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Version %d of configuration %d not found", body.CreateFromVersion, cfg.id)
+		return
+	}
+
+	cfg.lastVersion++
+	v := newAppSecVersion(cfg.lastVersion, from.version, s.timestamp())
+	for path, objects := range from.collections {
+		for id, obj := range objects {
+			v.collections[path][id] = copyDocument(obj).(map[string]interface{})
+		}
+	}
+	for key, doc := range from.documents {
+		v.documents[key] = copyDocument(doc)
+	}
+	cfg.versions[v.version] = v
+
+	writeJSON(w, http.StatusOK, cfg.versionDocument(v))
+}
+
+// copyDocument returns a deep copy of a decoded JSON document
+func copyDocument(doc interface{}) interface{} {
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			c[k] = copyDocument(v)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(d))
+		for i, v := range d {
+			c[i] = copyDocument(v)
+		}
+		return c
+	default:
+		return d
+	}
+}
+
+func (s *Server) appsecExportVersion(w http.ResponseWriter, _ *http.Request, p params) {
+	cfg, v := s.appsecVersion(w, p)
+	if v == nil {
+		return
+	}
+
+	export := cfg.versionDocument(v)
+	for _, c := range appsecCollections {
+		export[c.listKey] = sortedObjects(v.collections[c.path])
+	}
+	export["customRules"] = sortedObjects(cfg.customRules)
+	export["selectedHosts"] = []interface{}{}
+	if doc, ok := v.documents["selected-hostnames"].(map[string]interface{}); ok {
+		if hosts, ok := doc["hostnameList"].([]interface{}); ok {
+			selected := make([]interface{}, 0, len(hosts))
+			for _, h := range hosts {
+				if host, ok := h.(map[string]interface{}); ok {
+					selected = append(selected, host["hostname"])
+				}
+			}
+			export["selectedHosts"] = selected
+		}
+	}
+
+	writeJSON(w, http.StatusOK, export)
+}
+
+func (s *Server) appsecGetSelectableHostnames(w http.ResponseWriter, _ *http.Request, p params) {
+	cfg, v := s.appsecVersion(w, p)
+	if v == nil {
+		return
+	}
+
+	available := make([]map[string]interface{}, 0)
+	for _, hostname := range s.papi.activeHostnames() {
+		available = append(available, map[string]interface{}{
+			"activeInProduction":   true,
+			"activeInStaging":      true,
+			"arlInclusion":         false,
+			"hostname":             hostname,
+			"configIdInProduction": cfg.id,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"availableSet": available, "protect": true, "errorSet": []interface{}{}})
+}
+
+// documentKey returns the path of the document relative to the configuration version
+func documentKey(r *http.Request) string {
+	return strings.Join(splitPath(r.URL.Path)[6:], "/")
+}
+
+func (s *Server) appsecGetDocument(w http.ResponseWriter, r *http.Request, p params) {
+	_, v := s.appsecVersion(w, p)
+	if v == nil {
+		return
+	}
+
+	doc, ok := v.documents[documentKey(r)]
+	if !ok {
+		// the settings which were never changed are returned with their default values, which the stand-in leaves empty
+		doc = map[string]interface{}{}
+		if strings.HasSuffix(r.URL.Path, "/selected-hostnames") {
+			doc = map[string]interface{}{"hostnameList": []interface{}{}}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) appsecPutDocument(w http.ResponseWriter, r *http.Request, p params) {
+	_, v := s.appsecVersion(w, p)
+	if v == nil || !v.editable(w) {
+		return
+	}
+	var doc interface{}
+	if !readJSON(w, r, &doc) {
+		return
+	}
+
+	v.documents[documentKey(r)] = doc
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) appsecRemoveDocument(w http.ResponseWriter, r *http.Request, p params) {
+	_, v := s.appsecVersion(w, p)
+	if v == nil || !v.editable(w) {
+		return
+	}
+
+	delete(v.documents, documentKey(r))
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// appsecCollection returns the collection of the path, it is the 7th segment of the version object paths
+func appsecCollection(r *http.Request) (string, string, string) {
+	path := splitPath(r.URL.Path)[6]
+	for _, c := range appsecCollections {
+		if c.path == path {
+			return c.path, c.idField, c.listKey
+		}
+	}
+
+	return path, "id", path
+}
+
+func (s *Server) appsecListObjects(w http.ResponseWriter, r *http.Request, p params) {
+	_, v := s.appsecVersion(w, p)
+	if v == nil {
+		return
+	}
+	path, _, listKey := appsecCollection(r)
+	objects := sortedObjects(v.collections[path])
+
+	if path == "match-targets" {
+		website, api := make([]interface{}, 0), make([]interface{}, 0)
+		for _, obj := range objects {
+			if t, _ := obj.(map[string]interface{})["type"].(string); t == "api" {
+				api = append(api, obj)
+				continue
+			}
+			website = append(website, obj)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{listKey: map[string]interface{}{"websiteTargets": website, "apiTargets": api}})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"configId": p["configId"], "version": v.version, listKey: objects})
+}
+
+func (s *Server) appsecCreateObject(w http.ResponseWriter, r *http.Request, p params) {
+	_, v := s.appsecVersion(w, p)
+	if v == nil || !v.editable(w) {
+		return
+	}
+	var obj map[string]interface{}
+	if !readJSON(w, r, &obj) {
+		return
+	}
+	if obj == nil {
+		obj = make(map[string]interface{})
+	}
+	path, idField, _ := appsecCollection(r)
+
+	var id string
+	switch path {
+	case "security-policies":
+		prefix, _ := obj["policyPrefix"].(string)
+		if len(prefix) != 4 {
+			writeError(w, http.StatusBadRequest, "The policy prefix must be 4 characters long")
+			return
+		}
+		id = fmt.Sprintf("%s_%d", prefix, s.nextID())
+		obj[idField] = id
+		delete(obj, "policyPrefix")
+	default:
+		n := s.nextID()
+		id = strconv.Itoa(n)
+		obj[idField] = n
+	}
+	obj["configId"], _ = strconv.Atoi(p["configId"])
+	obj["configVersion"] = v.version
+	v.collections[path][id] = obj
+
+	status := http.StatusCreated
+	if path == "security-policies" {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, obj)
+}
+
+func (s *Server) appsecObject(w http.ResponseWriter, r *http.Request, p params) (*appsecVersion, string, map[string]interface{}) {
+	_, v := s.appsecVersion(w, p)
+	if v == nil {
+		return nil, "", nil
+	}
+	path, _, _ := appsecCollection(r)
+	obj, ok := v.collections[path][p["id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "%s %s not found", path, p["id"])
+		return nil, "", nil
+	}
+
+	return v, path, obj
+}
+
+func (s *Server) appsecGetObject(w http.ResponseWriter, r *http.Request, p params) {
+	if _, _, obj := s.appsecObject(w, r, p); obj != nil {
+		writeJSON(w, http.StatusOK, obj)
+	}
+}
+
+func (s *Server) appsecUpdateObject(w http.ResponseWriter, r *http.Request, p params) {
+	v, path, current := s.appsecObject(w, r, p)
+	if current == nil || !v.editable(w) {
+		return
+	}
+	var obj map[string]interface{}
+	if !readJSON(w, r, &obj) {
+		return
+	}
+	if obj == nil {
+		obj = make(map[string]interface{})
+	}
+	_, idField, _ := appsecCollection(r)
+
+	obj[idField] = current[idField]
+	obj["configId"] = current["configId"]
+	obj["configVersion"] = current["configVersion"]
+	v.collections[path][p["id"]] = obj
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) appsecRemoveObject(w http.ResponseWriter, r *http.Request, p params) {
+	v, path, obj := s.appsecObject(w, r, p)
+	if obj == nil || !v.editable(w) {
+		return
+	}
+
+	delete(v.collections[path], p["id"])
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) appsecGetCustomRules(w http.ResponseWriter, _ *http.Request, p params) {
+	cfg := s.appsecConfig(w, p)
+	if cfg == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"customRules": sortedObjects(cfg.customRules)})
+}
+
+func (s *Server) appsecCreateCustomRule(w http.ResponseWriter, r *http.Request, p params) {
+	cfg := s.appsecConfig(w, p)
+	if cfg == nil {
+		return
+	}
+	var rule map[string]interface{}
+	if !readJSON(w, r, &rule) {
+		return
+	}
+	if name, _ := rule["name"].(string); name == "" {
+		writeError(w, http.StatusBadRequest, "The custom rule name is required")
+		return
+	}
+
+	id := s.nextID()
+	rule["id"] = id
+	cfg.customRules[strconv.Itoa(id)] = rule
+
+	writeJSON(w, http.StatusCreated, rule)
+}
+
+func (s *Server) customRule(w http.ResponseWriter, p params) (*appsecConfig, map[string]interface{}) {
+	cfg := s.appsecConfig(w, p)
+	if cfg == nil {
+		return nil, nil
+	}
+	rule, ok := cfg.customRules[p["id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Custom rule %s not found", p["id"])
+		return nil, nil
+	}
+
+	return cfg, rule
+}
+
+func (s *Server) appsecGetCustomRule(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, rule := s.customRule(w, p); rule != nil {
+		writeJSON(w, http.StatusOK, rule)
+	}
+}
+
+func (s *Server) appsecUpdateCustomRule(w http.ResponseWriter, r *http.Request, p params) {
+	cfg, current := s.customRule(w, p)
+	if current == nil {
+		return
+	}
+	var rule map[string]interface{}
+	if !readJSON(w, r, &rule) {
+		return
+	}
+	if rule == nil {
+		rule = make(map[string]interface{})
+	}
+
+	rule["id"] = current["id"]
+	cfg.customRules[p["id"]] = rule
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) appsecRemoveCustomRule(w http.ResponseWriter, _ *http.Request, p params) {
+	cfg, rule := s.customRule(w, p)
+	if rule == nil {
+		return
+	}
+
+	delete(cfg.customRules, p["id"])
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) appsecCreateActivation(w http.ResponseWriter, r *http.Request, _ params) {
+	var body appsec.CreateActivationsRequest
+	if !readJSON(w, r, &body) {
+		return
+	}
+	network := appsec.NetworkValue(strings.ToUpper(body.Network))
+	if network != appsec.NetworkStaging && network != appsec.NetworkProduction {
+		writeError(w, http.StatusBadRequest, "Unknown network %s", body.Network)
+		return
+	}
+	action := strings.ToUpper(body.Action)
+	if action != string(appsec.ActivationTypeActivate) && action != string(appsec.ActivationTypeDeactivate) {
+		writeError(w, http.StatusBadRequest, "Unknown action %s", body.Action)
+		return
+	}
+	if len(body.ActivationConfigs) != 1 {
+		writeError(w, http.StatusBadRequest, "Exactly one configuration can be activated at a time")
+		return
+	}
+	cfg, ok := s.appsec.configs[body.ActivationConfigs[0].ConfigID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Configuration %d not found", body.ActivationConfigs[0].ConfigID)
+		return
+	}
+	if _, ok := cfg.versions[body.ActivationConfigs[0].ConfigVersion]; !ok {
+		writeError(w, http.StatusNotFound, "Version %d of configuration %d not found", body.ActivationConfigs[0].ConfigVersion, cfg.id)
+		return
+	}
+
+	a := &appsecActivation{configVersion: body.ActivationConfigs[0].ConfigVersion, pending: s.newPending()}
+	a.ActivationID = s.nextID()
+	a.Action = action
+	a.Network = network
+	a.Status = appsec.StatusPending
+	a.CreatedBy = "apiserver"
+	a.CreateDate = s.now().UTC()
+	a.ActivationConfigs = append(a.ActivationConfigs, struct {
+		ConfigID              int    `json:"configId"`
+		ConfigName            string `json:"configName"`
+		ConfigVersion         int    `json:"configVersion"`
+		PreviousConfigVersion int    `json:"previousConfigVersion"`
+	}{ConfigID: cfg.id, ConfigName: cfg.name, ConfigVersion: a.configVersion})
+	s.appsec.activations[a.ActivationID] = a
+	if a.pending.done() {
+		s.completeAppSecActivation(cfg, a)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"activationId": a.ActivationID, "action": a.Action, "status": a.Status})
+}
+
+func (s *Server) completeAppSecActivation(cfg *appsecConfig, a *appsecActivation) {
+	a.pending = nil
+	v := cfg.versions[a.configVersion]
+	status := "Active"
+	a.Status = appsec.StatusActive
+	if a.Action == string(appsec.ActivationTypeDeactivate) {
+		status = "Deactivated"
+		a.Status = appsec.StatusDeactivated
+	}
+
+	if a.Network == appsec.NetworkStaging {
+		if previous, ok := cfg.versions[cfg.stagingVersion]; ok && previous != v {
+			previous.staging = "Deactivated"
+		}
+		v.staging = status
+		cfg.stagingVersion = v.version
+		if status == "Deactivated" {
+			cfg.stagingVersion = 0
+		}
+		return
+	}
+	if previous, ok := cfg.versions[cfg.productionVersion]; ok && previous != v {
+		previous.production = "Deactivated"
+	}
+	v.production = status
+	cfg.productionVersion = v.version
+	if status == "Deactivated" {
+		cfg.productionVersion = 0
+	}
+}
+
+func (s *Server) appsecGetActivation(w http.ResponseWriter, _ *http.Request, p params) {
+	id, err := strconv.Atoi(p["activationId"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid activation id %s", p["activationId"])
+		return
+	}
+	a, ok := s.appsec.activations[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Activation %d not found", id)
+		return
+	}
+	if a.pending != nil && a.pending.poll() {
+		s.completeAppSecActivation(s.appsec.configs[a.ActivationConfigs[0].ConfigID], a)
+	}
+
+	writeJSON(w, http.StatusOK, a.GetActivationsResponse)
+}
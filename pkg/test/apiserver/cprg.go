@@ -0,0 +1,214 @@
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type (
+	cprgState struct {
+		reportingGroups map[int]*cprgReportingGroup
+	}
+
+	// cprgCPCode is the CPRG view of a PAPI CP code, only the name can be changed
+	cprgCPCode struct {
+		CPCodeID   int            `json:"cpcodeId"`
+		CPCodeName string         `json:"cpcodeName"`
+		Purgeable  bool           `json:"purgeable"`
+		Contracts  []cprgContract `json:"contracts"`
+		Products   []cprgProduct  `json:"products"`
+	}
+
+	cprgContract struct {
+		ContractID string `json:"contractId"`
+		Status     string `json:"status"`
+	}
+
+	cprgProduct struct {
+		ProductID string `json:"productId"`
+	}
+
+	cprgReportingGroup struct {
+		ReportingGroupID   int                          `json:"reportingGroupId"`
+		ReportingGroupName string                       `json:"reportingGroupName"`
+		Contracts          []cprgReportingGroupContract `json:"contracts"`
+		AccessGroup        cprgAccessGroup              `json:"accessGroup"`
+	}
+
+	cprgReportingGroupContract struct {
+		ContractID string                     `json:"contractId"`
+		CPCodes    []cprgReportingGroupCPCode `json:"cpcodes"`
+	}
+
+	cprgReportingGroupCPCode struct {
+		CPCodeID   int    `json:"cpcodeId"`
+		CPCodeName string `json:"cpcodeName"`
+	}
+
+	cprgAccessGroup struct {
+		GroupID    int    `json:"groupId"`
+		ContractID string `json:"contractId"`
+	}
+)
+
+func newCPRGState() *cprgState {
+	return &cprgState{
+		reportingGroups: make(map[int]*cprgReportingGroup),
+	}
+}
+
+func (s *Server) registerCPRG() {
+	rt := s.router
+	rt.handle(http.MethodGet, "/cprg/v1/cpcodes/{cpcodeId}", s.cprgGetCPCode)
+	rt.handle(http.MethodPut, "/cprg/v1/cpcodes/{cpcodeId}", s.cprgUpdateCPCode)
+	rt.handle(http.MethodPost, "/cprg/v1/reporting-groups", s.cprgCreateReportingGroup)
+	rt.handle(http.MethodGet, "/cprg/v1/reporting-groups/{reportingGroupId}", s.cprgGetReportingGroup)
+	rt.handle(http.MethodPut, "/cprg/v1/reporting-groups/{reportingGroupId}", s.cprgUpdateReportingGroup)
+	rt.handle(http.MethodDelete, "/cprg/v1/reporting-groups/{reportingGroupId}", s.cprgDeleteReportingGroup)
+}
+
+// cprgFindCPCode returns the PAPI CP code with the CPRG number, or nil
+func (s *Server) cprgFindCPCode(id int) *cpCode {
+	for _, c := range s.papi.cpCodes {
+		if c.ID == fmt.Sprintf("cpc_%d", id) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// cprgCPCode returns the CP code from the path, it writes a not found error when it does not exist
+func (s *Server) cprgCPCode(w http.ResponseWriter, p params) *cpCode {
+	id, _ := strconv.Atoi(p["cpcodeId"])
+	if c := s.cprgFindCPCode(id); c != nil {
+		return c
+	}
+	writeError(w, http.StatusNotFound, "CP code %s not found", p["cpcodeId"])
+
+	return nil
+}
+
+func (c *cpCode) cprg() cprgCPCode {
+	id, _ := strconv.Atoi(strings.TrimPrefix(c.ID, "cpc_"))
+	rval := cprgCPCode{
+		CPCodeID:   id,
+		CPCodeName: c.Name,
+		Purgeable:  true,
+		Contracts:  []cprgContract{{ContractID: strings.TrimPrefix(c.contractID, "ctr_"), Status: "ongoing"}},
+	}
+	for _, product := range c.ProductIDs {
+		rval.Products = append(rval.Products, cprgProduct{ProductID: strings.TrimPrefix(product, "prd_")})
+	}
+
+	return rval
+}
+
+func (s *Server) cprgGetCPCode(w http.ResponseWriter, _ *http.Request, p params) {
+	c := s.cprgCPCode(w, p)
+	if c == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.cprg())
+}
+
+func (s *Server) cprgUpdateCPCode(w http.ResponseWriter, r *http.Request, p params) {
+	c := s.cprgCPCode(w, p)
+	if c == nil {
+		return
+	}
+	var body cprgCPCode
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.CPCodeName == "" {
+		writeError(w, http.StatusBadRequest, "A cpcodeName is required")
+		return
+	}
+
+	c.Name = body.CPCodeName
+	writeJSON(w, http.StatusOK, c.cprg())
+}
+
+// cprgReportingGroup returns the reporting group from the path, it writes a not found error when it does not exist
+func (s *Server) cprgReportingGroup(w http.ResponseWriter, p params) *cprgReportingGroup {
+	id, _ := strconv.Atoi(p["reportingGroupId"])
+	if g, ok := s.cprg.reportingGroups[id]; ok {
+		return g
+	}
+	writeError(w, http.StatusNotFound, "Reporting group %s not found", p["reportingGroupId"])
+
+	return nil
+}
+
+// readReportingGroup decodes the reporting group of the request and checks its CP codes exist, it fills their names
+func (s *Server) readReportingGroup(w http.ResponseWriter, r *http.Request) (*cprgReportingGroup, bool) {
+	var body cprgReportingGroup
+	if !readJSON(w, r, &body) {
+		return nil, false
+	}
+	if body.ReportingGroupName == "" || len(body.Contracts) == 0 {
+		writeError(w, http.StatusBadRequest, "A reportingGroupName and contracts are required")
+		return nil, false
+	}
+	for i := range body.Contracts {
+		for j, member := range body.Contracts[i].CPCodes {
+			c := s.cprgFindCPCode(member.CPCodeID)
+			if c == nil {
+				writeError(w, http.StatusBadRequest, "CP code %d not found", member.CPCodeID)
+				return nil, false
+			}
+			body.Contracts[i].CPCodes[j].CPCodeName = c.Name
+		}
+	}
+
+	return &body, true
+}
+
+func (s *Server) cprgCreateReportingGroup(w http.ResponseWriter, r *http.Request, _ params) {
+	g, ok := s.readReportingGroup(w, r)
+	if !ok {
+		return
+	}
+
+	g.ReportingGroupID = s.nextID()
+	s.cprg.reportingGroups[g.ReportingGroupID] = g
+	writeJSON(w, http.StatusCreated, g)
+}
+
+func (s *Server) cprgGetReportingGroup(w http.ResponseWriter, _ *http.Request, p params) {
+	g := s.cprgReportingGroup(w, p)
+	if g == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, g)
+}
+
+func (s *Server) cprgUpdateReportingGroup(w http.ResponseWriter, r *http.Request, p params) {
+	g := s.cprgReportingGroup(w, p)
+	if g == nil {
+		return
+	}
+	body, ok := s.readReportingGroup(w, r)
+	if !ok {
+		return
+	}
+
+	g.ReportingGroupName = body.ReportingGroupName
+	g.Contracts = body.Contracts
+	writeJSON(w, http.StatusOK, g)
+}
+
+func (s *Server) cprgDeleteReportingGroup(w http.ResponseWriter, _ *http.Request, p params) {
+	g := s.cprgReportingGroup(w, p)
+	if g == nil {
+		return
+	}
+
+	delete(s.cprg.reportingGroups, g.ReportingGroupID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,519 @@
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	dns "github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/configdns"
+)
+
+var nameServers = []string{"a1-1.akam.net.", "a2-2.akam.net.", "a3-3.akam.net.", "a4-4.akam.net.", "a5-5.akam.net.", "a6-6.akam.net."}
+
+type (
+	dnsState struct {
+		zones map[string]*zone
+	}
+
+	zone struct {
+		dns.ZoneResponse
+		records    map[string]*dns.RecordBody
+		changelist bool
+		pending    *pending
+	}
+)
+
+func newDNSState() *dnsState {
+	return &dnsState{
+		zones: make(map[string]*zone),
+	}
+}
+
+func (s *Server) registerDNS() {
+	rt := s.router
+	rt.handle(http.MethodGet, "/config-dns/v2/data/authorities", s.dnsGetAuthorities)
+	rt.handle(http.MethodGet, "/config-dns/v2/zones", s.dnsListZones)
+	rt.handle(http.MethodPost, "/config-dns/v2/zones", s.dnsCreateZone)
+	rt.handle(http.MethodGet, "/config-dns/v2/zones/{zone}", s.dnsGetZone)
+	rt.handle(http.MethodPut, "/config-dns/v2/zones/{zone}", s.dnsUpdateZone)
+	rt.handle(http.MethodDelete, "/config-dns/v2/zones/{zone}", s.dnsDeleteZone)
+	rt.handle(http.MethodPost, "/config-dns/v2/changelists", s.dnsSaveChangelist)
+	rt.handle(http.MethodGet, "/config-dns/v2/changelists/{zone}", s.dnsGetChangelist)
+	rt.handle(http.MethodPost, "/config-dns/v2/changelists/{zone}/submit", s.dnsSubmitChangelist)
+	rt.handle(http.MethodGet, "/config-dns/v2/zones/{zone}/recordsets", s.dnsGetRecordsets)
+	rt.handle(http.MethodGet, "/config-dns/v2/zones/{zone}/names", s.dnsGetNames)
+	rt.handle(http.MethodGet, "/config-dns/v2/zones/{zone}/names/{name}/types", s.dnsGetNameTypes)
+	rt.handle(http.MethodGet, "/config-dns/v2/zones/{zone}/names/{name}/types/{type}", s.dnsGetRecord)
+	rt.handle(http.MethodPost, "/config-dns/v2/zones/{zone}/names/{name}/types/{type}", s.dnsCreateRecord)
+	rt.handle(http.MethodPut, "/config-dns/v2/zones/{zone}/names/{name}/types/{type}", s.dnsUpdateRecord)
+	rt.handle(http.MethodDelete, "/config-dns/v2/zones/{zone}/names/{name}/types/{type}", s.dnsDeleteRecord)
+}
+
+func recordKey(name, recordType string) string {
+	return strings.ToLower(name) + "/" + strings.ToUpper(recordType)
+}
+
+// zone returns the zone from the path, it writes a not found error when it does not exist
+func (s *Server) zone(w http.ResponseWriter, name string) *zone {
+	z, ok := s.dns.zones[strings.ToLower(name)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Zone %s not found", name)
+		return nil
+	}
+
+	return z
+}
+
+// touch records a change of the zone, it is propagated after a number of status reads
+func (s *Server) touch(z *zone) {
+	z.VersionId = fmt.Sprintf("%08x-0000-4000-8000-%012x", s.nextID(), s.nextID())
+	z.LastModifiedDate = s.timestamp()
+	z.LastModifiedBy = "apiserver"
+	z.ActivationState = "PENDING"
+	z.pending = s.newPending()
+	if z.pending.done() {
+		z.ActivationState = "ACTIVE"
+		z.LastActivationDate = s.timestamp()
+		z.pending = nil
+	}
+}
+
+// bumpSerial increments the serial of the zone SOA record, the real API does it for every record change
+func bumpSerial(z *zone) {
+	soa, ok := z.records[recordKey(z.Zone, "SOA")]
+	if !ok || len(soa.Target) == 0 {
+		return
+	}
+	fields := strings.Fields(soa.Target[0])
+	if len(fields) != 7 {
+		return
+	}
+	serial, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return
+	}
+	fields[2] = strconv.Itoa(serial + 1)
+	soa.Target[0] = strings.Join(fields, " ")
+}
+
+func soaSerial(rec *dns.RecordBody) int {
+	if len(rec.Target) == 0 {
+		return 0
+	}
+	fields := strings.Fields(rec.Target[0])
+	if len(fields) != 7 {
+		return 0
+	}
+	serial, _ := strconv.Atoi(fields[2])
+
+	return serial
+}
+
+func (s *Server) dnsGetAuthorities(w http.ResponseWriter, r *http.Request, _ params) {
+	contracts := strings.Split(r.URL.Query().Get("contractIds"), ",")
+	resp := dns.AuthorityResponse{Contracts: make([]dns.Contract, 0, len(contracts))}
+	for _, c := range contracts {
+		if !s.papi.hasContract(c) {
+			writeError(w, http.StatusNotFound, "Contract %s not found", c)
+			return
+		}
+		resp.Contracts = append(resp.Contracts, dns.Contract{ContractID: strings.TrimPrefix(c, "ctr_"), Authorities: nameServers})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) dnsListZones(w http.ResponseWriter, _ *http.Request, _ params) {
+	names := make([]string, 0, len(s.dns.zones))
+	for name := range s.dns.zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zones := make([]*dns.ZoneResponse, 0, len(names))
+	for _, name := range names {
+		z := s.dns.zones[name].ZoneResponse
+		zones = append(zones, &z)
+	}
+	writeJSON(w, http.StatusOK, dns.ZoneListResponse{
+		Metadata: &dns.ListMetadata{
+			ContractIDs:   []string{strings.TrimPrefix(ContractID, "ctr_")},
+			Page:          1,
+			PageSize:      len(zones),
+			ShowAll:       true,
+			TotalElements: len(zones),
+		},
+		Zones: zones,
+	})
+}
+
+func (s *Server) dnsCreateZone(w http.ResponseWriter, r *http.Request, _ params) {
+	contractID := r.URL.Query().Get("contractId")
+	if !s.papi.hasContract(contractID) {
+		writeError(w, http.StatusBadRequest, "Contract %s not found", contractID)
+		return
+	}
+	if gid := r.URL.Query().Get("gid"); gid != "" && s.papi.group(gid) == nil {
+		writeError(w, http.StatusBadRequest, "Group %s not found", gid)
+		return
+	}
+	var body dns.ZoneCreate
+	if !readJSON(w, r, &body) {
+		return
+	}
+	name := strings.ToLower(strings.TrimSuffix(body.Zone, "."))
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "The zone name is required")
+		return
+	}
+	switch strings.ToUpper(body.Type) {
+	case "PRIMARY", "ALIAS":
+	case "SECONDARY":
+		if len(body.Masters) == 0 {
+			writeError(w, http.StatusBadRequest, "Secondary zones require masters")
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "Unsupported zone type %s", body.Type)
+		return
+	}
+	if _, ok := s.dns.zones[name]; ok {
+		writeError(w, http.StatusConflict, "Zone %s already exists", name)
+		return
+	}
+
+	z := &zone{
+		ZoneResponse: dns.ZoneResponse{
+			Zone:                  name,
+			Type:                  strings.ToUpper(body.Type),
+			Masters:               body.Masters,
+			Comment:               body.Comment,
+			SignAndServe:          body.SignAndServe,
+			SignAndServeAlgorithm: body.SignAndServeAlgorithm,
+			TsigKey:               body.TsigKey,
+			Target:                body.Target,
+			EndCustomerID:         body.EndCustomerID,
+			ContractID:            strings.TrimPrefix(contractID, "ctr_"),
+		},
+		records: make(map[string]*dns.RecordBody),
+	}
+	s.touch(z)
+	if z.Type == "PRIMARY" {
+		// primary zones are activated by the first changelist, which creates the SOA and NS records
+		z.ActivationState = "NEW"
+		z.pending = nil
+	}
+	s.dns.zones[name] = z
+
+	writeJSON(w, http.StatusCreated, z.ZoneResponse)
+}
+
+func (s *Server) dnsGetZone(w http.ResponseWriter, _ *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+	if z.pending != nil && z.pending.poll() {
+		z.ActivationState = "ACTIVE"
+		z.LastActivationDate = s.timestamp()
+		z.pending = nil
+	}
+
+	writeJSON(w, http.StatusOK, z.ZoneResponse)
+}
+
+func (s *Server) dnsUpdateZone(w http.ResponseWriter, r *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+	var body dns.ZoneCreate
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if !strings.EqualFold(body.Type, z.Type) {
+		writeError(w, http.StatusBadRequest, "The type of zone %s cannot be changed", z.Zone)
+		return
+	}
+
+	z.Masters = body.Masters
+	z.Comment = body.Comment
+	z.SignAndServe = body.SignAndServe
+	z.SignAndServeAlgorithm = body.SignAndServeAlgorithm
+	z.TsigKey = body.TsigKey
+	z.Target = body.Target
+	z.EndCustomerID = body.EndCustomerID
+	s.touch(z)
+
+	writeJSON(w, http.StatusOK, z.ZoneResponse)
+}
+
+func (s *Server) dnsDeleteZone(w http.ResponseWriter, _ *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+
+	delete(s.dns.zones, z.Zone)
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) dnsSaveChangelist(w http.ResponseWriter, r *http.Request, _ params) {
+	z := s.zone(w, r.URL.Query().Get("zone"))
+	if z == nil {
+		return
+	}
+	if z.changelist {
+		writeError(w, http.StatusConflict, "A changelist already exists for zone %s", z.Zone)
+		return
+	}
+
+	z.changelist = true
+	writeJSON(w, http.StatusCreated, nil)
+}
+
+func (s *Server) dnsGetChangelist(w http.ResponseWriter, _ *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+	if !z.changelist {
+		writeError(w, http.StatusNotFound, "No changelist exists for zone %s", z.Zone)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dns.ChangeListResponse{
+		Zone:             z.Zone,
+		ChangeTag:        z.VersionId,
+		ZoneVersionID:    z.VersionId,
+		LastModifiedDate: z.LastModifiedDate,
+	})
+}
+
+func (s *Server) dnsSubmitChangelist(w http.ResponseWriter, _ *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+	if !z.changelist {
+		writeError(w, http.StatusNotFound, "No changelist exists for zone %s", z.Zone)
+		return
+	}
+
+	z.changelist = false
+	if _, ok := z.records[recordKey(z.Zone, "SOA")]; !ok {
+		z.records[recordKey(z.Zone, "SOA")] = &dns.RecordBody{
+			Name:       z.Zone,
+			RecordType: "SOA",
+			TTL:        86400,
+			Target:     []string{fmt.Sprintf("%s hostmaster.%s. 1 3600 600 604800 300", nameServers[0], z.Zone)},
+		}
+	}
+	if _, ok := z.records[recordKey(z.Zone, "NS")]; !ok {
+		z.records[recordKey(z.Zone, "NS")] = &dns.RecordBody{
+			Name:       z.Zone,
+			RecordType: "NS",
+			TTL:        86400,
+			Target:     append([]string{}, nameServers...),
+		}
+	}
+	s.touch(z)
+
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) sortedRecords(z *zone) []*dns.RecordBody {
+	keys := make([]string, 0, len(z.records))
+	for k := range z.records {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	records := make([]*dns.RecordBody, 0, len(keys))
+	for _, k := range keys {
+		records = append(records, z.records[k])
+	}
+
+	return records
+}
+
+func (s *Server) dnsGetRecordsets(w http.ResponseWriter, r *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+
+	types := make(map[string]bool)
+	for _, t := range strings.Split(r.URL.Query().Get("types"), ",") {
+		if t != "" {
+			types[strings.ToUpper(t)] = true
+		}
+	}
+	sets := make([]dns.Recordset, 0)
+	for _, rec := range s.sortedRecords(z) {
+		if len(types) > 0 && !types[rec.RecordType] {
+			continue
+		}
+		sets = append(sets, dns.Recordset{Name: rec.Name, Type: rec.RecordType, TTL: rec.TTL, Rdata: rec.Target})
+	}
+
+	writeJSON(w, http.StatusOK, dns.RecordSetResponse{
+		Metadata: dns.MetadataH{
+			LastPage:      1,
+			Page:          1,
+			PageSize:      len(sets),
+			ShowAll:       true,
+			TotalElements: len(sets),
+		},
+		Recordsets: sets,
+	})
+}
+
+func (s *Server) dnsGetNames(w http.ResponseWriter, _ *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, rec := range s.sortedRecords(z) {
+		if !seen[rec.Name] {
+			seen[rec.Name] = true
+			names = append(names, rec.Name)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, dns.ZoneNamesResponse{Names: names})
+}
+
+func (s *Server) dnsGetNameTypes(w http.ResponseWriter, _ *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+
+	types := make([]string, 0)
+	for _, rec := range s.sortedRecords(z) {
+		if strings.EqualFold(rec.Name, p["name"]) {
+			types = append(types, rec.RecordType)
+		}
+	}
+	if len(types) == 0 {
+		writeError(w, http.StatusNotFound, "Name %s not found in zone %s", p["name"], z.Zone)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dns.ZoneNameTypesResponse{Types: types})
+}
+
+func (s *Server) dnsGetRecord(w http.ResponseWriter, _ *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+	rec, ok := z.records[recordKey(p["name"], p["type"])]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record %s %s not found in zone %s", p["name"], p["type"], z.Zone)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rec)
+}
+
+// recordBody reads and validates a record body, the name and type have to match the path
+func (s *Server) recordBody(w http.ResponseWriter, r *http.Request, z *zone, p params) *dns.RecordBody {
+	var body dns.RecordBody
+	if !readJSON(w, r, &body) {
+		return nil
+	}
+	if !strings.EqualFold(body.Name, p["name"]) || !strings.EqualFold(body.RecordType, p["type"]) {
+		writeError(w, http.StatusBadRequest, "The record name and type must match the path")
+		return nil
+	}
+	if name := strings.ToLower(body.Name); name != z.Zone && !strings.HasSuffix(name, "."+z.Zone) {
+		writeError(w, http.StatusBadRequest, "Record %s does not belong to zone %s", body.Name, z.Zone)
+		return nil
+	}
+	if body.TTL <= 0 || len(body.Target) == 0 {
+		writeError(w, http.StatusBadRequest, "The record ttl and rdata are required")
+		return nil
+	}
+	body.RecordType = strings.ToUpper(body.RecordType)
+	body.Active = true
+
+	return &body
+}
+
+func (s *Server) dnsCreateRecord(w http.ResponseWriter, r *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+	rec := s.recordBody(w, r, z, p)
+	if rec == nil {
+		return
+	}
+	key := recordKey(rec.Name, rec.RecordType)
+	if _, ok := z.records[key]; ok {
+		writeError(w, http.StatusConflict, "Record %s %s already exists in zone %s", rec.Name, rec.RecordType, z.Zone)
+		return
+	}
+
+	z.records[key] = rec
+	if rec.RecordType != "SOA" {
+		bumpSerial(z)
+	}
+	s.touch(z)
+
+	writeJSON(w, http.StatusCreated, rec)
+}
+
+func (s *Server) dnsUpdateRecord(w http.ResponseWriter, r *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+	rec := s.recordBody(w, r, z, p)
+	if rec == nil {
+		return
+	}
+	key := recordKey(rec.Name, rec.RecordType)
+	current, ok := z.records[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record %s %s not found in zone %s", rec.Name, rec.RecordType, z.Zone)
+		return
+	}
+	if rec.RecordType == "SOA" && soaSerial(rec) <= soaSerial(current) {
+		writeError(w, http.StatusBadRequest, "SOA serial number must be incremented")
+		return
+	}
+
+	z.records[key] = rec
+	if rec.RecordType != "SOA" {
+		bumpSerial(z)
+	}
+	s.touch(z)
+
+	writeJSON(w, http.StatusOK, rec)
+}
+
+func (s *Server) dnsDeleteRecord(w http.ResponseWriter, _ *http.Request, p params) {
+	z := s.zone(w, p["zone"])
+	if z == nil {
+		return
+	}
+	key := recordKey(p["name"], p["type"])
+	if _, ok := z.records[key]; !ok {
+		writeError(w, http.StatusNotFound, "Record %s %s not found in zone %s", p["name"], p["type"], z.Zone)
+		return
+	}
+
+	delete(z.records, key)
+	bumpSerial(z)
+	s.touch(z)
+
+	writeJSON(w, http.StatusNoContent, nil)
+}
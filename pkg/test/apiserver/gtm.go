@@ -0,0 +1,493 @@
+package apiserver
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/configgtm"
+)
+
+// gtmCollections maps the path of the named domain objects to their key in the domain document
+var gtmCollections = map[string]string{
+	"properties":      "properties",
+	"resources":       "resources",
+	"geographic-maps": "geographicMaps",
+	"cidr-maps":       "cidrMaps",
+	"as-maps":         "asMaps",
+}
+
+// gtmDefaultDatacenters maps the default datacenter paths to their fixed ids
+var gtmDefaultDatacenters = map[string]int{
+	"default-datacenter-for-maps":             gtm.MapDefaultDC,
+	"datacenter-for-ip-version-selector-ipv4": gtm.Ipv4DefaultDC,
+	"datacenter-for-ip-version-selector-ipv6": gtm.Ipv6DefaultDC,
+}
+
+const gtmFirstDatacenterID = 3131
+
+type (
+	gtmState struct {
+		domains map[string]*gtmDomain
+	}
+
+	// gtmDomain keeps the documents sent by the client as is, the stand-in does not validate the GTM objects
+	gtmDomain struct {
+		doc              map[string]interface{}
+		contractID       string
+		groupID          string
+		datacenters      map[int]map[string]interface{}
+		lastDatacenterID int
+		objects          map[string]map[string]map[string]interface{}
+		status           gtm.ResponseStatus
+		pending          *pending
+	}
+)
+
+func newGTMState() *gtmState {
+	return &gtmState{
+		domains: make(map[string]*gtmDomain),
+	}
+}
+
+func (s *Server) registerGTM() {
+	rt := s.router
+	rt.handle(http.MethodGet, "/config-gtm/v1/domains", s.gtmListDomains)
+	rt.handle(http.MethodPost, "/config-gtm/v1/domains", s.gtmCreateDomain)
+	rt.handle(http.MethodGet, "/config-gtm/v1/domains/{domain}", s.gtmGetDomain)
+	rt.handle(http.MethodPut, "/config-gtm/v1/domains/{domain}", s.gtmUpdateDomain)
+	rt.handle(http.MethodDelete, "/config-gtm/v1/domains/{domain}", s.gtmDeleteDomain)
+	rt.handle(http.MethodGet, "/config-gtm/v1/domains/{domain}/status/current", s.gtmGetDomainStatus)
+	rt.handle(http.MethodGet, "/config-gtm/v1/domains/{domain}/datacenters", s.gtmListDatacenters)
+	rt.handle(http.MethodPost, "/config-gtm/v1/domains/{domain}/datacenters", s.gtmCreateDatacenter)
+	for path := range gtmDefaultDatacenters {
+		rt.handle(http.MethodPost, "/config-gtm/v1/domains/{domain}/datacenters/"+path, s.gtmCreateDefaultDatacenter)
+	}
+	rt.handle(http.MethodGet, "/config-gtm/v1/domains/{domain}/datacenters/{datacenterId}", s.gtmGetDatacenter)
+	rt.handle(http.MethodPut, "/config-gtm/v1/domains/{domain}/datacenters/{datacenterId}", s.gtmUpdateDatacenter)
+	rt.handle(http.MethodDelete, "/config-gtm/v1/domains/{domain}/datacenters/{datacenterId}", s.gtmDeleteDatacenter)
+	rt.handle(http.MethodGet, "/config-gtm/v1/domains/{domain}/{collection}", s.gtmListObjects)
+	rt.handle(http.MethodGet, "/config-gtm/v1/domains/{domain}/{collection}/{name}", s.gtmGetObject)
+	rt.handle(http.MethodPut, "/config-gtm/v1/domains/{domain}/{collection}/{name}", s.gtmPutObject)
+	rt.handle(http.MethodDelete, "/config-gtm/v1/domains/{domain}/{collection}/{name}", s.gtmDeleteObject)
+}
+
+func (s *Server) gtmDomain(w http.ResponseWriter, name string) *gtmDomain {
+	d, ok := s.gtm.domains[strings.ToLower(name)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Domain %s not found", name)
+		return nil
+	}
+
+	return d
+}
+
+func (d *gtmDomain) name() string {
+	name, _ := d.doc["name"].(string)
+	return name
+}
+
+// change records a change of the domain, it is propagated after a number of status reads
+func (s *Server) change(d *gtmDomain) *gtm.ResponseStatus {
+	d.doc["lastModified"] = s.timestamp()
+	d.status = gtm.ResponseStatus{
+		ChangeId:              strconv.Itoa(s.nextID()),
+		Message:               "Change Pending",
+		PassingValidation:     true,
+		PropagationStatus:     "PENDING",
+		PropagationStatusDate: s.timestamp(),
+	}
+	d.pending = s.newPending()
+	if d.pending.done() {
+		d.complete(s.timestamp())
+	}
+	status := d.status
+
+	return &status
+}
+
+func (d *gtmDomain) complete(timestamp string) {
+	d.status.Message = "Current configuration has been propagated to all GTM nameservers"
+	d.status.PropagationStatus = "COMPLETE"
+	d.status.PropagationStatusDate = timestamp
+	d.pending = nil
+}
+
+// document returns the domain with all its objects as returned by GetDomain
+func (d *gtmDomain) document() map[string]interface{} {
+	doc := make(map[string]interface{}, len(d.doc)+len(gtmCollections)+2)
+	for k, v := range d.doc {
+		doc[k] = v
+	}
+
+	ids := make([]int, 0, len(d.datacenters))
+	for id := range d.datacenters {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	datacenters := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		datacenters = append(datacenters, d.datacenters[id])
+	}
+	doc["datacenters"] = datacenters
+
+	for collection, key := range gtmCollections {
+		doc[key] = sortedObjects(d.objects[collection])
+	}
+	doc["status"] = d.status
+
+	return doc
+}
+
+func sortedObjects(objects map[string]map[string]interface{}) []interface{} {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		items = append(items, objects[name])
+	}
+
+	return items
+}
+
+func (s *Server) gtmListDomains(w http.ResponseWriter, _ *http.Request, _ params) {
+	names := make([]string, 0, len(s.gtm.domains))
+	for name := range s.gtm.domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]*gtm.DomainItem, 0, len(names))
+	for _, name := range names {
+		d := s.gtm.domains[name]
+		lastModified, _ := d.doc["lastModified"].(string)
+		items = append(items, &gtm.DomainItem{
+			AcgId:        d.contractID,
+			LastModified: lastModified,
+			Name:         d.name(),
+			Status:       d.status.PropagationStatus,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, gtm.DomainsList{DomainItems: items})
+}
+
+func (s *Server) gtmCreateDomain(w http.ResponseWriter, r *http.Request, _ params) {
+	contractID := r.URL.Query().Get("contractId")
+	if !s.papi.hasContract(contractID) {
+		writeError(w, http.StatusBadRequest, "Contract %s not found", contractID)
+		return
+	}
+	groupID := r.URL.Query().Get("gid")
+	if groupID != "" && s.papi.group(groupID) == nil {
+		writeError(w, http.StatusBadRequest, "Group %s not found", groupID)
+		return
+	}
+	var doc map[string]interface{}
+	if !readJSON(w, r, &doc) {
+		return
+	}
+	name, _ := doc["name"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "The domain name is required")
+		return
+	}
+	if !strings.HasSuffix(name, ".akadns.net") {
+		writeError(w, http.StatusBadRequest, "Domain %s must be a subdomain of akadns.net", name)
+		return
+	}
+	if _, ok := s.gtm.domains[strings.ToLower(name)]; ok {
+		writeError(w, http.StatusConflict, "Domain %s already exists", name)
+		return
+	}
+
+	for _, key := range append([]string{"datacenters", "status", "links"}, collectionKeys()...) {
+		delete(doc, key)
+	}
+	d := &gtmDomain{
+		doc:              doc,
+		contractID:       strings.TrimPrefix(contractID, "ctr_"),
+		groupID:          strings.TrimPrefix(groupID, "grp_"),
+		datacenters:      make(map[int]map[string]interface{}),
+		lastDatacenterID: gtmFirstDatacenterID - 1,
+		objects:          make(map[string]map[string]map[string]interface{}),
+	}
+	for collection := range gtmCollections {
+		d.objects[collection] = make(map[string]map[string]interface{})
+	}
+	s.gtm.domains[strings.ToLower(name)] = d
+	status := s.change(d)
+
+	writeJSON(w, http.StatusCreated, gtm.ResponseBody{Resource: d.document(), Status: status})
+}
+
+func collectionKeys() []string {
+	keys := make([]string, 0, len(gtmCollections))
+	for _, key := range gtmCollections {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (s *Server) gtmGetDomain(w http.ResponseWriter, _ *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, d.document())
+}
+
+func (s *Server) gtmUpdateDomain(w http.ResponseWriter, r *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	var doc map[string]interface{}
+	if !readJSON(w, r, &doc) {
+		return
+	}
+	if name, _ := doc["name"].(string); !strings.EqualFold(name, d.name()) {
+		writeError(w, http.StatusBadRequest, "The domain name must match the path")
+		return
+	}
+
+	for _, key := range append([]string{"datacenters", "status", "links"}, collectionKeys()...) {
+		delete(doc, key)
+	}
+	d.doc = doc
+	status := s.change(d)
+
+	writeJSON(w, http.StatusOK, gtm.ResponseBody{Resource: d.document(), Status: status})
+}
+
+func (s *Server) gtmDeleteDomain(w http.ResponseWriter, _ *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+
+	delete(s.gtm.domains, strings.ToLower(d.name()))
+	writeJSON(w, http.StatusOK, gtm.ResponseBody{Status: &gtm.ResponseStatus{
+		ChangeId:              strconv.Itoa(s.nextID()),
+		Message:               "Domain deleted",
+		PassingValidation:     true,
+		PropagationStatus:     "COMPLETE",
+		PropagationStatusDate: s.timestamp(),
+	}})
+}
+
+func (s *Server) gtmGetDomainStatus(w http.ResponseWriter, _ *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	if d.pending != nil && d.pending.poll() {
+		d.complete(s.timestamp())
+	}
+
+	writeJSON(w, http.StatusOK, d.status)
+}
+
+func (s *Server) gtmListDatacenters(w http.ResponseWriter, _ *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": d.document()["datacenters"]})
+}
+
+// datacenter returns the datacenter from the path, it writes a not found error when it does not exist
+func (s *Server) datacenter(w http.ResponseWriter, d *gtmDomain, p params) (int, map[string]interface{}) {
+	id, err := strconv.Atoi(p["datacenterId"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid datacenter id %s", p["datacenterId"])
+		return 0, nil
+	}
+	dc, ok := d.datacenters[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Datacenter %d not found in domain %s", id, d.name())
+		return 0, nil
+	}
+
+	return id, dc
+}
+
+func (s *Server) gtmCreateDatacenter(w http.ResponseWriter, r *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	var dc map[string]interface{}
+	if !readJSON(w, r, &dc) {
+		return
+	}
+
+	d.lastDatacenterID++
+	dc["datacenterId"] = d.lastDatacenterID
+	d.datacenters[d.lastDatacenterID] = dc
+	status := s.change(d)
+
+	writeJSON(w, http.StatusCreated, gtm.ResponseBody{Resource: dc, Status: status})
+}
+
+func (s *Server) gtmCreateDefaultDatacenter(w http.ResponseWriter, r *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	segments := splitPath(r.URL.Path)
+	id := gtmDefaultDatacenters[segments[len(segments)-1]]
+	dc, ok := d.datacenters[id]
+	if !ok {
+		dc = map[string]interface{}{
+			"datacenterId": id,
+			"nickname":     "Default Datacenter",
+			"virtual":      true,
+		}
+		d.datacenters[id] = dc
+	}
+	status := s.change(d)
+
+	writeJSON(w, http.StatusCreated, gtm.ResponseBody{Resource: dc, Status: status})
+}
+
+func (s *Server) gtmGetDatacenter(w http.ResponseWriter, _ *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	if _, dc := s.datacenter(w, d, p); dc != nil {
+		writeJSON(w, http.StatusOK, dc)
+	}
+}
+
+func (s *Server) gtmUpdateDatacenter(w http.ResponseWriter, r *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	id, current := s.datacenter(w, d, p)
+	if current == nil {
+		return
+	}
+	var dc map[string]interface{}
+	if !readJSON(w, r, &dc) {
+		return
+	}
+
+	dc["datacenterId"] = id
+	d.datacenters[id] = dc
+	status := s.change(d)
+
+	writeJSON(w, http.StatusOK, gtm.ResponseBody{Resource: dc, Status: status})
+}
+
+func (s *Server) gtmDeleteDatacenter(w http.ResponseWriter, _ *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	id, dc := s.datacenter(w, d, p)
+	if dc == nil {
+		return
+	}
+
+	delete(d.datacenters, id)
+	status := s.change(d)
+
+	writeJSON(w, http.StatusOK, gtm.ResponseBody{Status: status})
+}
+
+// objects returns the named objects of the collection in the path, it writes a not found error for unknown collections
+func (s *Server) objects(w http.ResponseWriter, d *gtmDomain, p params) map[string]map[string]interface{} {
+	objects, ok := d.objects[p["collection"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The resource %s is not implemented by the stand-in server", p["collection"])
+		return nil
+	}
+
+	return objects
+}
+
+func (s *Server) gtmListObjects(w http.ResponseWriter, _ *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	objects := s.objects(w, d, p)
+	if objects == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": sortedObjects(objects)})
+}
+
+func (s *Server) gtmGetObject(w http.ResponseWriter, _ *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	objects := s.objects(w, d, p)
+	if objects == nil {
+		return
+	}
+	obj, ok := objects[p["name"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "%s %s not found in domain %s", p["collection"], p["name"], d.name())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) gtmPutObject(w http.ResponseWriter, r *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	objects := s.objects(w, d, p)
+	if objects == nil {
+		return
+	}
+	var obj map[string]interface{}
+	if !readJSON(w, r, &obj) {
+		return
+	}
+	if name, _ := obj["name"].(string); name != p["name"] {
+		writeError(w, http.StatusBadRequest, "The name must match the path")
+		return
+	}
+
+	status := http.StatusOK
+	if _, ok := objects[p["name"]]; !ok {
+		status = http.StatusCreated
+	}
+	objects[p["name"]] = obj
+	writeJSON(w, status, gtm.ResponseBody{Resource: obj, Status: s.change(d)})
+}
+
+func (s *Server) gtmDeleteObject(w http.ResponseWriter, _ *http.Request, p params) {
+	d := s.gtmDomain(w, p["domain"])
+	if d == nil {
+		return
+	}
+	objects := s.objects(w, d, p)
+	if objects == nil {
+		return
+	}
+	if _, ok := objects[p["name"]]; !ok {
+		writeError(w, http.StatusNotFound, "%s %s not found in domain %s", p["collection"], p["name"], d.name())
+		return
+	}
+
+	delete(objects, p["name"])
+	writeJSON(w, http.StatusOK, gtm.ResponseBody{Status: s.change(d)})
+}
@@ -0,0 +1,214 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+const (
+	// hapiDefaultTTL is the TTL of the edge hostnames which keep the Akamai default
+	hapiDefaultTTL = 21600
+
+	// hapiDefaultMap is the map of the edge hostnames which keep the Akamai default
+	hapiDefaultMap = "a;dscb.akamai.net"
+)
+
+type (
+	hapiState struct {
+		changes map[int]*hapiChange
+	}
+
+	// hapiEdgeHostname is the HAPI view of an edge hostname, only the patchable attributes and the identifiers are kept
+	hapiEdgeHostname struct {
+		EdgeHostnameID    int    `json:"edgeHostnameId"`
+		RecordName        string `json:"recordName"`
+		DNSZone           string `json:"dnsZone"`
+		SecurityType      string `json:"securityType"`
+		UseDefaultTTL     bool   `json:"useDefaultTtl"`
+		UseDefaultMap     bool   `json:"useDefaultMap"`
+		TTL               int    `json:"ttl"`
+		Map               string `json:"map"`
+		IPVersionBehavior string `json:"ipVersionBehavior"`
+	}
+
+	hapiChange struct {
+		ChangeID      int    `json:"changeId"`
+		Action        string `json:"action"`
+		Status        string `json:"status"`
+		StatusMessage string `json:"statusMessage,omitempty"`
+
+		pending *pending
+		apply   func()
+	}
+)
+
+func newHAPIState() *hapiState {
+	return &hapiState{
+		changes: make(map[int]*hapiChange),
+	}
+}
+
+func (s *Server) registerHAPI() {
+	rt := s.router
+	rt.handle(http.MethodGet, "/hapi/v1/edge-hostnames/{recordName}/{dnsZone}", s.hapiGetEdgeHostname)
+	rt.handle(http.MethodPatch, "/hapi/v1/edge-hostnames/{recordName}/{dnsZone}", s.hapiPatchEdgeHostname)
+	rt.handle(http.MethodDelete, "/hapi/v1/edge-hostnames/{recordName}/{dnsZone}", s.hapiDeleteEdgeHostname)
+	rt.handle(http.MethodGet, "/hapi/v1/change-requests/{changeId}", s.hapiGetChangeRequest)
+}
+
+// hapiEdgeHostname returns the edge hostname from the path, it writes a not found error when it does not exist
+func (s *Server) hapiEdgeHostname(w http.ResponseWriter, p params) *edgeHostname {
+	for _, e := range s.papi.edgeHostnames {
+		if e.DomainPrefix == p["recordName"] && e.DomainSuffix == p["dnsZone"] {
+			return e
+		}
+	}
+	writeError(w, http.StatusNotFound, "Edge hostname %s.%s not found", p["recordName"], p["dnsZone"])
+
+	return nil
+}
+
+func (e *edgeHostname) hapi() hapiEdgeHostname {
+	id, _ := strconv.Atoi(strings.TrimPrefix(e.ID, "ehn_"))
+	h := hapiEdgeHostname{
+		EdgeHostnameID:    id,
+		RecordName:        e.DomainPrefix,
+		DNSZone:           e.DomainSuffix,
+		SecurityType:      "STANDARD-TLS",
+		UseDefaultTTL:     e.ttl == 0,
+		UseDefaultMap:     e.mapName == "",
+		TTL:               e.ttl,
+		Map:               e.mapName,
+		IPVersionBehavior: e.IPVersionBehavior,
+	}
+	if e.Secure {
+		h.SecurityType = "ENHANCED-TLS"
+	}
+	if h.UseDefaultTTL {
+		h.TTL = hapiDefaultTTL
+	}
+	if h.UseDefaultMap {
+		h.Map = hapiDefaultMap
+	}
+
+	return h
+}
+
+// hapiChange records a change request which is applied once it completes after a number of status reads
+func (s *Server) hapiChange(action string, apply func()) *hapiChange {
+	c := &hapiChange{
+		ChangeID: s.nextID(),
+		Action:   action,
+		Status:   "PENDING",
+		pending:  s.newPending(),
+		apply:    apply,
+	}
+	s.hapi.changes[c.ChangeID] = c
+	if c.pending.done() {
+		c.complete()
+	}
+
+	return c
+}
+
+func (c *hapiChange) complete() {
+	c.apply()
+	c.Status = "SUCCEEDED"
+	c.pending = nil
+}
+
+func (s *Server) hapiGetEdgeHostname(w http.ResponseWriter, _ *http.Request, p params) {
+	e := s.hapiEdgeHostname(w, p)
+	if e == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, e.hapi())
+}
+
+func (s *Server) hapiPatchEdgeHostname(w http.ResponseWriter, r *http.Request, p params) {
+	e := s.hapiEdgeHostname(w, p)
+	if e == nil {
+		return
+	}
+	var ops []tools.JSONPatchOperation
+	if !readJSON(w, r, &ops) {
+		return
+	}
+	for _, op := range ops {
+		switch op.Path {
+		case "/ipVersionBehavior", "/ttl", "/map":
+		default:
+			writeError(w, http.StatusBadRequest, "The attribute %s cannot be changed", op.Path)
+			return
+		}
+	}
+
+	var doc interface{}
+	encoded, err := json.Marshal(e.hapi())
+	if err == nil {
+		err = json.Unmarshal(encoded, &doc)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Decoding the edge hostname: %s", err)
+		return
+	}
+	patched, err := tools.ApplyJSONPatch(doc, ops)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "The patch cannot be applied: %s", err)
+		return
+	}
+	var body hapiEdgeHostname
+	encoded, err = json.Marshal(patched)
+	if err == nil {
+		err = json.Unmarshal(encoded, &body)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "The patched edge hostname is not valid: %s", err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, s.hapiChange("EDIT", func() {
+		e.IPVersionBehavior = body.IPVersionBehavior
+		if body.TTL != hapiDefaultTTL {
+			e.ttl = body.TTL
+		}
+		if body.Map != hapiDefaultMap {
+			e.mapName = body.Map
+		}
+	}))
+}
+
+func (s *Server) hapiDeleteEdgeHostname(w http.ResponseWriter, _ *http.Request, p params) {
+	e := s.hapiEdgeHostname(w, p)
+	if e == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, s.hapiChange("DELETE", func() {
+		for i, other := range s.papi.edgeHostnames {
+			if other == e {
+				s.papi.edgeHostnames = append(s.papi.edgeHostnames[:i], s.papi.edgeHostnames[i+1:]...)
+				return
+			}
+		}
+	}))
+}
+
+func (s *Server) hapiGetChangeRequest(w http.ResponseWriter, _ *http.Request, p params) {
+	id, _ := strconv.Atoi(p["changeId"])
+	c, ok := s.hapi.changes[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Change request %s not found", p["changeId"])
+		return
+	}
+	if c.pending != nil && c.pending.poll() {
+		c.complete()
+	}
+
+	writeJSON(w, http.StatusOK, c)
+}
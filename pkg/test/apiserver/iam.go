@@ -0,0 +1,283 @@
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/iam"
+)
+
+const iamPrefix = "/identity-management/v2/user-admin"
+
+type iamState struct {
+	groups    []iam.Group
+	roles     []iam.Role
+	users     map[string]*iam.User
+	countries map[string][]string
+}
+
+func newIAMState() *iamState {
+	id, _ := strconv.ParseInt(strings.TrimPrefix(GroupID, "grp_"), 10, 64)
+
+	return &iamState{
+		groups: []iam.Group{{
+			GroupID:   id,
+			GroupName: "Test Account",
+			SubGroups: []iam.Group{{GroupID: id + 1, GroupName: "Test Group", ParentGroupID: id}},
+		}},
+		roles: []iam.Role{
+			{RoleID: 12, RoleName: "Admin", RoleDescription: "Full access to the account", RoleType: iam.RoleTypeStandard},
+			{RoleID: 13, RoleName: "Editor", RoleDescription: "Edit access to the account", RoleType: iam.RoleTypeStandard},
+			{RoleID: 14, RoleName: "Viewer", RoleDescription: "Read only access to the account", RoleType: iam.RoleTypeStandard},
+		},
+		users: make(map[string]*iam.User),
+		countries: map[string][]string{
+			"USA":     {"CA", "MA", "NY", "TX"},
+			"Canada":  {"AB", "BC", "ON", "QC"},
+			"Poland":  {},
+			"Germany": {},
+		},
+	}
+}
+
+func (s *Server) registerIAM() {
+	rt := s.router
+	rt.handle(http.MethodGet, iamPrefix+"/groups", s.iamListGroups)
+	rt.handle(http.MethodGet, iamPrefix+"/roles", s.iamListRoles)
+	rt.handle(http.MethodPost, iamPrefix+"/ui-identities", s.iamCreateUser)
+	rt.handle(http.MethodGet, iamPrefix+"/ui-identities/{id}", s.iamGetUser)
+	rt.handle(http.MethodDelete, iamPrefix+"/ui-identities/{id}", s.iamRemoveUser)
+	rt.handle(http.MethodPut, iamPrefix+"/ui-identities/{id}/basic-info", s.iamUpdateUserInfo)
+	rt.handle(http.MethodPut, iamPrefix+"/ui-identities/{id}/notifications", s.iamUpdateUserNotifications)
+	rt.handle(http.MethodPut, iamPrefix+"/ui-identities/{id}/auth-grants", s.iamUpdateUserAuthGrants)
+	rt.handle(http.MethodGet, iamPrefix+"/common/countries", s.iamListCountries)
+	rt.handle(http.MethodGet, iamPrefix+"/common/countries/{country}/states", s.iamListStates)
+	rt.handle(http.MethodGet, iamPrefix+"/common/contact-types", s.iamList("Billing", "Technical - Primary Contact", "Technical - Secondary Contact", "Other"))
+	rt.handle(http.MethodGet, iamPrefix+"/common/supported-languages", s.iamList("English", "Deutsch", "Español", "Français", "Polski"))
+	rt.handle(http.MethodGet, iamPrefix+"/common/notification-products", s.iamList("Ion", "EdgeDNS", "Global Traffic Management", "Kona Site Defender"))
+	rt.handle(http.MethodGet, iamPrefix+"/common/timeout-policies", s.iamListTimeoutPolicies)
+	rt.handle(http.MethodGet, iamPrefix+"/common/timezones", s.iamListTimezones)
+}
+
+func (s *Server) iamListGroups(w http.ResponseWriter, _ *http.Request, _ params) {
+	writeJSON(w, http.StatusOK, s.iam.groups)
+}
+
+func (s *Server) iamListRoles(w http.ResponseWriter, _ *http.Request, _ params) {
+	writeJSON(w, http.StatusOK, s.iam.roles)
+}
+
+func (st *iamState) hasGroup(id int, groups []iam.Group) bool {
+	for _, g := range groups {
+		if g.GroupID == int64(id) || st.hasGroup(id, g.SubGroups) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (st *iamState) role(id int) *iam.Role {
+	for i, r := range st.roles {
+		if r.RoleID == int64(id) {
+			return &st.roles[i]
+		}
+	}
+
+	return nil
+}
+
+// checkAuthGrants validates the groups and roles of the grants and fills in their names
+func (s *Server) checkAuthGrants(w http.ResponseWriter, grants []iam.AuthGrant) bool {
+	for i := range grants {
+		g := &grants[i]
+		if !s.iam.hasGroup(g.GroupID, s.iam.groups) {
+			writeError(w, http.StatusBadRequest, "Group %d not found", g.GroupID)
+			return false
+		}
+		if g.RoleID != nil {
+			role := s.iam.role(*g.RoleID)
+			if role == nil {
+				writeError(w, http.StatusBadRequest, "Role %d not found", *g.RoleID)
+				return false
+			}
+			g.RoleName = role.RoleName
+			g.RoleDescription = role.RoleDescription
+		}
+		if !s.checkAuthGrants(w, g.Subgroups) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) checkUserInfo(w http.ResponseWriter, info iam.UserBasicInfo, identityID string) bool {
+	if info.FirstName == "" || info.LastName == "" || info.Email == "" || info.Country == "" {
+		writeError(w, http.StatusBadRequest, "The firstName, lastName, email and country are required")
+		return false
+	}
+	if _, ok := s.iam.countries[info.Country]; !ok {
+		writeError(w, http.StatusBadRequest, "Unknown country %s", info.Country)
+		return false
+	}
+	for id, u := range s.iam.users {
+		if id != identityID && strings.EqualFold(u.Email, info.Email) {
+			writeError(w, http.StatusConflict, "A user with email %s already exists", info.Email)
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) iamCreateUser(w http.ResponseWriter, r *http.Request, _ params) {
+	// the user is sent flattened, not wrapped as in iam.CreateUserRequest
+	u := &iam.User{}
+	if !readJSON(w, r, u) {
+		return
+	}
+	if !s.checkUserInfo(w, u.UserBasicInfo, "") || !s.checkAuthGrants(w, u.AuthGrants) {
+		return
+	}
+	if len(u.AuthGrants) == 0 {
+		writeError(w, http.StatusBadRequest, "At least one auth grant is required")
+		return
+	}
+
+	u.IdentityID = fmt.Sprintf("B-C-%X", s.nextID())
+	if u.UserName == "" {
+		u.UserName = u.Email
+	}
+	s.iam.users[u.IdentityID] = u
+
+	writeJSON(w, http.StatusCreated, u)
+}
+
+func (s *Server) iamUser(w http.ResponseWriter, p params) *iam.User {
+	u, ok := s.iam.users[p["id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "User %s not found", p["id"])
+		return nil
+	}
+
+	return u
+}
+
+func (s *Server) iamGetUser(w http.ResponseWriter, r *http.Request, p params) {
+	u := s.iamUser(w, p)
+	if u == nil {
+		return
+	}
+
+	resp := *u
+	q := r.URL.Query()
+	if q.Get("authGrants") != "true" {
+		resp.AuthGrants = nil
+	}
+	if q.Get("notifications") != "true" {
+		resp.Notifications = iam.UserNotifications{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) iamRemoveUser(w http.ResponseWriter, _ *http.Request, p params) {
+	if u := s.iamUser(w, p); u != nil {
+		delete(s.iam.users, u.IdentityID)
+		writeJSON(w, http.StatusNoContent, nil)
+	}
+}
+
+func (s *Server) iamUpdateUserInfo(w http.ResponseWriter, r *http.Request, p params) {
+	u := s.iamUser(w, p)
+	if u == nil {
+		return
+	}
+	var info iam.UserBasicInfo
+	if !readJSON(w, r, &info) || !s.checkUserInfo(w, info, u.IdentityID) {
+		return
+	}
+	if info.UserName == "" {
+		info.UserName = u.UserName
+	}
+
+	u.UserBasicInfo = info
+	writeJSON(w, http.StatusOK, u.UserBasicInfo)
+}
+
+func (s *Server) iamUpdateUserNotifications(w http.ResponseWriter, r *http.Request, p params) {
+	u := s.iamUser(w, p)
+	if u == nil {
+		return
+	}
+	var notifications iam.UserNotifications
+	if !readJSON(w, r, &notifications) {
+		return
+	}
+
+	u.Notifications = notifications
+	writeJSON(w, http.StatusOK, u.Notifications)
+}
+
+func (s *Server) iamUpdateUserAuthGrants(w http.ResponseWriter, r *http.Request, p params) {
+	u := s.iamUser(w, p)
+	if u == nil {
+		return
+	}
+	var grants []iam.AuthGrant
+	if !readJSON(w, r, &grants) || !s.checkAuthGrants(w, grants) {
+		return
+	}
+
+	u.AuthGrants = grants
+	writeJSON(w, http.StatusOK, u.AuthGrants)
+}
+
+func (s *Server) iamListCountries(w http.ResponseWriter, _ *http.Request, _ params) {
+	countries := make([]string, 0, len(s.iam.countries))
+	for c := range s.iam.countries {
+		countries = append(countries, c)
+	}
+	sort.Strings(countries)
+
+	writeJSON(w, http.StatusOK, countries)
+}
+
+func (s *Server) iamListStates(w http.ResponseWriter, _ *http.Request, p params) {
+	states, ok := s.iam.countries[p["country"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Country %s not found", p["country"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, states)
+}
+
+// iamList returns a handler serving a fixed list of values
+func (s *Server) iamList(values ...string) handlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request, _ params) {
+		writeJSON(w, http.StatusOK, values)
+	}
+}
+
+func (s *Server) iamListTimeoutPolicies(w http.ResponseWriter, _ *http.Request, _ params) {
+	writeJSON(w, http.StatusOK, []iam.TimeoutPolicy{
+		{Name: "after15Minutes", Value: 900},
+		{Name: "after30Minutes", Value: 1800},
+		{Name: "after1Hour", Value: 3600},
+		{Name: "after2Hours", Value: 7200},
+		{Name: "after4Hours", Value: 14400},
+		{Name: "after8Hours", Value: 28800},
+	})
+}
+
+func (s *Server) iamListTimezones(w http.ResponseWriter, _ *http.Request, _ params) {
+	writeJSON(w, http.StatusOK, []iam.Timezone{
+		{Description: "GMT 0:00 Greenwich Mean Time", Offset: "0", Posix: "GMT", Timezone: "GMT"},
+		{Description: "GMT -5:00 America/New York", Offset: "-5", Posix: "America/New_York", Timezone: "America/New_York"},
+		{Description: "GMT +1:00 Europe/Warsaw", Offset: "+1", Posix: "Europe/Warsaw", Timezone: "Europe/Warsaw"},
+	})
+}
@@ -0,0 +1,1078 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/tools"
+)
+
+type (
+	papiState struct {
+		groups        []*papi.Group
+		contracts     []*papi.Contract
+		products      []papi.ProductItem
+		ruleFormats   []string
+		cpCodes       []*cpCode
+		edgeHostnames []*edgeHostname
+		properties    map[string]*property
+		activations   []*activation
+	}
+
+	cpCode struct {
+		papi.CPCode
+		contractID string
+		groupID    string
+	}
+
+	edgeHostname struct {
+		papi.EdgeHostnameGetItem
+		contractID string
+		groupID    string
+
+		// ttl and mapName are only changed through HAPI, zero values keep the Akamai defaults
+		ttl     int
+		mapName string
+	}
+
+	property struct {
+		papi.Property
+		versions []*propertyVersion
+	}
+
+	propertyVersion struct {
+		papi.PropertyVersionGetItem
+		rules     papi.Rules
+		hostnames []papi.Hostname
+	}
+
+	activation struct {
+		papi.Activation
+		pending *pending
+	}
+)
+
+func newPAPIState() *papiState {
+	return &papiState{
+		groups: []*papi.Group{
+			{GroupID: GroupID, GroupName: "Test Account", ContractIDs: []string{ContractID}},
+			{GroupID: "grp_10001", GroupName: "Test Group", ParentGroupID: GroupID, ContractIDs: []string{ContractID}},
+		},
+		contracts: []*papi.Contract{
+			{ContractID: ContractID, ContractTypeName: "DIRECT_CUSTOMER"},
+		},
+		products: []papi.ProductItem{
+			{ProductID: "prd_Fresca", ProductName: "Ion Standard"},
+			{ProductID: "prd_SPM", ProductName: "Ion Premier"},
+			{ProductID: "prd_Site_Accel", ProductName: "Dynamic Site Accelerator"},
+			{ProductID: "prd_Web_App_Accel", ProductName: "Web Application Accelerator"},
+			{ProductID: "prd_Download_Delivery", ProductName: "Download Delivery"},
+		},
+		ruleFormats: []string{"latest", "v2020-11-02", "v2020-03-04", "v2019-07-25"},
+		properties:  make(map[string]*property),
+	}
+}
+
+func (s *Server) registerPAPI() {
+	rt := s.router
+	rt.handle(http.MethodGet, "/papi/v1/groups", s.papiGetGroups)
+	rt.handle(http.MethodGet, "/papi/v1/contracts", s.papiGetContracts)
+	rt.handle(http.MethodGet, "/papi/v1/products", s.papiGetProducts)
+	rt.handle(http.MethodGet, "/papi/v1/rule-formats", s.papiGetRuleFormats)
+	rt.handle(http.MethodGet, "/papi/v1/cpcodes", s.papiGetCPCodes)
+	rt.handle(http.MethodPost, "/papi/v1/cpcodes", s.papiCreateCPCode)
+	rt.handle(http.MethodGet, "/papi/v1/cpcodes/{cpcodeId}", s.papiGetCPCode)
+	rt.handle(http.MethodGet, "/papi/v1/edgehostnames", s.papiGetEdgeHostnames)
+	rt.handle(http.MethodPost, "/papi/v1/edgehostnames", s.papiCreateEdgeHostname)
+	rt.handle(http.MethodGet, "/papi/v1/edgehostnames/{edgeHostnameId}", s.papiGetEdgeHostname)
+	rt.handle(http.MethodGet, "/papi/v1/properties", s.papiGetProperties)
+	rt.handle(http.MethodPost, "/papi/v1/properties", s.papiCreateProperty)
+	rt.handle(http.MethodGet, "/papi/v1/properties/{propertyId}", s.papiGetProperty)
+	rt.handle(http.MethodDelete, "/papi/v1/properties/{propertyId}", s.papiRemoveProperty)
+	rt.handle(http.MethodGet, "/papi/v1/properties/{propertyId}/versions", s.papiGetPropertyVersions)
+	rt.handle(http.MethodPost, "/papi/v1/properties/{propertyId}/versions", s.papiCreatePropertyVersion)
+	rt.handle(http.MethodGet, "/papi/v1/properties/{propertyId}/versions/latest", s.papiGetLatestVersion)
+	rt.handle(http.MethodGet, "/papi/v1/properties/{propertyId}/versions/{version}", s.papiGetPropertyVersion)
+	rt.handle(http.MethodGet, "/papi/v1/properties/{propertyId}/versions/{version}/rules", s.papiGetRuleTree)
+	rt.handle(http.MethodPut, "/papi/v1/properties/{propertyId}/versions/{version}/rules", s.papiUpdateRuleTree)
+	rt.handle(http.MethodPatch, "/papi/v1/properties/{propertyId}/versions/{version}/rules", s.papiPatchRuleTree)
+	rt.handle(http.MethodGet, "/papi/v1/properties/{propertyId}/versions/{version}/hostnames", s.papiGetHostnames)
+	rt.handle(http.MethodPut, "/papi/v1/properties/{propertyId}/versions/{version}/hostnames", s.papiUpdateHostnames)
+	rt.handle(http.MethodGet, "/papi/v1/properties/{propertyId}/activations", s.papiGetActivations)
+	rt.handle(http.MethodPost, "/papi/v1/properties/{propertyId}/activations", s.papiCreateActivation)
+	rt.handle(http.MethodGet, "/papi/v1/properties/{propertyId}/activations/{activationId}", s.papiGetActivation)
+	rt.handle(http.MethodDelete, "/papi/v1/properties/{propertyId}/activations/{activationId}", s.papiCancelActivation)
+	rt.handle(http.MethodPost, "/papi/v1/search/find-by-value", s.papiSearch)
+}
+
+// withPrefix returns the id with the given prefix, PAPI accepts the ids with or without it
+func withPrefix(id, prefix string) string {
+	if id == "" || strings.HasPrefix(id, prefix) {
+		return id
+	}
+
+	return prefix + id
+}
+
+func (st *papiState) group(id string) *papi.Group {
+	for _, g := range st.groups {
+		if g.GroupID == withPrefix(id, "grp_") {
+			return g
+		}
+	}
+
+	return nil
+}
+
+func (st *papiState) hasContract(id string) bool {
+	for _, c := range st.contracts {
+		if c.ContractID == withPrefix(id, "ctr_") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (st *papiState) hasProduct(id string) bool {
+	for _, p := range st.products {
+		if p.ProductID == withPrefix(id, "prd_") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// activeHostnames returns the hostnames of the property versions active on any network, AppSec can protect them
+func (st *papiState) activeHostnames() []string {
+	seen := make(map[string]bool)
+	for _, prp := range st.properties {
+		for _, v := range prp.versions {
+			active := (prp.StagingVersion != nil && *prp.StagingVersion == v.PropertyVersion) ||
+				(prp.ProductionVersion != nil && *prp.ProductionVersion == v.PropertyVersion)
+			if !active {
+				continue
+			}
+			for _, h := range v.hostnames {
+				seen[h.CnameFrom] = true
+			}
+		}
+	}
+
+	hostnames := make([]string, 0, len(seen))
+	for h := range seen {
+		hostnames = append(hostnames, h)
+	}
+	sort.Strings(hostnames)
+
+	return hostnames
+}
+
+// checkContractGroup validates the contractId and groupId query parameters required by most PAPI operations
+func (s *Server) checkContractGroup(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	contractID := withPrefix(r.URL.Query().Get("contractId"), "ctr_")
+	groupID := withPrefix(r.URL.Query().Get("groupId"), "grp_")
+	if contractID == "" || groupID == "" {
+		writeError(w, http.StatusBadRequest, "The contractId and groupId query parameters are required")
+		return "", "", false
+	}
+	if !s.papi.hasContract(contractID) {
+		writeError(w, http.StatusNotFound, "Contract %s not found", contractID)
+		return "", "", false
+	}
+	if s.papi.group(groupID) == nil {
+		writeError(w, http.StatusNotFound, "Group %s not found", groupID)
+		return "", "", false
+	}
+
+	return contractID, groupID, true
+}
+
+func (s *Server) papiGetGroups(w http.ResponseWriter, _ *http.Request, _ params) {
+	writeJSON(w, http.StatusOK, papi.GetGroupsResponse{
+		AccountID:   AccountID,
+		AccountName: "Test Account",
+		Groups:      papi.GroupItems{Items: s.papi.groups},
+	})
+}
+
+func (s *Server) papiGetContracts(w http.ResponseWriter, _ *http.Request, _ params) {
+	writeJSON(w, http.StatusOK, papi.GetContractsResponse{
+		AccountID: AccountID,
+		Contracts: papi.ContractsItems{Items: s.papi.contracts},
+	})
+}
+
+func (s *Server) papiGetProducts(w http.ResponseWriter, r *http.Request, _ params) {
+	contractID := withPrefix(r.URL.Query().Get("contractId"), "ctr_")
+	if !s.papi.hasContract(contractID) {
+		writeError(w, http.StatusNotFound, "Contract %s not found", contractID)
+		return
+	}
+	writeJSON(w, http.StatusOK, papi.GetProductsResponse{
+		AccountID:  AccountID,
+		ContractID: contractID,
+		Products:   papi.ProductsItems{Items: s.papi.products},
+	})
+}
+
+func (s *Server) papiGetRuleFormats(w http.ResponseWriter, _ *http.Request, _ params) {
+	writeJSON(w, http.StatusOK, papi.GetRuleFormatsResponse{
+		RuleFormats: papi.RuleFormatItems{Items: s.papi.ruleFormats},
+	})
+}
+
+func (s *Server) papiGetCPCodes(w http.ResponseWriter, r *http.Request, _ params) {
+	contractID, groupID, ok := s.checkContractGroup(w, r)
+	if !ok {
+		return
+	}
+
+	items := make([]papi.CPCode, 0)
+	for _, c := range s.papi.cpCodes {
+		if c.contractID == contractID && c.groupID == groupID {
+			items = append(items, c.CPCode)
+		}
+	}
+	writeJSON(w, http.StatusOK, papi.GetCPCodesResponse{
+		AccountID:  AccountID,
+		ContractID: contractID,
+		GroupID:    groupID,
+		CPCodes:    papi.CPCodeItems{Items: items},
+	})
+}
+
+func (s *Server) papiGetCPCode(w http.ResponseWriter, r *http.Request, p params) {
+	contractID, groupID, ok := s.checkContractGroup(w, r)
+	if !ok {
+		return
+	}
+
+	for _, c := range s.papi.cpCodes {
+		if c.ID == withPrefix(p["cpcodeId"], "cpc_") && c.contractID == contractID && c.groupID == groupID {
+			writeJSON(w, http.StatusOK, papi.GetCPCodesResponse{
+				AccountID:  AccountID,
+				ContractID: contractID,
+				GroupID:    groupID,
+				CPCodes:    papi.CPCodeItems{Items: []papi.CPCode{c.CPCode}},
+			})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "CP code %s not found", p["cpcodeId"])
+}
+
+func (s *Server) papiCreateCPCode(w http.ResponseWriter, r *http.Request, _ params) {
+	contractID, groupID, ok := s.checkContractGroup(w, r)
+	if !ok {
+		return
+	}
+	var body papi.CreateCPCode
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.CPCodeName == "" || !s.papi.hasProduct(body.ProductID) {
+		writeError(w, http.StatusBadRequest, "A cpcodeName and a productId available on the contract are required")
+		return
+	}
+
+	c := &cpCode{
+		CPCode: papi.CPCode{
+			ID:          fmt.Sprintf("cpc_%d", s.nextID()),
+			Name:        body.CPCodeName,
+			CreatedDate: s.timestamp(),
+			ProductIDs:  []string{withPrefix(body.ProductID, "prd_")},
+		},
+		contractID: contractID,
+		groupID:    groupID,
+	}
+	s.papi.cpCodes = append(s.papi.cpCodes, c)
+
+	writeJSON(w, http.StatusCreated, papi.CreateCPCodeResponse{
+		CPCodeLink: fmt.Sprintf("/papi/v1/cpcodes/%s?contractId=%s&groupId=%s", c.ID, contractID, groupID),
+	})
+}
+
+func (s *Server) papiGetEdgeHostnames(w http.ResponseWriter, r *http.Request, _ params) {
+	contractID, groupID, ok := s.checkContractGroup(w, r)
+	if !ok {
+		return
+	}
+
+	items := make([]papi.EdgeHostnameGetItem, 0)
+	for _, e := range s.papi.edgeHostnames {
+		if e.contractID == contractID && e.groupID == groupID {
+			items = append(items, e.EdgeHostnameGetItem)
+		}
+	}
+	writeJSON(w, http.StatusOK, papi.GetEdgeHostnamesResponse{
+		AccountID:     AccountID,
+		ContractID:    contractID,
+		GroupID:       groupID,
+		EdgeHostnames: papi.EdgeHostnameItems{Items: items},
+	})
+}
+
+func (s *Server) papiGetEdgeHostname(w http.ResponseWriter, r *http.Request, p params) {
+	contractID, groupID, ok := s.checkContractGroup(w, r)
+	if !ok {
+		return
+	}
+
+	for _, e := range s.papi.edgeHostnames {
+		if e.ID == withPrefix(p["edgeHostnameId"], "ehn_") && e.contractID == contractID && e.groupID == groupID {
+			writeJSON(w, http.StatusOK, papi.GetEdgeHostnamesResponse{
+				AccountID:     AccountID,
+				ContractID:    contractID,
+				GroupID:       groupID,
+				EdgeHostnames: papi.EdgeHostnameItems{Items: []papi.EdgeHostnameGetItem{e.EdgeHostnameGetItem}},
+			})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Edge hostname %s not found", p["edgeHostnameId"])
+}
+
+func (s *Server) papiCreateEdgeHostname(w http.ResponseWriter, r *http.Request, _ params) {
+	contractID, groupID, ok := s.checkContractGroup(w, r)
+	if !ok {
+		return
+	}
+	var body papi.EdgeHostnameCreate
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.DomainPrefix == "" || body.DomainSuffix == "" || !s.papi.hasProduct(body.ProductID) {
+		writeError(w, http.StatusBadRequest, "A domainPrefix, a domainSuffix and a productId available on the contract are required")
+		return
+	}
+
+	domain := body.DomainPrefix + "." + body.DomainSuffix
+	for _, e := range s.papi.edgeHostnames {
+		if e.Domain == domain {
+			writeError(w, http.StatusConflict, "Edge hostname %s already exists", domain)
+			return
+		}
+	}
+
+	e := &edgeHostname{
+		EdgeHostnameGetItem: papi.EdgeHostnameGetItem{
+			ID:                fmt.Sprintf("ehn_%d", s.nextID()),
+			Domain:            domain,
+			ProductID:         withPrefix(body.ProductID, "prd_"),
+			DomainPrefix:      body.DomainPrefix,
+			DomainSuffix:      body.DomainSuffix,
+			Status:            "CREATED",
+			Secure:            body.Secure || body.SecureNetwork != "",
+			IPVersionBehavior: body.IPVersionBehavior,
+			UseCases:          body.UseCases,
+		},
+		contractID: contractID,
+		groupID:    groupID,
+	}
+	s.papi.edgeHostnames = append(s.papi.edgeHostnames, e)
+
+	writeJSON(w, http.StatusCreated, papi.CreateEdgeHostnameResponse{
+		EdgeHostnameLink: fmt.Sprintf("/papi/v1/edgehostnames/%s?contractId=%s&groupId=%s", e.ID, contractID, groupID),
+	})
+}
+
+// property returns the property from the path, it writes a not found error when it does not exist
+func (s *Server) property(w http.ResponseWriter, p params) *property {
+	prp, ok := s.papi.properties[withPrefix(p["propertyId"], "prp_")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Property %s not found", p["propertyId"])
+		return nil
+	}
+
+	return prp
+}
+
+// propertyVersion returns the property and version from the path, it writes a not found error when one does not exist
+func (s *Server) propertyVersion(w http.ResponseWriter, p params) (*property, *propertyVersion) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(p["version"])
+	if err != nil || n < 1 || n > len(prp.versions) {
+		writeError(w, http.StatusNotFound, "Version %s of property %s not found", p["version"], prp.PropertyID)
+		return nil, nil
+	}
+
+	return prp, prp.versions[n-1]
+}
+
+func (s *Server) newEtag() string {
+	return fmt.Sprintf("%040x", s.nextID())
+}
+
+func (s *Server) papiGetProperties(w http.ResponseWriter, r *http.Request, _ params) {
+	contractID, groupID, ok := s.checkContractGroup(w, r)
+	if !ok {
+		return
+	}
+
+	items := make([]*papi.Property, 0)
+	for _, prp := range s.sortedProperties() {
+		if prp.ContractID == contractID && prp.GroupID == groupID {
+			p := prp.Property
+			items = append(items, &p)
+		}
+	}
+	writeJSON(w, http.StatusOK, papi.GetPropertiesResponse{
+		Properties: papi.PropertiesItems{Items: items},
+	})
+}
+
+func (s *Server) sortedProperties() []*property {
+	props := make([]*property, 0, len(s.papi.properties))
+	for _, prp := range s.papi.properties {
+		props = append(props, prp)
+	}
+	sort.Slice(props, func(i, j int) bool {
+		return props[i].AssetID < props[j].AssetID
+	})
+
+	return props
+}
+
+func (s *Server) papiCreateProperty(w http.ResponseWriter, r *http.Request, _ params) {
+	contractID, groupID, ok := s.checkContractGroup(w, r)
+	if !ok {
+		return
+	}
+	var body papi.PropertyCreate
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.PropertyName == "" || !s.papi.hasProduct(body.ProductID) {
+		writeError(w, http.StatusBadRequest, "A propertyName and a productId available on the contract are required")
+		return
+	}
+	for _, prp := range s.papi.properties {
+		if prp.PropertyName == body.PropertyName {
+			writeError(w, http.StatusConflict, "Property %s already exists", body.PropertyName)
+			return
+		}
+	}
+	ruleFormat := body.RuleFormat
+	if ruleFormat == "" {
+		ruleFormat = "latest"
+	}
+
+	version := &propertyVersion{
+		PropertyVersionGetItem: papi.PropertyVersionGetItem{
+			Etag:             s.newEtag(),
+			ProductID:        withPrefix(body.ProductID, "prd_"),
+			ProductionStatus: papi.VersionStatusInactive,
+			PropertyVersion:  1,
+			RuleFormat:       ruleFormat,
+			StagingStatus:    papi.VersionStatusInactive,
+			UpdatedByUser:    "apiserver",
+			UpdatedDate:      s.timestamp(),
+		},
+		rules: papi.Rules{Name: "default", CriteriaMustSatisfy: papi.RuleCriteriaMustSatisfyAll},
+	}
+	if body.CloneFrom != nil {
+		from, ok := s.papi.properties[withPrefix(body.CloneFrom.PropertyID, "prp_")]
+		if !ok || body.CloneFrom.Version < 1 || body.CloneFrom.Version > len(from.versions) {
+			writeError(w, http.StatusNotFound, "Version %d of property %s not found", body.CloneFrom.Version, body.CloneFrom.PropertyID)
+			return
+		}
+		source := from.versions[body.CloneFrom.Version-1]
+		version.rules = source.rules
+		if body.CloneFrom.CopyHostnames {
+			version.hostnames = append([]papi.Hostname{}, source.hostnames...)
+		}
+	}
+
+	id := s.nextID()
+	prp := &property{
+		Property: papi.Property{
+			AccountID:     AccountID,
+			AssetID:       fmt.Sprintf("aid_%d", id),
+			ContractID:    contractID,
+			GroupID:       groupID,
+			LatestVersion: 1,
+			ProductID:     withPrefix(body.ProductID, "prd_"),
+			PropertyID:    fmt.Sprintf("prp_%d", id),
+			PropertyName:  body.PropertyName,
+			RuleFormat:    ruleFormat,
+		},
+		versions: []*propertyVersion{version},
+	}
+	s.papi.properties[prp.PropertyID] = prp
+
+	writeJSON(w, http.StatusCreated, papi.CreatePropertyResponse{
+		PropertyLink: fmt.Sprintf("/papi/v1/properties/%s?contractId=%s&groupId=%s", prp.PropertyID, contractID, groupID),
+	})
+}
+
+func (s *Server) papiGetProperty(w http.ResponseWriter, _ *http.Request, p params) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return
+	}
+
+	item := prp.Property
+	writeJSON(w, http.StatusOK, papi.GetPropertyResponse{
+		Properties: papi.PropertiesItems{Items: []*papi.Property{&item}},
+	})
+}
+
+func (s *Server) papiRemoveProperty(w http.ResponseWriter, _ *http.Request, p params) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return
+	}
+	if prp.StagingVersion != nil || prp.ProductionVersion != nil {
+		writeError(w, http.StatusBadRequest, "Property %s is active, deactivate it before removing it", prp.PropertyID)
+		return
+	}
+
+	delete(s.papi.properties, prp.PropertyID)
+	writeJSON(w, http.StatusOK, papi.RemovePropertyResponse{Message: "Deletion Successful."})
+}
+
+func (s *Server) versionsResponse(prp *property, versions ...*propertyVersion) papi.GetPropertyVersionsResponse {
+	items := make([]papi.PropertyVersionGetItem, 0, len(versions))
+	for _, v := range versions {
+		items = append(items, v.PropertyVersionGetItem)
+	}
+
+	return papi.GetPropertyVersionsResponse{
+		PropertyID:   prp.PropertyID,
+		PropertyName: prp.PropertyName,
+		AccountID:    AccountID,
+		ContractID:   prp.ContractID,
+		GroupID:      prp.GroupID,
+		AssetID:      prp.AssetID,
+		Versions:     papi.PropertyVersionItems{Items: items},
+	}
+}
+
+func (s *Server) papiGetPropertyVersions(w http.ResponseWriter, _ *http.Request, p params) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return
+	}
+
+	versions := make([]*propertyVersion, 0, len(prp.versions))
+	for i := len(prp.versions) - 1; i >= 0; i-- {
+		versions = append(versions, prp.versions[i])
+	}
+	writeJSON(w, http.StatusOK, s.versionsResponse(prp, versions...))
+}
+
+func (s *Server) papiGetPropertyVersion(w http.ResponseWriter, _ *http.Request, p params) {
+	prp, version := s.propertyVersion(w, p)
+	if version == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.versionsResponse(prp, version))
+}
+
+func (s *Server) papiGetLatestVersion(w http.ResponseWriter, r *http.Request, p params) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return
+	}
+
+	var n *int
+	switch r.URL.Query().Get("activatedOn") {
+	case "":
+		n = &prp.LatestVersion
+	case papi.VersionStaging:
+		n = prp.StagingVersion
+	case papi.VersionProduction:
+		n = prp.ProductionVersion
+	default:
+		writeError(w, http.StatusBadRequest, "activatedOn must be STAGING or PRODUCTION")
+		return
+	}
+	if n == nil {
+		writeError(w, http.StatusNotFound, "Property %s has no version active on %s", prp.PropertyID, r.URL.Query().Get("activatedOn"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.versionsResponse(prp, prp.versions[*n-1]))
+}
+
+func (s *Server) papiCreatePropertyVersion(w http.ResponseWriter, r *http.Request, p params) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return
+	}
+	var body papi.PropertyVersionCreate
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.CreateFromVersion < 1 || body.CreateFromVersion > len(prp.versions) {
+		writeError(w, http.StatusBadRequest, "Version %d of property %s does not exist", body.CreateFromVersion, prp.PropertyID)
+		return
+	}
+	from := prp.versions[body.CreateFromVersion-1]
+	if body.CreateFromVersionEtag != "" && body.CreateFromVersionEtag != from.Etag {
+		writeError(w, http.StatusPreconditionFailed, "The etag of version %d does not match", body.CreateFromVersion)
+		return
+	}
+
+	version := &propertyVersion{
+		PropertyVersionGetItem: papi.PropertyVersionGetItem{
+			Etag:             s.newEtag(),
+			ProductID:        from.ProductID,
+			ProductionStatus: papi.VersionStatusInactive,
+			PropertyVersion:  len(prp.versions) + 1,
+			RuleFormat:       from.RuleFormat,
+			StagingStatus:    papi.VersionStatusInactive,
+			UpdatedByUser:    "apiserver",
+			UpdatedDate:      s.timestamp(),
+		},
+		rules:     from.rules,
+		hostnames: append([]papi.Hostname{}, from.hostnames...),
+	}
+	prp.versions = append(prp.versions, version)
+	prp.LatestVersion = version.PropertyVersion
+
+	writeJSON(w, http.StatusCreated, papi.CreatePropertyVersionResponse{
+		VersionLink: fmt.Sprintf("/papi/v1/properties/%s/versions/%d?contractId=%s&groupId=%s",
+			prp.PropertyID, version.PropertyVersion, prp.ContractID, prp.GroupID),
+	})
+}
+
+// editable tells whether a version can still be modified, versions which were ever activated are locked
+func (v *propertyVersion) editable() bool {
+	return v.StagingStatus == papi.VersionStatusInactive && v.ProductionStatus == papi.VersionStatusInactive
+}
+
+func (s *Server) papiGetRuleTree(w http.ResponseWriter, _ *http.Request, p params) {
+	prp, version := s.propertyVersion(w, p)
+	if version == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, papi.GetRuleTreeResponse{
+		Response:        papi.Response{AccountID: AccountID, ContractID: prp.ContractID, GroupID: prp.GroupID},
+		PropertyID:      prp.PropertyID,
+		PropertyVersion: version.PropertyVersion,
+		Etag:            version.Etag,
+		RuleFormat:      version.RuleFormat,
+		Rules:           version.rules,
+	})
+}
+
+func (s *Server) papiUpdateRuleTree(w http.ResponseWriter, r *http.Request, p params) {
+	prp, version := s.propertyVersion(w, p)
+	if version == nil {
+		return
+	}
+	if !version.editable() {
+		writeError(w, http.StatusForbidden, "Version %d of property %s was activated and cannot be modified", version.PropertyVersion, prp.PropertyID)
+		return
+	}
+	var body ruleTree
+	if !readJSON(w, r, &body) {
+		return
+	}
+	s.saveRuleTree(w, r, prp, version, body)
+}
+
+// ruleTree is the body of the rules requests, the comments are the notes of the version
+type ruleTree struct {
+	Rules    papi.Rules `json:"rules"`
+	Comments *string    `json:"comments,omitempty"`
+}
+
+// papiPatchRuleTree applies a JSON patch to the rule tree of the version, like {"rules": {...}, "comments": "..."}
+func (s *Server) papiPatchRuleTree(w http.ResponseWriter, r *http.Request, p params) {
+	prp, version := s.propertyVersion(w, p)
+	if version == nil {
+		return
+	}
+	if !version.editable() {
+		writeError(w, http.StatusForbidden, "Version %d of property %s was activated and cannot be modified", version.PropertyVersion, prp.PropertyID)
+		return
+	}
+	var ops []tools.JSONPatchOperation
+	if !readJSON(w, r, &ops) {
+		return
+	}
+
+	current, err := json.Marshal(ruleTree{Rules: version.rules, Comments: &version.Note})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Encoding the rule tree: %s", err)
+		return
+	}
+	var doc interface{}
+	if err := json.Unmarshal(current, &doc); err != nil {
+		writeError(w, http.StatusInternalServerError, "Decoding the rule tree: %s", err)
+		return
+	}
+	patched, err := tools.ApplyJSONPatch(doc, ops)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "The patch cannot be applied: %s", err)
+		return
+	}
+
+	var body ruleTree
+	encoded, err := json.Marshal(patched)
+	if err == nil {
+		err = json.Unmarshal(encoded, &body)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "The patched rule tree is not valid: %s", err)
+		return
+	}
+	s.saveRuleTree(w, r, prp, version, body)
+}
+
+func (s *Server) saveRuleTree(w http.ResponseWriter, r *http.Request, prp *property, version *propertyVersion, body ruleTree) {
+	if body.Rules.Name != "default" {
+		writeError(w, http.StatusBadRequest, "The top level rule must be named default")
+		return
+	}
+
+	if r.URL.Query().Get("dryRun") != "true" {
+		version.rules = body.Rules
+		if body.Comments != nil {
+			version.Note = *body.Comments
+		}
+		version.Etag = s.newEtag()
+		version.UpdatedDate = s.timestamp()
+	}
+
+	writeJSON(w, http.StatusOK, papi.UpdateRulesResponse{
+		AccountID:       AccountID,
+		ContractID:      prp.ContractID,
+		GroupID:         prp.GroupID,
+		PropertyID:      prp.PropertyID,
+		PropertyVersion: version.PropertyVersion,
+		Etag:            version.Etag,
+		RuleFormat:      version.RuleFormat,
+		Rules:           body.Rules,
+	})
+}
+
+func (s *Server) hostnamesResponse(prp *property, version *propertyVersion) papi.GetPropertyVersionHostnamesResponse {
+	items := version.hostnames
+	if items == nil {
+		items = make([]papi.Hostname, 0)
+	}
+
+	return papi.GetPropertyVersionHostnamesResponse{
+		AccountID:       AccountID,
+		ContractID:      prp.ContractID,
+		GroupID:         prp.GroupID,
+		PropertyID:      prp.PropertyID,
+		PropertyVersion: version.PropertyVersion,
+		Etag:            version.Etag,
+		Hostnames:       papi.HostnameResponseItems{Items: items},
+	}
+}
+
+func (s *Server) papiGetHostnames(w http.ResponseWriter, _ *http.Request, p params) {
+	prp, version := s.propertyVersion(w, p)
+	if version == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.hostnamesResponse(prp, version))
+}
+
+func (s *Server) papiUpdateHostnames(w http.ResponseWriter, r *http.Request, p params) {
+	prp, version := s.propertyVersion(w, p)
+	if version == nil {
+		return
+	}
+	if !version.editable() {
+		writeError(w, http.StatusForbidden, "Version %d of property %s was activated and cannot be modified", version.PropertyVersion, prp.PropertyID)
+		return
+	}
+	var body []papi.Hostname
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	for i, h := range body {
+		if h.CnameFrom == "" {
+			writeError(w, http.StatusBadRequest, "Hostname %d has no cnameFrom", i)
+			return
+		}
+		if h.CnameType == "" {
+			body[i].CnameType = papi.HostnameCnameTypeEdgeHostname
+		}
+		for _, e := range s.papi.edgeHostnames {
+			if e.Domain == h.CnameTo || e.ID == h.EdgeHostnameID {
+				body[i].CnameTo = e.Domain
+				body[i].EdgeHostnameID = e.ID
+			}
+		}
+	}
+	version.hostnames = body
+	version.Etag = s.newEtag()
+	version.UpdatedDate = s.timestamp()
+
+	writeJSON(w, http.StatusOK, papi.UpdatePropertyVersionHostnamesResponse(s.hostnamesResponse(prp, version)))
+}
+
+// advanceActivations records a status read of the pending activations of the property and applies the completed ones
+func (s *Server) advanceActivations(prp *property) {
+	for _, a := range s.papi.activations {
+		if a.PropertyID != prp.PropertyID || a.pending == nil || !a.pending.poll() {
+			continue
+		}
+		a.pending = nil
+		s.completeActivation(prp, a)
+	}
+}
+
+// completeActivation applies a finished activation or deactivation to the versions of the property,
+// deactivations also report the ACTIVE status once they are fully processed
+func (s *Server) completeActivation(prp *property, a *activation) {
+	a.Status = papi.ActivationStatusActive
+	a.UpdateDate = s.timestamp()
+
+	for _, other := range s.papi.activations {
+		if other != a && other.PropertyID == a.PropertyID && other.Network == a.Network && other.Status == papi.ActivationStatusActive {
+			other.Status = papi.ActivationStatusInactive
+		}
+	}
+
+	active := &prp.StagingVersion
+	if a.Network == papi.ActivationNetworkProduction {
+		active = &prp.ProductionVersion
+	}
+	setStatus := func(v *propertyVersion, status papi.VersionStatus) {
+		if a.Network == papi.ActivationNetworkProduction {
+			v.ProductionStatus = status
+		} else {
+			v.StagingStatus = status
+		}
+	}
+
+	if *active != nil {
+		setStatus(prp.versions[**active-1], papi.VersionStatusDeactivated)
+		*active = nil
+	}
+	if a.ActivationType == papi.ActivationTypeActivate {
+		n := a.PropertyVersion
+		*active = &n
+		setStatus(prp.versions[n-1], papi.VersionStatusActive)
+	}
+}
+
+func (s *Server) activationsResponse(prp *property, activations ...*activation) papi.GetActivationsResponse {
+	items := make([]*papi.Activation, 0, len(activations))
+	for _, a := range activations {
+		item := a.Activation
+		items = append(items, &item)
+	}
+
+	return papi.GetActivationsResponse{
+		Response:    papi.Response{AccountID: AccountID, ContractID: prp.ContractID, GroupID: prp.GroupID},
+		Activations: papi.ActivationsItems{Items: items},
+	}
+}
+
+func (s *Server) papiGetActivations(w http.ResponseWriter, _ *http.Request, p params) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return
+	}
+	s.advanceActivations(prp)
+
+	activations := make([]*activation, 0)
+	for i := len(s.papi.activations) - 1; i >= 0; i-- {
+		if a := s.papi.activations[i]; a.PropertyID == prp.PropertyID {
+			activations = append(activations, a)
+		}
+	}
+	writeJSON(w, http.StatusOK, s.activationsResponse(prp, activations...))
+}
+
+func (s *Server) activation(w http.ResponseWriter, prp *property, p params) *activation {
+	id := withPrefix(p["activationId"], "atv_")
+	for _, a := range s.papi.activations {
+		if a.ActivationID == id && a.PropertyID == prp.PropertyID {
+			return a
+		}
+	}
+	writeError(w, http.StatusNotFound, "Activation %s not found", p["activationId"])
+
+	return nil
+}
+
+func (s *Server) papiGetActivation(w http.ResponseWriter, _ *http.Request, p params) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return
+	}
+	a := s.activation(w, prp, p)
+	if a == nil {
+		return
+	}
+	s.advanceActivations(prp)
+
+	if a.pending != nil {
+		w.Header().Set("Retry-After", "1")
+	}
+	writeJSON(w, http.StatusOK, s.activationsResponse(prp, a))
+}
+
+func (s *Server) papiCreateActivation(w http.ResponseWriter, r *http.Request, p params) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return
+	}
+	var body papi.Activation
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.PropertyVersion < 1 || body.PropertyVersion > len(prp.versions) {
+		writeError(w, http.StatusBadRequest, "Version %d of property %s does not exist", body.PropertyVersion, prp.PropertyID)
+		return
+	}
+	if body.Network != papi.ActivationNetworkStaging && body.Network != papi.ActivationNetworkProduction {
+		writeError(w, http.StatusBadRequest, "The network must be STAGING or PRODUCTION")
+		return
+	}
+	if body.ActivationType == "" {
+		body.ActivationType = papi.ActivationTypeActivate
+	}
+	for _, a := range s.papi.activations {
+		if a.PropertyID == prp.PropertyID && a.Network == body.Network && a.pending != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Activation %s is still pending on %s", a.ActivationID, a.Network)
+			return
+		}
+	}
+
+	a := &activation{
+		Activation: papi.Activation{
+			AccountID:       AccountID,
+			ActivationID:    fmt.Sprintf("atv_%d", s.nextID()),
+			ActivationType:  body.ActivationType,
+			GroupID:         prp.GroupID,
+			PropertyName:    prp.PropertyName,
+			PropertyID:      prp.PropertyID,
+			PropertyVersion: body.PropertyVersion,
+			Network:         body.Network,
+			Status:          papi.ActivationStatusPending,
+			SubmitDate:      s.timestamp(),
+			UpdateDate:      s.timestamp(),
+			Note:            body.Note,
+			NotifyEmails:    body.NotifyEmails,
+			UseFastFallback: body.UseFastFallback,
+		},
+		pending: s.newPending(),
+	}
+	if a.pending.done() {
+		a.pending = nil
+		s.completeActivation(prp, a)
+	} else {
+		status := papi.VersionStatusPending
+		if a.Network == papi.ActivationNetworkProduction {
+			prp.versions[a.PropertyVersion-1].ProductionStatus = status
+		} else {
+			prp.versions[a.PropertyVersion-1].StagingStatus = status
+		}
+	}
+	s.papi.activations = append(s.papi.activations, a)
+
+	writeJSON(w, http.StatusCreated, papi.CreateActivationResponse{
+		ActivationLink: fmt.Sprintf("/papi/v1/properties/%s/activations/%s?contractId=%s&groupId=%s",
+			prp.PropertyID, a.ActivationID, prp.ContractID, prp.GroupID),
+	})
+}
+
+func (s *Server) papiCancelActivation(w http.ResponseWriter, _ *http.Request, p params) {
+	prp := s.property(w, p)
+	if prp == nil {
+		return
+	}
+	a := s.activation(w, prp, p)
+	if a == nil {
+		return
+	}
+	if a.pending == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Activation %s is not pending and cannot be canceled", a.ActivationID)
+		return
+	}
+
+	a.pending = nil
+	a.Status = papi.ActivationStatusAborted
+	version := prp.versions[a.PropertyVersion-1]
+	if a.Network == papi.ActivationNetworkProduction {
+		version.ProductionStatus = papi.VersionStatusInactive
+	} else {
+		version.StagingStatus = papi.VersionStatusInactive
+	}
+
+	item := a.Activation
+	writeJSON(w, http.StatusOK, papi.CancelActivationResponse{
+		Activations: papi.ActivationsItems{Items: []*papi.Activation{&item}},
+	})
+}
+
+func (s *Server) papiSearch(w http.ResponseWriter, r *http.Request, _ params) {
+	var body map[string]string
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if len(body) != 1 {
+		writeError(w, http.StatusBadRequest, "Exactly one of propertyName, hostname or edgeHostname is required")
+		return
+	}
+
+	items := make([]papi.SearchItem, 0)
+	for _, prp := range s.sortedProperties() {
+		for _, v := range prp.versions {
+			latest := v.PropertyVersion == prp.LatestVersion
+			active := v.StagingStatus == papi.VersionStatusActive || v.ProductionStatus == papi.VersionStatusActive
+			if !latest && !active {
+				continue
+			}
+			item := papi.SearchItem{
+				AccountID:        AccountID,
+				AssetID:          prp.AssetID,
+				ContractID:       prp.ContractID,
+				GroupID:          prp.GroupID,
+				ProductionStatus: string(v.ProductionStatus),
+				PropertyID:       prp.PropertyID,
+				PropertyName:     prp.PropertyName,
+				PropertyVersion:  v.PropertyVersion,
+				StagingStatus:    string(v.StagingStatus),
+				UpdatedByUser:    v.UpdatedByUser,
+				UpdatedDate:      v.UpdatedDate,
+			}
+			for key, value := range body {
+				switch key {
+				case "propertyName":
+					if prp.PropertyName == value {
+						items = append(items, item)
+					}
+				case "hostname", "edgeHostname":
+					for _, h := range v.hostnames {
+						if (key == "hostname" && h.CnameFrom == value) || (key == "edgeHostname" && h.CnameTo == value) {
+							item.Hostname, item.EdgeHostname = h.CnameFrom, h.CnameTo
+							items = append(items, item)
+						}
+					}
+				default:
+					writeError(w, http.StatusBadRequest, "Unsupported search key %s", key)
+					return
+				}
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, papi.SearchResponse{Versions: papi.SearchItems{Items: items}})
+}
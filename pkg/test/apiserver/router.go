@@ -0,0 +1,86 @@
+package apiserver
+
+import (
+	"net/http"
+	"strings"
+)
+
+type (
+	// params holds the values of the {name} segments of a route pattern
+	params map[string]string
+
+	handlerFunc func(w http.ResponseWriter, r *http.Request, p params)
+
+	route struct {
+		method   string
+		segments []string
+		handler  handlerFunc
+	}
+
+	// router matches the request paths segment by segment, trailing slashes are ignored as
+	// some clients call /config-dns/v2/zones/ while others call /config-dns/v2/zones
+	router struct {
+		routes []route
+	}
+)
+
+func (rt *router) handle(method, pattern string, h handlerFunc) {
+	rt.routes = append(rt.routes, route{
+		method:   method,
+		segments: splitPath(pattern),
+		handler:  h,
+	})
+}
+
+// ServeHTTP implements the http.Handler interface
+func (rt *router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := splitPath(r.URL.Path)
+
+	var methodMismatch bool
+	for _, rte := range rt.routes {
+		p, ok := rte.match(segments)
+		if !ok {
+			continue
+		}
+		if rte.method != r.Method {
+			methodMismatch = true
+			continue
+		}
+		rte.handler(w, r, p)
+		return
+	}
+
+	if methodMismatch {
+		writeError(w, http.StatusMethodNotAllowed, "Method %s is not supported on %s", r.Method, r.URL.Path)
+		return
+	}
+	writeError(w, http.StatusNotFound, "The resource %s is not implemented by the stand-in server", r.URL.Path)
+}
+
+func (rte route) match(segments []string) (params, bool) {
+	if len(segments) != len(rte.segments) {
+		return nil, false
+	}
+
+	p := make(params)
+	for i, s := range rte.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			p[s[1:len(s)-1]] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+
+	return p, true
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
@@ -0,0 +1,262 @@
+// Package apiserver is an in-memory stand-in for the Akamai APIs called by the provider resources
+//
+// It implements the subset of the PAPI, Edge Hostnames, CP Codes and Reporting Groups, Edge DNS, GTM, AppSec and IAM
+// endpoints used by the provider, keeps the created objects in memory and simulates the asynchronous behaviors of the
+// real APIs:
+// activations, edge hostname change requests and zone or domain changes stay pending for a configurable number
+// of status reads.
+//
+// Point the provider at it with the host of an inline config block or edgerc section, the credentials are not verified:
+//
+//	srv := apiserver.NewServer()
+//	defer srv.Close()
+//	akamai.SetHTTPTransport(srv.Client().Transport)
+package apiserver
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AccountID is the account all the objects of the server belong to
+	AccountID = "act_1-TEST"
+
+	// ContractID is the contract available to the server objects
+	ContractID = "ctr_1-TEST"
+
+	// GroupID is the top level group available to the server objects
+	GroupID = "grp_10000"
+
+	// DefaultPendingPolls is the number of status reads an asynchronous change stays pending for
+	DefaultPendingPolls = 1
+
+	authorizationPrefix = "EG1-HMAC-SHA256 "
+)
+
+type (
+	// Server is the Akamai API stand-in
+	Server struct {
+		*httptest.Server
+
+		addr         string
+		pendingPolls int
+		log          io.Writer
+		now          func() time.Time
+
+		mu     sync.Mutex
+		router *router
+		lastID int
+
+		papi   *papiState
+		hapi   *hapiState
+		cprg   *cprgState
+		dns    *dnsState
+		gtm    *gtmState
+		appsec *appsecState
+		iam    *iamState
+	}
+
+	// Option is a server option
+	Option func(*Server)
+)
+
+// WithAddress sets the address the server listens on, a random local port is used by default
+func WithAddress(addr string) Option {
+	return func(s *Server) {
+		s.addr = addr
+	}
+}
+
+// WithPendingPolls sets the number of status reads activations and propagations stay pending for
+func WithPendingPolls(n int) Option {
+	return func(s *Server) {
+		s.pendingPolls = n
+	}
+}
+
+// WithLog writes a line per request to w
+func WithLog(w io.Writer) Option {
+	return func(s *Server) {
+		s.log = w
+	}
+}
+
+// NewHandler returns the server without starting it, its Handler serves the API requests
+func NewHandler(opts ...Option) *Server {
+	s := &Server{
+		pendingPolls: DefaultPendingPolls,
+		now:          time.Now,
+		router:       &router{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.papi = newPAPIState()
+	s.hapi = newHAPIState()
+	s.cprg = newCPRGState()
+	s.dns = newDNSState()
+	s.gtm = newGTMState()
+	s.appsec = newAppSecState()
+	s.iam = newIAMState()
+
+	s.registerPAPI()
+	s.registerHAPI()
+	s.registerCPRG()
+	s.registerDNS()
+	s.registerGTM()
+	s.registerAppSec()
+	s.registerIAM()
+
+	return s
+}
+
+// NewServer returns a started TLS server, it panics if the listen address is not available
+func NewServer(opts ...Option) *Server {
+	s := NewHandler(opts...)
+	s.Server = httptest.NewUnstartedServer(s)
+	if s.addr != "" {
+		l, err := net.Listen("tcp", s.addr)
+		if err != nil {
+			panic(fmt.Sprintf("apiserver: listening on %s: %s", s.addr, err))
+		}
+		s.Server.Listener.Close()
+		s.Server.Listener = l
+	}
+	s.Server.StartTLS()
+
+	return s
+}
+
+// Host returns the host and port to set as the EdgeGrid host
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.URL, "https://")
+}
+
+// CertificatePEM returns the PEM encoded certificate of the server, clients outside of the process have to trust it
+func (s *Server) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+}
+
+// Edgerc returns an edgerc section pointing to the server
+func (s *Server) Edgerc(section string) string {
+	return fmt.Sprintf(`[%s]
+host = %s
+client_token = akab-client-token-xxx-xxxxxxxxxxxxxxxx
+client_secret = client-secret-xxxxxxxxxxxxxxxxxxxxxxxxxxxxxx=
+access_token = akab-access-token-xxx-xxxxxxxxxxxxxxxx
+`, section, s.Host())
+}
+
+// ServeHTTP implements the http.Handler interface
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	start := time.Now()
+	defer func() {
+		if s.log != nil {
+			fmt.Fprintf(s.log, "%s %s %s %d %s\n", start.Format(time.RFC3339), r.Method, r.URL.RequestURI(), rec.status, time.Since(start))
+		}
+	}()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), authorizationPrefix) {
+		writeError(rec, http.StatusUnauthorized, "The request is not signed with EdgeGrid credentials")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.router.ServeHTTP(rec, r)
+}
+
+// nextID returns a number unique across all the objects of the server
+func (s *Server) nextID() int {
+	s.lastID++
+	return s.lastID
+}
+
+// timestamp returns the current time in the format used by the APIs
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// pending tracks an asynchronous change which completes after a number of status reads
+type pending struct {
+	polls int
+}
+
+func (s *Server) newPending() *pending {
+	return &pending{polls: s.pendingPolls}
+}
+
+// poll records a status read and tells whether the change is complete
+func (p *pending) poll() bool {
+	if p.polls <= 0 {
+		return true
+	}
+	p.polls--
+
+	return false
+}
+
+// done tells whether the change is complete without counting a read
+func (p *pending) done() bool {
+	return p.polls <= 0
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// problem is the error body returned by all the APIs
+type problem struct {
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Detail     string `json:"detail"`
+	Instance   string `json:"instance,omitempty"`
+	StatusCode int    `json:"statusCode"`
+}
+
+func writeError(w http.ResponseWriter, status int, detail string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem{
+		Type:       fmt.Sprintf("https://problems.luna.akamaiapis.net/%d", status),
+		Title:      http.StatusText(status),
+		Detail:     fmt.Sprintf(detail, args...),
+		StatusCode: status,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// readJSON decodes the request body into v, it writes a bad request error and returns false on failure
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "The request body is not valid JSON: %s", err)
+		return false
+	}
+
+	return true
+}
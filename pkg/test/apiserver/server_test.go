@@ -0,0 +1,433 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/appsec"
+	dns "github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/configdns"
+	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/configgtm"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/iam"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSession(t *testing.T, srv *Server) session.Session {
+	sess, err := session.New(
+		session.WithClient(srv.Client()),
+		session.WithSigner(&edgegrid.Config{
+			Host:         srv.Host(),
+			ClientToken:  "client_token",
+			ClientSecret: "client_secret",
+			AccessToken:  "access_token",
+			MaxBody:      edgegrid.MaxBodySize,
+		}),
+	)
+	require.NoError(t, err)
+
+	return sess
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(WithPendingPolls(2))
+	defer srv.Close()
+	sess := newSession(t, srv)
+
+	t.Run("unsigned request", func(t *testing.T) {
+		resp, err := srv.Client().Get(srv.URL + "/papi/v1/groups")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/papi/v1/unknown", nil)
+		require.NoError(t, err)
+		resp, err := sess.Exec(req, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("papi property lifecycle", func(t *testing.T) {
+		client := papi.Client(sess)
+
+		cpCode, err := client.CreateCPCode(ctx, papi.CreateCPCodeRequest{
+			ContractID: ContractID,
+			GroupID:    GroupID,
+			CPCode:     papi.CreateCPCode{ProductID: "prd_Fresca", CPCodeName: "test"},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, cpCode.CPCodeID)
+
+		ehn, err := client.CreateEdgeHostname(ctx, papi.CreateEdgeHostnameRequest{
+			ContractID: ContractID,
+			GroupID:    GroupID,
+			EdgeHostname: papi.EdgeHostnameCreate{
+				ProductID:         "prd_Fresca",
+				DomainPrefix:      "www.example.com",
+				DomainSuffix:      "edgesuite.net",
+				IPVersionBehavior: "IPV4",
+			},
+		})
+		require.NoError(t, err)
+
+		prp, err := client.CreateProperty(ctx, papi.CreatePropertyRequest{
+			ContractID: ContractID,
+			GroupID:    GroupID,
+			Property:   papi.PropertyCreate{ProductID: "prd_Fresca", PropertyName: "test"},
+		})
+		require.NoError(t, err)
+
+		_, err = client.UpdateRuleTree(ctx, papi.UpdateRulesRequest{
+			PropertyID:      prp.PropertyID,
+			PropertyVersion: 1,
+			ContractID:      ContractID,
+			GroupID:         GroupID,
+			Rules: papi.RulesUpdate{Rules: papi.Rules{
+				Name:      "default",
+				Behaviors: []papi.RuleBehavior{{Name: "cpCode", Options: papi.RuleOptionsMap{"value": map[string]interface{}{"id": cpCode.CPCodeID}}}},
+			}},
+		})
+		require.NoError(t, err)
+
+		_, err = client.UpdatePropertyVersionHostnames(ctx, papi.UpdatePropertyVersionHostnamesRequest{
+			PropertyID:      prp.PropertyID,
+			PropertyVersion: 1,
+			ContractID:      ContractID,
+			GroupID:         GroupID,
+			Hostnames:       []papi.Hostname{{CnameFrom: "www.example.com", CnameTo: "www.example.com.edgesuite.net"}},
+		})
+		require.NoError(t, err)
+
+		// the version notes are the comments of the rule tree, set with a JSON patch
+		req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/papi/v1/properties/%s/versions/1/rules?contractId=%s&groupId=%s", prp.PropertyID, ContractID, GroupID), nil)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json-patch+json")
+		resp, err := sess.Exec(req, nil, []map[string]interface{}{{"op": "add", "path": "/comments", "value": "first version"}})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		version, err := client.GetPropertyVersion(ctx, papi.GetPropertyVersionRequest{PropertyID: prp.PropertyID, PropertyVersion: 1, ContractID: ContractID, GroupID: GroupID})
+		require.NoError(t, err)
+		assert.Equal(t, "first version", version.Version.Note)
+
+		act, err := client.CreateActivation(ctx, papi.CreateActivationRequest{
+			PropertyID: prp.PropertyID,
+			ContractID: ContractID,
+			GroupID:    GroupID,
+			Activation: papi.Activation{
+				PropertyVersion: 1,
+				Network:         papi.ActivationNetworkStaging,
+				ActivationType:  papi.ActivationTypeActivate,
+				NotifyEmails:    []string{"noreply@example.com"},
+			},
+		})
+		require.NoError(t, err)
+
+		// the activation stays pending for the configured number of status reads
+		statuses := make([]papi.ActivationStatus, 0, 3)
+		for i := 0; i < 3; i++ {
+			got, err := client.GetActivation(ctx, papi.GetActivationRequest{PropertyID: prp.PropertyID, ActivationID: act.ActivationID})
+			require.NoError(t, err)
+			statuses = append(statuses, got.Activation.Status)
+		}
+		assert.Equal(t, []papi.ActivationStatus{papi.ActivationStatusPending, papi.ActivationStatusPending, papi.ActivationStatusActive}, statuses)
+
+		got, err := client.GetProperty(ctx, papi.GetPropertyRequest{PropertyID: prp.PropertyID})
+		require.NoError(t, err)
+		require.NotNil(t, got.Property.StagingVersion)
+		assert.Equal(t, 1, *got.Property.StagingVersion)
+
+		hostnames, err := client.GetPropertyVersionHostnames(ctx, papi.GetPropertyVersionHostnamesRequest{PropertyID: prp.PropertyID, PropertyVersion: 1, ContractID: ContractID, GroupID: GroupID})
+		require.NoError(t, err)
+		require.Len(t, hostnames.Hostnames.Items, 1)
+		assert.Equal(t, ehn.EdgeHostnameID, hostnames.Hostnames.Items[0].EdgeHostnameID)
+
+		_, err = client.RemoveProperty(ctx, papi.RemovePropertyRequest{PropertyID: prp.PropertyID})
+		assert.Error(t, err, "an active property cannot be removed")
+	})
+
+	t.Run("hapi edge hostname changes", func(t *testing.T) {
+		_, err := papi.Client(sess).CreateEdgeHostname(ctx, papi.CreateEdgeHostnameRequest{
+			ContractID: ContractID,
+			GroupID:    GroupID,
+			EdgeHostname: papi.EdgeHostnameCreate{
+				ProductID:         "prd_Fresca",
+				DomainPrefix:      "hapi.example.com",
+				DomainSuffix:      "edgesuite.net",
+				IPVersionBehavior: "IPV4",
+			},
+		})
+		require.NoError(t, err)
+
+		exec := func(method, path string, in, out interface{}) int {
+			req, err := http.NewRequest(method, path, nil)
+			require.NoError(t, err)
+			var resp *http.Response
+			if in != nil {
+				req.Header.Set("Content-Type", "application/json-patch+json")
+				resp, err = sess.Exec(req, out, in)
+			} else {
+				resp, err = sess.Exec(req, out)
+			}
+			require.NoError(t, err)
+			return resp.StatusCode
+		}
+		type change struct {
+			ChangeID int    `json:"changeId"`
+			Status   string `json:"status"`
+		}
+		// the change stays pending for the configured number of status reads and is applied once it completes
+		waitChange := func(c change) {
+			var statuses []string
+			for c.Status == "PENDING" {
+				require.Equal(t, http.StatusOK, exec(http.MethodGet, fmt.Sprintf("/hapi/v1/change-requests/%d", c.ChangeID), nil, &c))
+				statuses = append(statuses, c.Status)
+			}
+			assert.Equal(t, []string{"PENDING", "PENDING", "SUCCEEDED"}, statuses)
+		}
+
+		var ehn hapiEdgeHostname
+		require.Equal(t, http.StatusOK, exec(http.MethodGet, "/hapi/v1/edge-hostnames/hapi.example.com/edgesuite.net", nil, &ehn))
+		assert.Equal(t, hapiEdgeHostname{
+			EdgeHostnameID: ehn.EdgeHostnameID, RecordName: "hapi.example.com", DNSZone: "edgesuite.net", SecurityType: "STANDARD-TLS",
+			UseDefaultTTL: true, UseDefaultMap: true, TTL: hapiDefaultTTL, Map: hapiDefaultMap, IPVersionBehavior: "IPV4",
+		}, ehn)
+
+		var c change
+		patch := []map[string]interface{}{
+			{"op": "replace", "path": "/ipVersionBehavior", "value": "IPV6_COMPLIANCE"},
+			{"op": "replace", "path": "/ttl", "value": 300},
+		}
+		require.Equal(t, http.StatusAccepted, exec(http.MethodPatch, "/hapi/v1/edge-hostnames/hapi.example.com/edgesuite.net", patch, &c))
+		waitChange(c)
+
+		require.Equal(t, http.StatusOK, exec(http.MethodGet, "/hapi/v1/edge-hostnames/hapi.example.com/edgesuite.net", nil, &ehn))
+		assert.Equal(t, "IPV6_COMPLIANCE", ehn.IPVersionBehavior)
+		assert.Equal(t, 300, ehn.TTL)
+		assert.False(t, ehn.UseDefaultTTL)
+		assert.True(t, ehn.UseDefaultMap)
+
+		patch = []map[string]interface{}{{"op": "replace", "path": "/recordName", "value": "other"}}
+		assert.Equal(t, http.StatusBadRequest, exec(http.MethodPatch, "/hapi/v1/edge-hostnames/hapi.example.com/edgesuite.net", patch, nil))
+
+		require.Equal(t, http.StatusAccepted, exec(http.MethodDelete, "/hapi/v1/edge-hostnames/hapi.example.com/edgesuite.net", nil, &c))
+		waitChange(c)
+		assert.Equal(t, http.StatusNotFound, exec(http.MethodGet, "/hapi/v1/edge-hostnames/hapi.example.com/edgesuite.net", nil, nil))
+	})
+
+	t.Run("cprg cp code rename and reporting groups", func(t *testing.T) {
+		created, err := papi.Client(sess).CreateCPCode(ctx, papi.CreateCPCodeRequest{
+			ContractID: ContractID,
+			GroupID:    GroupID,
+			CPCode:     papi.CreateCPCode{ProductID: "prd_Fresca", CPCodeName: "cprg"},
+		})
+		require.NoError(t, err)
+		cpCodeID, err := strconv.Atoi(strings.TrimPrefix(created.CPCodeID, "cpc_"))
+		require.NoError(t, err)
+
+		exec := func(method, path string, in, out interface{}) int {
+			req, err := http.NewRequest(method, path, nil)
+			require.NoError(t, err)
+			var resp *http.Response
+			if in != nil {
+				req.Header.Set("Content-Type", "application/json")
+				resp, err = sess.Exec(req, out, in)
+			} else {
+				resp, err = sess.Exec(req, out)
+			}
+			require.NoError(t, err)
+			return resp.StatusCode
+		}
+
+		var cpCode cprgCPCode
+		cpCodePath := fmt.Sprintf("/cprg/v1/cpcodes/%d", cpCodeID)
+		require.Equal(t, http.StatusOK, exec(http.MethodGet, cpCodePath, nil, &cpCode))
+		assert.Equal(t, "cprg", cpCode.CPCodeName)
+		cpCode.CPCodeName = "renamed"
+		require.Equal(t, http.StatusOK, exec(http.MethodPut, cpCodePath, cpCode, &cpCode))
+
+		got, err := papi.Client(sess).GetCPCode(ctx, papi.GetCPCodeRequest{CPCodeID: created.CPCodeID, ContractID: ContractID, GroupID: GroupID})
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", got.CPCode.Name, "the rename is visible through PAPI")
+
+		group := cprgReportingGroup{
+			ReportingGroupName: "reports",
+			Contracts:          []cprgReportingGroupContract{{ContractID: "1-TEST", CPCodes: []cprgReportingGroupCPCode{{CPCodeID: cpCodeID}}}},
+			AccessGroup:        cprgAccessGroup{GroupID: 10000, ContractID: "1-TEST"},
+		}
+		require.Equal(t, http.StatusCreated, exec(http.MethodPost, "/cprg/v1/reporting-groups", group, &group))
+		assert.Equal(t, "renamed", group.Contracts[0].CPCodes[0].CPCodeName)
+
+		groupPath := fmt.Sprintf("/cprg/v1/reporting-groups/%d", group.ReportingGroupID)
+		group.ReportingGroupName = "all reports"
+		require.Equal(t, http.StatusOK, exec(http.MethodPut, groupPath, group, nil))
+		require.Equal(t, http.StatusOK, exec(http.MethodGet, groupPath, nil, &group))
+		assert.Equal(t, "all reports", group.ReportingGroupName)
+
+		group.Contracts[0].CPCodes = []cprgReportingGroupCPCode{{CPCodeID: 999999}}
+		assert.Equal(t, http.StatusBadRequest, exec(http.MethodPut, groupPath, group, nil), "the CP codes must exist")
+
+		require.Equal(t, http.StatusNoContent, exec(http.MethodDelete, groupPath, nil, nil))
+		assert.Equal(t, http.StatusNotFound, exec(http.MethodGet, groupPath, nil, nil))
+	})
+
+	t.Run("dns zone and records", func(t *testing.T) {
+		client := dns.Client(sess)
+		zone := &dns.ZoneCreate{Zone: "example.com", Type: "PRIMARY", ContractID: ContractID}
+
+		require.NoError(t, client.CreateZone(ctx, zone, dns.ZoneQueryString{Contract: ContractID, Group: GroupID}))
+		require.NoError(t, client.SaveChangelist(ctx, zone))
+		require.NoError(t, client.SubmitChangelist(ctx, zone))
+
+		states := make([]string, 0, 3)
+		for i := 0; i < 3; i++ {
+			got, err := client.GetZone(ctx, "example.com")
+			require.NoError(t, err)
+			states = append(states, got.ActivationState)
+		}
+		assert.Equal(t, []string{"PENDING", "PENDING", "ACTIVE"}, states)
+
+		record := &dns.RecordBody{Name: "www.example.com", RecordType: "A", TTL: 300, Target: []string{"192.0.2.1"}}
+		require.NoError(t, client.CreateRecord(ctx, record, "example.com"))
+		assert.Error(t, client.CreateRecord(ctx, record, "example.com"), "the record already exists")
+
+		soa, err := client.GetRecord(ctx, "example.com", "example.com", "SOA")
+		require.NoError(t, err)
+		assert.Contains(t, soa.Target[0], " 2 3600 ", "the SOA serial is incremented by record changes")
+		err = client.UpdateRecord(ctx, soa, "example.com")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "SOA serial number must be incremented")
+
+		require.NoError(t, client.DeleteRecord(ctx, record, "example.com"))
+		_, err = client.GetRecord(ctx, "example.com", "www.example.com", "A")
+		assert.Error(t, err)
+	})
+
+	t.Run("gtm domain", func(t *testing.T) {
+		client := gtm.Client(sess)
+		domain := client.NewDomain(ctx, "test.akadns.net", "basic")
+
+		_, err := client.CreateDomain(ctx, domain, map[string]string{"contractId": ContractID, "gid": GroupID})
+		require.NoError(t, err)
+
+		statuses := make([]string, 0, 3)
+		for i := 0; i < 3; i++ {
+			got, err := client.GetDomainStatus(ctx, "test.akadns.net")
+			require.NoError(t, err)
+			statuses = append(statuses, got.PropagationStatus)
+		}
+		assert.Equal(t, []string{"PENDING", "PENDING", "COMPLETE"}, statuses)
+
+		dc := client.NewDatacenter(ctx)
+		dc.Nickname = "test"
+		created, err := client.CreateDatacenter(ctx, dc, "test.akadns.net")
+		require.NoError(t, err)
+		assert.NotZero(t, created.Resource.DatacenterId)
+
+		def, err := client.CreateMapsDefaultDatacenter(ctx, "test.akadns.net")
+		require.NoError(t, err)
+		assert.Equal(t, gtm.MapDefaultDC, def.DatacenterId)
+
+		prop := client.NewProperty(ctx, "www")
+		prop.Type = "failover"
+		prop.ScoreAggregationType = "median"
+		prop.HandoutLimit = 8
+		prop.HandoutMode = "normal"
+		prop.TrafficTargets = []*gtm.TrafficTarget{{DatacenterId: created.Resource.DatacenterId, Enabled: true, Weight: 1, Servers: []string{"192.0.2.1"}}}
+		_, err = client.CreateProperty(ctx, prop, "test.akadns.net")
+		require.NoError(t, err)
+
+		got, err := client.GetDomain(ctx, "test.akadns.net")
+		require.NoError(t, err)
+		assert.Len(t, got.Datacenters, 2)
+		require.Len(t, got.Properties, 1)
+		assert.Equal(t, "www", got.Properties[0].Name)
+
+		_, err = client.DeleteProperty(ctx, prop, "test.akadns.net")
+		require.NoError(t, err)
+		_, err = client.GetProperty(ctx, "www", "test.akadns.net")
+		assert.Error(t, err)
+	})
+
+	t.Run("appsec configuration", func(t *testing.T) {
+		client := appsec.Client(sess)
+
+		version, err := client.CreateConfigurationClone(ctx, appsec.CreateConfigurationCloneRequest{ConfigID: AppSecConfigID, CreateFromVersion: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 2, version.Version)
+
+		policy, err := client.CreateSecurityPolicy(ctx, appsec.CreateSecurityPolicyRequest{ConfigID: AppSecConfigID, Version: 2, PolicyName: "test", PolicyPrefix: "TEST"})
+		require.NoError(t, err)
+		assert.Contains(t, policy.PolicyID, "TEST_")
+
+		_, err = client.UpdateWAFMode(ctx, appsec.UpdateWAFModeRequest{ConfigID: AppSecConfigID, Version: 2, PolicyID: policy.PolicyID, Mode: "AAG"})
+		require.NoError(t, err)
+		mode, err := client.GetWAFMode(ctx, appsec.GetWAFModeRequest{ConfigID: AppSecConfigID, Version: 2, PolicyID: policy.PolicyID})
+		require.NoError(t, err)
+		assert.Equal(t, "AAG", mode.Mode)
+
+		req := appsec.CreateActivationsRequest{Action: "ACTIVATE", Network: "STAGING"}
+		req.ActivationConfigs = append(req.ActivationConfigs, struct {
+			ConfigID      int `json:"configId"`
+			ConfigVersion int `json:"configVersion"`
+		}{ConfigID: AppSecConfigID, ConfigVersion: 2})
+		act, err := client.CreateActivations(ctx, req, true)
+		require.NoError(t, err)
+		assert.Equal(t, appsec.StatusPending, act.Status)
+
+		var status appsec.StatusValue
+		for i := 0; i < 3; i++ {
+			got, err := client.GetActivations(ctx, appsec.GetActivationsRequest{ActivationID: act.ActivationID})
+			require.NoError(t, err)
+			status = got.Status
+		}
+		assert.Equal(t, appsec.StatusActive, status)
+
+		_, err = client.UpdateWAFMode(ctx, appsec.UpdateWAFModeRequest{ConfigID: AppSecConfigID, Version: 2, PolicyID: policy.PolicyID, Mode: "KRS"})
+		assert.Error(t, err, "an activated version cannot be modified")
+	})
+
+	t.Run("iam user", func(t *testing.T) {
+		client := iam.Client(sess)
+		roleID, timeout := 12, 1800
+
+		user, err := client.CreateUser(ctx, iam.CreateUserRequest{
+			User:          iam.UserBasicInfo{FirstName: "John", LastName: "Doe", Email: "john.doe@example.com", Phone: "(555) 555-5555", Country: "USA"},
+			AuthGrants:    []iam.AuthGrant{{GroupID: 10000, RoleID: &roleID}},
+			Notifications: iam.UserNotifications{EnableEmail: true},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, user.IdentityID)
+
+		got, err := client.GetUser(ctx, iam.GetUserRequest{IdentityID: user.IdentityID, AuthGrants: true})
+		require.NoError(t, err)
+		require.Len(t, got.AuthGrants, 1)
+		assert.Equal(t, "Admin", got.AuthGrants[0].RoleName)
+
+		_, err = client.UpdateUserInfo(ctx, iam.UpdateUserInfoRequest{IdentityID: user.IdentityID, User: iam.UserBasicInfo{
+			FirstName:         "Jane",
+			LastName:          "Doe",
+			Email:             "jane.doe@example.com",
+			Phone:             "(555) 555-5555",
+			Country:           "Poland",
+			ContactType:       "Billing",
+			TimeZone:          "GMT",
+			PreferredLanguage: "English",
+			SessionTimeOut:    &timeout,
+		}})
+		require.NoError(t, err)
+
+		require.NoError(t, client.RemoveUser(ctx, iam.RemoveUserRequest{IdentityID: user.IdentityID}))
+		_, err = client.GetUser(ctx, iam.GetUserRequest{IdentityID: user.IdentityID})
+		assert.Error(t, err)
+	})
+}
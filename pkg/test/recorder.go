@@ -0,0 +1,333 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// ModeReplay serves the responses stored in the cassette, no request leaves the process
+	ModeReplay Mode = iota
+
+	// ModeRecord sends the requests to the API and stores the exchanges in the cassette
+	ModeRecord
+)
+
+const (
+	// ScrubbedHost replaces the EdgeGrid host of the recorded requests
+	ScrubbedHost = "akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net"
+)
+
+var (
+	// ErrNoInteraction is returned in replay mode when the cassette holds no response for a request
+	ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+	// recordedHeaders are the only request headers stored in a cassette, Authorization holds the signature
+	recordedHeaders = []string{"Content-Type", "Accept", "If-Match"}
+
+	// scrubbedQueryParams are removed from the recorded urls
+	scrubbedQueryParams = []string{"accountSwitchKey"}
+
+	// scrubbedResponseHeaders are removed from the recorded responses
+	scrubbedResponseHeaders = []string{"Set-Cookie", "Authorization", "X-Akamai-Request-Id", "X-Trace-Id"}
+)
+
+type (
+	// Mode is the recorder mode
+	Mode int
+
+	// Cassette holds the recorded HTTP exchanges of a test
+	Cassette struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+
+	// Interaction is a single recorded request and its response
+	Interaction struct {
+		Request  RecordedRequest  `json:"request"`
+		Response RecordedResponse `json:"response"`
+	}
+
+	// RecordedRequest is the scrubbed request of an interaction
+	RecordedRequest struct {
+		Method  string      `json:"method"`
+		URL     string      `json:"url"`
+		Headers http.Header `json:"headers,omitempty"`
+		Body    string      `json:"body,omitempty"`
+	}
+
+	// RecordedResponse is the scrubbed response of an interaction
+	RecordedResponse struct {
+		StatusCode int         `json:"status_code"`
+		Headers    http.Header `json:"headers,omitempty"`
+		Body       string      `json:"body,omitempty"`
+	}
+
+	// Scrubber removes sensitive data from an interaction before it is saved
+	Scrubber func(*Interaction)
+
+	// RecorderOption is a recorder option
+	RecorderOption func(*Recorder)
+
+	// Recorder is a http.RoundTripper capturing EdgeGrid exchanges to a cassette file and replaying them
+	//
+	// Use it as the provider transport so the signer, session, clients and resources all run unchanged:
+	//   rec, err := test.NewRecorder("testdata/cassettes/TestDataContracts.json")
+	//   akamai.SetHTTPTransport(rec)
+	//   defer akamai.SetHTTPTransport(nil)
+	//   ... run the test steps ...
+	//   err = rec.Stop()
+	Recorder struct {
+		mode      Mode
+		path      string
+		next      http.RoundTripper
+		scrubbers []Scrubber
+
+		mu       sync.Mutex
+		cassette Cassette
+		used     []bool
+	}
+)
+
+// WithMode sets the recorder mode, by default it is read from the TEST_RECORD env variable
+func WithMode(mode Mode) RecorderOption {
+	return func(r *Recorder) {
+		r.mode = mode
+	}
+}
+
+// WithTransport sets the transport the requests are sent with in record mode
+func WithTransport(next http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.next = next
+	}
+}
+
+// WithScrubbers adds scrubbers applied to each interaction after the default ones
+func WithScrubbers(scrubbers ...Scrubber) RecorderOption {
+	return func(r *Recorder) {
+		r.scrubbers = append(r.scrubbers, scrubbers...)
+	}
+}
+
+// ScrubString replaces every occurrence of value in the urls and bodies of an interaction
+// Use it for account or contract identifiers which should not be published with the cassette
+func ScrubString(value, replacement string) Scrubber {
+	return func(i *Interaction) {
+		if value == "" {
+			return
+		}
+		i.Request.URL = strings.ReplaceAll(i.Request.URL, value, replacement)
+		i.Request.Body = strings.ReplaceAll(i.Request.Body, value, replacement)
+		i.Response.Body = strings.ReplaceAll(i.Response.Body, value, replacement)
+		for _, values := range i.Response.Headers {
+			for n := range values {
+				values[n] = strings.ReplaceAll(values[n], value, replacement)
+			}
+		}
+	}
+}
+
+// RecorderModeFromEnv returns ModeRecord when the TEST_RECORD env variable is not empty
+func RecorderModeFromEnv() Mode {
+	if os.Getenv("TEST_RECORD") != "" {
+		return ModeRecord
+	}
+
+	return ModeReplay
+}
+
+// NewRecorder returns a recorder for the cassette at path
+// In replay mode the cassette is loaded immediately, in record mode it is written by Stop
+func NewRecorder(path string, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		mode: RecorderModeFromEnv(),
+		path: path,
+		next: http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading cassette, set TEST_RECORD=1 in env to record it: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the recorder mode
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	recorded := RecordedRequest{
+		Method:  req.Method,
+		URL:     scrubURL(req.URL),
+		Headers: http.Header{},
+		Body:    string(body),
+	}
+	for _, h := range recordedHeaders {
+		if v := req.Header.Get(h); v != "" {
+			recorded.Headers.Set(h, v)
+		}
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for n, i := range r.cassette.Interactions {
+		if r.used[n] || !matchRequest(i.Request, recorded) {
+			continue
+		}
+		r.used[n] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Response.Headers.Clone(),
+			Body:          ioutil.NopCloser(strings.NewReader(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.URL)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	i := Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    resp.Header.Clone(),
+			Body:       string(body),
+		},
+	}
+	for _, h := range scrubbedResponseHeaders {
+		i.Response.Headers.Del(h)
+	}
+	if host := req.URL.Host; host != "" {
+		ScrubString(host, ScrubbedHost)(&i)
+	}
+	for _, scrub := range r.scrubbers {
+		scrub(&i)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Stop writes the cassette in record mode
+// In replay mode it returns an error listing the interactions which were never requested
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeReplay {
+		var unused []string
+		for n, i := range r.cassette.Interactions {
+			if !r.used[n] {
+				unused = append(unused, fmt.Sprintf("%s %s", i.Request.Method, i.Request.URL))
+			}
+		}
+		if len(unused) > 0 {
+			return fmt.Errorf("recorded interactions were not requested: %s", strings.Join(unused, ", "))
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.path, data, 0644)
+}
+
+// scrubURL returns the request path and query, without the host and the account switch key
+func scrubURL(u *url.URL) string {
+	query := u.Query()
+	for _, param := range scrubbedQueryParams {
+		query.Del(param)
+	}
+
+	scrubbed := url.URL{Path: u.Path, RawQuery: query.Encode()}
+
+	return scrubbed.String()
+}
+
+// matchRequest compares the method, url and body, JSON bodies are compared by value
+func matchRequest(recorded, req RecordedRequest) bool {
+	if recorded.Method != req.Method || recorded.URL != req.URL {
+		return false
+	}
+	if recorded.Body == req.Body {
+		return true
+	}
+
+	var a, b interface{}
+	if json.Unmarshal([]byte(recorded.Body), &a) != nil || json.Unmarshal([]byte(req.Body), &b) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+
+	return bytes.Equal(ja, jb)
+}
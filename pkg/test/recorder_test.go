@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v2/pkg/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassettes")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cassette := filepath.Join(dir, "TestRecorder.json")
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.NotEmpty(t, r.Header.Get("Authorization"))
+		assert.Equal(t, "1-ABCDE", r.URL.Query().Get("accountSwitchKey"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"cpcodeLink":"/papi/v1/cpcodes/cpc_1?contractId=ctr_C-1234"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"accountId":"act_A-1234","contracts":{"items":[{"contractId":"ctr_C-1234"}]}}`))
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	newSession := func(rec *Recorder) session.Session {
+		sess, err := session.New(
+			session.WithClient(&http.Client{Transport: rec}),
+			session.WithSigner(&edgegrid.Config{
+				Host:         host,
+				ClientToken:  "client_token",
+				ClientSecret: "client_secret",
+				AccessToken:  "access_token",
+				AccountKey:   "1-ABCDE",
+				MaxBody:      edgegrid.MaxBodySize,
+			}),
+		)
+		require.NoError(t, err)
+		return sess
+	}
+
+	getContracts := func(sess session.Session) (map[string]interface{}, error) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/papi/v1/contracts", nil)
+		require.NoError(t, err)
+		var contracts map[string]interface{}
+		_, err = sess.Exec(req, &contracts)
+		return contracts, err
+	}
+
+	createCPCode := func(sess session.Session, body map[string]string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/papi/v1/cpcodes?contractId=ctr_C-1234", nil)
+		require.NoError(t, err)
+		var created map[string]interface{}
+		return sess.Exec(req, &created, body)
+	}
+
+	t.Run("record", func(t *testing.T) {
+		rec, err := NewRecorder(cassette, WithMode(ModeRecord), WithScrubbers(ScrubString("act_A-1234", "act_X")))
+		require.NoError(t, err)
+		sess := newSession(rec)
+
+		contracts, err := getContracts(sess)
+		require.NoError(t, err)
+		assert.Equal(t, "act_A-1234", contracts["accountId"])
+		resp, err := createCPCode(sess, map[string]string{"cpcodeName": "test", "productId": "prd_Web_App_Accel"})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.NoError(t, rec.Stop())
+		assert.Equal(t, 2, calls)
+
+		data, err := ioutil.ReadFile(cassette)
+		require.NoError(t, err)
+		for _, secret := range []string{host, "Authorization", "access_token", "client_token", "accountSwitchKey", "1-ABCDE", "session=secret", "act_A-1234"} {
+			assert.NotContains(t, string(data), secret)
+		}
+		assert.Contains(t, string(data), "act_X")
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		rec, err := NewRecorder(cassette, WithMode(ModeReplay))
+		require.NoError(t, err)
+		sess := newSession(rec)
+
+		contracts, err := getContracts(sess)
+		require.NoError(t, err)
+		assert.Equal(t, "act_X", contracts["accountId"])
+		// JSON bodies match regardless of the key order
+		resp, err := createCPCode(sess, map[string]string{"productId": "prd_Web_App_Accel", "cpcodeName": "test"})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.NoError(t, rec.Stop())
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("replay unknown request", func(t *testing.T) {
+		rec, err := NewRecorder(cassette, WithMode(ModeReplay))
+		require.NoError(t, err)
+		sess := newSession(rec)
+
+		_, err = createCPCode(sess, map[string]string{"cpcodeName": "other", "productId": "prd_Web_App_Accel"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrNoInteraction), err.Error())
+
+		err = rec.Stop()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GET /papi/v1/contracts")
+		assert.Contains(t, err.Error(), "POST /papi/v1/cpcodes?contractId=ctr_C-1234")
+	})
+
+	t.Run("missing cassette", func(t *testing.T) {
+		_, err := NewRecorder(filepath.Join(dir, "missing.json"), WithMode(ModeReplay))
+		assert.Error(t, err)
+	})
+}
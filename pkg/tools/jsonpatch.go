@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOperation is one operation of a JSON patch document, see RFC 6902
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies the operations in order to the decoded JSON document and returns the new document
+// It supports the add, remove, replace, move, copy and test operations
+func ApplyJSONPatch(doc interface{}, ops []JSONPatchOperation) (interface{}, error) {
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case "add", "replace", "test":
+			var value interface{}
+			if err = json.Unmarshal(op.Value, &value); err != nil {
+				return nil, fmt.Errorf("operation %d: value: %s", i, err)
+			}
+			if op.Op == "test" {
+				var current interface{}
+				if current, err = patchGet(doc, op.Path); err == nil && !reflect.DeepEqual(current, value) {
+					err = fmt.Errorf("the value at %s does not match", op.Path)
+				}
+				break
+			}
+			doc, err = patchSet(doc, op.Path, value, op.Op == "add")
+		case "remove":
+			doc, _, err = patchRemove(doc, op.Path)
+		case "move":
+			var value interface{}
+			if doc, value, err = patchRemove(doc, op.From); err == nil {
+				doc, err = patchSet(doc, op.Path, value, true)
+			}
+		case "copy":
+			var value interface{}
+			if value, err = patchGet(doc, op.From); err == nil {
+				doc, err = patchSet(doc, op.Path, value, true)
+			}
+		default:
+			err = fmt.Errorf("unknown operation %q", op.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %s", i, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func splitPointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("%q is not a JSON pointer", path)
+	}
+
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > length || (i == length && !allowEnd) {
+		return 0, fmt.Errorf("index %q is out of range", token)
+	}
+	return i, nil
+}
+
+func patchGet(doc interface{}, path string) (interface{}, error) {
+	tokens, err := splitPointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for _, t := range tokens {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if current, ok = v[t]; !ok {
+				return nil, fmt.Errorf("%s does not exist", path)
+			}
+		case []interface{}:
+			i, err := arrayIndex(t, len(v), false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", path, err)
+			}
+			current = v[i]
+		default:
+			return nil, fmt.Errorf("%s does not exist", path)
+		}
+	}
+
+	return current, nil
+}
+
+// patchSet adds or replaces the value at path, add inserts into arrays while replace overwrites
+func patchSet(doc interface{}, path string, value interface{}, add bool) (interface{}, error) {
+	tokens, err := splitPointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parentPath := joinPointer(tokens[:len(tokens)-1])
+	parent, err := patchGet(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	last := tokens[len(tokens)-1]
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := v[last]; !ok && !add {
+			return nil, fmt.Errorf("%s does not exist", path)
+		}
+		v[last] = value
+		return doc, nil
+	case []interface{}:
+		i, err := arrayIndex(last, len(v), add)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		if !add {
+			v[i] = value
+			return doc, nil
+		}
+		v = append(v, nil)
+		copy(v[i+1:], v[i:])
+		v[i] = value
+		// the grown array replaces the old one in its parent
+		return patchSet(doc, parentPath, v, false)
+	default:
+		return nil, fmt.Errorf("the parent of %s is not an object or an array", path)
+	}
+}
+
+func patchRemove(doc interface{}, path string) (interface{}, interface{}, error) {
+	tokens, err := splitPointer(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("the document root cannot be removed")
+	}
+	value, err := patchGet(doc, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parentPath := joinPointer(tokens[:len(tokens)-1])
+	parent, _ := patchGet(doc, parentPath)
+	last := tokens[len(tokens)-1]
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		delete(v, last)
+	case []interface{}:
+		i, _ := arrayIndex(last, len(v), false)
+		doc, err = patchSet(doc, parentPath, append(v[:i:i], v[i+1:]...), false)
+		return doc, value, err
+	}
+
+	return doc, value, nil
+}
+
+func joinPointer(tokens []string) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(escapeTokens(tokens), "/")
+}
+
+func escapeTokens(tokens []string) []string {
+	escaped := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		escaped = append(escaped, strings.NewReplacer("~", "~0", "/", "~1").Replace(t))
+	}
+	return escaped
+}
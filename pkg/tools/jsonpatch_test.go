@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	doc := `{"rules":{"name":"default","behaviors":[{"name":"origin"},{"name":"cpCode"}]}}`
+
+	tests := map[string]struct {
+		ops       string
+		expected  string
+		withError bool
+	}{
+		"add member": {
+			ops:      `[{"op":"add","path":"/comments","value":"notes"}]`,
+			expected: `{"comments":"notes","rules":{"behaviors":[{"name":"origin"},{"name":"cpCode"}],"name":"default"}}`,
+		},
+		"insert and append to array": {
+			ops:      `[{"op":"add","path":"/rules/behaviors/1","value":{"name":"caching"}},{"op":"add","path":"/rules/behaviors/-","value":{"name":"gzip"}}]`,
+			expected: `{"rules":{"behaviors":[{"name":"origin"},{"name":"caching"},{"name":"cpCode"},{"name":"gzip"}],"name":"default"}}`,
+		},
+		"replace and remove": {
+			ops:      `[{"op":"replace","path":"/rules/behaviors/0/name","value":"originCharacteristics"},{"op":"remove","path":"/rules/behaviors/1"}]`,
+			expected: `{"rules":{"behaviors":[{"name":"originCharacteristics"}],"name":"default"}}`,
+		},
+		"move and copy": {
+			ops:      `[{"op":"move","from":"/rules/behaviors/0","path":"/rules/behaviors/-"},{"op":"copy","from":"/rules/name","path":"/comments"}]`,
+			expected: `{"comments":"default","rules":{"behaviors":[{"name":"cpCode"},{"name":"origin"}],"name":"default"}}`,
+		},
+		"passing test": {
+			ops:      `[{"op":"test","path":"/rules/name","value":"default"}]`,
+			expected: doc,
+		},
+		"failing test": {
+			ops:       `[{"op":"test","path":"/rules/name","value":"other"}]`,
+			withError: true,
+		},
+		"replace missing member": {
+			ops:       `[{"op":"replace","path":"/rules/comment","value":"x"}]`,
+			withError: true,
+		},
+		"index out of range": {
+			ops:       `[{"op":"remove","path":"/rules/behaviors/2"}]`,
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var current interface{}
+			require.NoError(t, json.Unmarshal([]byte(doc), &current))
+			var ops []JSONPatchOperation
+			require.NoError(t, json.Unmarshal([]byte(test.ops), &ops))
+
+			patched, err := ApplyJSONPatch(current, ops)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			encoded, err := json.Marshal(patched)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(encoded))
+		})
+	}
+}
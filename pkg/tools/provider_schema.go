@@ -3,6 +3,7 @@ package tools
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -141,6 +142,32 @@ func GetBoolValue(key string, rd ResourceDataFetcher) (bool, error) {
 	return val, nil
 }
 
+// GetDurationValue fetches value with given key from ResourceData object and attempts to parse it as a duration like "30s"
+//
+// if value is not present on provided resource, ErrNotFound is returned
+// if casting or parsing is not successful, ErrInvalidType is returned
+func GetDurationValue(key string, rd ResourceDataFetcher) (time.Duration, error) {
+	str, err := GetStringValue(key, rd)
+	if err != nil {
+		return 0, err
+	}
+	val, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s, %q", ErrInvalidType, key, "time.Duration")
+	}
+	return val, nil
+}
+
+// PollInterval returns the poll_interval duration of a resource waiting on a change, or def when it is not set.
+// The interval is never shorter than min.
+func PollInterval(rd ResourceDataFetcher, def, min time.Duration) time.Duration {
+	interval, err := GetDurationValue("poll_interval", rd)
+	if err != nil {
+		interval = def
+	}
+	return MaxDuration(interval, min)
+}
+
 // GetSetValue fetches value with given key from ResourceData object and attempts type cast to *schema.Set
 //
 // if value is not present on provided resource, ErrNotFound is returned
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 type mocked struct {
@@ -166,6 +167,85 @@ func TestGetBoolValue(t *testing.T) {
 	}
 }
 
+func TestGetDurationValue(t *testing.T) {
+	tests := map[string]struct {
+		key       string
+		init      func(*mocked)
+		expected  time.Duration
+		withError error
+	}{
+		"duration value found": {
+			key: "key",
+			init: func(m *mocked) {
+				m.On("GetOk", "key").Return("1m30s", true).Once()
+			},
+			expected: 90 * time.Second,
+		},
+		"duration value not found": {
+			key: "key",
+			init: func(m *mocked) {
+				m.On("GetOk", "key").Return("", false).Once()
+			},
+			withError: ErrNotFound,
+		},
+		"value is not a duration": {
+			key: "key",
+			init: func(m *mocked) {
+				m.On("GetOk", "key").Return("soon", true).Once()
+			},
+			withError: ErrInvalidType,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := &mocked{}
+			test.init(m)
+			res, err := GetDurationValue(test.key, m)
+			m.AssertExpectations(t)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "want: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res)
+		})
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	tests := map[string]struct {
+		init     func(*mocked)
+		expected time.Duration
+	}{
+		"default interval": {
+			init: func(m *mocked) {
+				m.On("GetOk", "poll_interval").Return("", false).Once()
+			},
+			expected: time.Minute,
+		},
+		"configured interval": {
+			init: func(m *mocked) {
+				m.On("GetOk", "poll_interval").Return("15s", true).Once()
+			},
+			expected: 15 * time.Second,
+		},
+		"interval shorter than the minimum": {
+			init: func(m *mocked) {
+				m.On("GetOk", "poll_interval").Return("1s", true).Once()
+			},
+			expected: 10 * time.Second,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := &mocked{}
+			test.init(m)
+			assert.Equal(t, test.expected, PollInterval(m, time.Minute, 10*time.Second))
+			m.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetSetValue(t *testing.T) {
 	tests := map[string]struct {
 		key       string
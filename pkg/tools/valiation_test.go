@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/tj/assert"
 	"testing"
+	"time"
 )
 
 func TestIsBlank(t *testing.T) {
@@ -104,3 +105,42 @@ func TestValidateJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDuration(t *testing.T) {
+	tests := map[string]struct {
+		givenVal      interface{}
+		expectedError string
+	}{
+		"valid duration passed": {
+			givenVal: "2m30s",
+		},
+		"passed value is not a string": {
+			givenVal:      1,
+			expectedError: "value is not a string",
+		},
+		"invalid duration provided": {
+			givenVal:      "abc",
+			expectedError: `"abc" is not a valid positive duration`,
+		},
+		"zero duration provided": {
+			givenVal:      "0s",
+			expectedError: `"0s" is not a valid positive duration`,
+		},
+		"duration is too short": {
+			givenVal:      "5s",
+			expectedError: "duration 5s is shorter than 10s",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := ValidateDuration(10*time.Second)(test.givenVal, nil)
+			if test.expectedError != "" {
+				assert.NotEmpty(t, res)
+				assert.Contains(t, res[0].Summary, test.expectedError)
+				return
+			}
+			assert.Empty(t, res)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"reflect"
+	"time"
 )
 
 // AggregateValidations takes any number of schema.SchemaValidateDiagFunc and executes them one by one
@@ -53,3 +54,21 @@ func ValidateJSON(val interface{}, path cty.Path) diag.Diagnostics {
 	}
 	return diag.Errorf("value is not a string: %s", val)
 }
+
+// ValidateDuration checks whether given value is a positive duration like "30s" and is not shorter than min
+func ValidateDuration(min time.Duration) schema.SchemaValidateDiagFunc {
+	return func(val interface{}, path cty.Path) diag.Diagnostics {
+		str, ok := val.(string)
+		if !ok {
+			return diag.Errorf("value is not a string: %s", val)
+		}
+		d, err := time.ParseDuration(str)
+		if err != nil || d <= 0 {
+			return diag.Errorf("%q is not a valid positive duration, use values like 30s, 10m or 24h", str)
+		}
+		if d < min {
+			return diag.Errorf("duration %s is shorter than %s", d, min)
+		}
+		return nil
+	}
+}
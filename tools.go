@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/apex/log"
+
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/akamai"
+	"github.com/akamai/terraform-provider-akamai/v2/pkg/providers/property"
+)
+
+const toolsUsage = `Usage: terraform-provider-akamai tools <command> [flags]
+
+Commands:
+  export-property  write the Terraform configuration and import commands of existing properties
+`
+
+// runTools runs the command line tools built into the provider binary and returns the exit code
+func runTools(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, toolsUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "export-property":
+		return exportProperty(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], toolsUsage)
+		return 2
+	}
+}
+
+func exportProperty(args []string, stdout, stderr io.Writer) int {
+	var (
+		edgerc  string
+		section string
+		opts    property.ExportOptions
+	)
+	flags := flag.NewFlagSet("export-property", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&edgerc, "edgerc", "", "the edgerc file of the credentials, defaults to ~/.edgerc")
+	flags.StringVar(&section, "section", "default", "the section of the edgerc file")
+	flags.StringVar(&opts.ContractID, "contract", "", "the contract of the group to export all the properties of")
+	flags.StringVar(&opts.GroupID, "group", "", "the group to export all the properties of")
+	flags.StringVar(&opts.PropertyName, "name", "", "the name of the property to export")
+	flags.StringVar(&opts.Dir, "dir", ".", "the directory the configuration is written to")
+	flags.Usage = func() {
+		fmt.Fprint(stderr, "Usage: terraform-provider-akamai tools export-property (-name <property> | -contract <ctr_id> -group <grp_id>) [flags]\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	sess, err := akamai.NewToolSession(edgerc, section, log.Log)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	files, failures, err := property.ExportProperties(context.Background(), sess, opts)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	for _, file := range files {
+		fmt.Fprintln(stdout, file)
+	}
+	fmt.Fprintln(stdout, "\nRun terraform init, then sh import.sh to import the resources into the state.")
+
+	if len(failures) > 0 {
+		fmt.Fprintln(stderr)
+		for _, failure := range failures {
+			fmt.Fprintln(stderr, failure)
+		}
+		return 1
+	}
+
+	return 0
+}